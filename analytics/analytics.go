@@ -0,0 +1,281 @@
+// Package analytics computes strength metrics from performed sets: estimated
+// one rep maxes (e1RM) and personal records.
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/notation"
+)
+
+// e1RM formulas
+const (
+	EPLEY   = "epley"
+	BRZYCKI = "brzycki"
+)
+
+var Formulas = []string{EPLEY, BRZYCKI}
+
+// record kinds
+const (
+	E1RM           = "e1RM"
+	REPS           = "reps"
+	SET_VOLUME     = "set volume"
+	SESSION_VOLUME = "session volume"
+)
+
+// RepMaxes are the rep counts tracked as rep-max records, e.g. the 5RM is the
+// heaviest weight moved for at least 5 reps.
+var RepMaxes = []int{1, 3, 5, 10}
+
+// EstimateOneRepMax estimates the one rep max from reps at weight. Brzycki
+// is not defined beyond 36 reps, Epley is used there instead.
+func EstimateOneRepMax(formula string, reps int, weight float64) float64 {
+	switch {
+	case reps <= 0 || weight <= 0:
+		return 0
+	case reps == 1:
+		return weight
+	case formula == BRZYCKI && reps < 37:
+		return weight * 36 / float64(37-reps)
+	default:
+		return weight * (1 + float64(reps)/30)
+	}
+}
+
+func RepMaxKind(reps int) string {
+	return fmt.Sprintf("%vRM", reps)
+}
+
+// Record is the best value of one kind for an exercise.
+type Record struct {
+	Kind  string
+	Value float64
+	Date  time.Time
+}
+
+// Format shows the record with weights in unit, e.g. "5RM 100".
+func (r Record) Format(unit string) string {
+	value := r.Value
+	if r.Kind != REPS {
+		value = notation.FromKg(value, unit)
+	}
+	v := fmt.Sprintf("%.1f", value)
+	v = strings.TrimSuffix(v, ".0")
+	if r.Kind == REPS {
+		return fmt.Sprintf("%v %v", v, r.Kind)
+	}
+	return fmt.Sprintf("%v %v", r.Kind, v)
+}
+
+// Records holds the best record per kind.
+type Records map[string]Record
+
+func (rs Records) offer(kind string, value float64, date time.Time) {
+	if value <= 0 {
+		return
+	}
+	if r, ok := rs[kind]; !ok || value > r.Value {
+		rs[kind] = Record{Kind: kind, Value: value, Date: date}
+	}
+}
+
+// Best computes the records of sets, which are expected to be of one
+// exercise. Warm-ups are left out.
+func Best(formula string, sets []wodb.PerformedSet) Records {
+	rs := make(Records)
+	sessions := make(map[string]float64)
+	sessionDates := make(map[string]time.Time)
+
+	for _, s := range sets {
+		if s.Reps <= 0 || s.IsWarmup() {
+			continue
+		}
+
+		volume := float64(s.Reps) * s.Weight
+		rs.offer(E1RM, EstimateOneRepMax(formula, s.Reps, s.Weight), s.PerformedDate)
+		rs.offer(REPS, float64(s.Reps), s.PerformedDate)
+		rs.offer(SET_VOLUME, volume, s.PerformedDate)
+		for _, n := range RepMaxes {
+			if s.Reps >= n {
+				rs.offer(RepMaxKind(n), s.Weight, s.PerformedDate)
+			}
+		}
+
+		k := sessionKey(s)
+		sessions[k] += volume
+		sessionDates[k] = s.PerformedDate
+	}
+
+	for k, volume := range sessions {
+		rs.offer(SESSION_VOLUME, volume, sessionDates[k])
+	}
+	return rs
+}
+
+// sessionKey groups sets by session, or by day for sets logged without one.
+func sessionKey(s wodb.PerformedSet) string {
+	if s.SessionID != 0 {
+		return fmt.Sprintf("s%v", s.SessionID)
+	}
+	return s.PerformedDate.Local().Format("2006-01-02")
+}
+
+// PR is a record broken by newly logged sets.
+type PR struct {
+	ExerciseID string
+	Record     Record
+	Previous   Record
+}
+
+// NewPRs compares the records of history with those after adding logged and
+// returns the ones logged broke. Exercises without any history don't set
+// records yet, every set would be one.
+func NewPRs(formula string, history, logged []wodb.PerformedSet) []PR {
+	before := byExercise(history)
+	after := byExercise(logged)
+
+	ids := make([]string, 0, len(after))
+	for id := range after {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	prs := make([]PR, 0)
+	for _, id := range ids {
+		if len(before[id]) == 0 {
+			continue
+		}
+
+		old := Best(formula, before[id])
+		all := append(append(make([]wodb.PerformedSet, 0, len(before[id])+len(after[id])), before[id]...), after[id]...)
+		now := Best(formula, all)
+		for _, kind := range Kinds() {
+			r, ok := now[kind]
+			if ok && r.Value > old[kind].Value {
+				prs = append(prs, PR{ExerciseID: id, Record: r, Previous: old[kind]})
+			}
+		}
+	}
+	return prs
+}
+
+// Kinds lists all record kinds, strongest statement first.
+func Kinds() []string {
+	kinds := make([]string, 0, len(RepMaxes)+4)
+	for _, n := range RepMaxes {
+		kinds = append(kinds, RepMaxKind(n))
+	}
+	return append(kinds, E1RM, SET_VOLUME, SESSION_VOLUME, REPS)
+}
+
+func byExercise(sets []wodb.PerformedSet) map[string][]wodb.PerformedSet {
+	result := make(map[string][]wodb.PerformedSet)
+	for _, s := range sets {
+		result[s.ExerciseID] = append(result[s.ExerciseID], s)
+	}
+	return result
+}
+
+// Summary describes prs in one line with weights in unit, e.g. "Pullups: 5RM
+// 20, e1RM 23.3". name resolves exercise IDs to display names.
+func Summary(prs []PR, unit string, name func(exerciseID string) string) string {
+	sb := &strings.Builder{}
+	for i, pr := range prs {
+		switch {
+		case i == 0:
+			sb.WriteString(name(pr.ExerciseID) + ": ")
+		case pr.ExerciseID != prs[i-1].ExerciseID:
+			sb.WriteString("; " + name(pr.ExerciseID) + ": ")
+		default:
+			sb.WriteString(", ")
+		}
+		sb.WriteString(pr.Record.Format(unit))
+	}
+	return sb.String()
+}
+
+// SessionStat sums up the sets of one exercise in one session.
+type SessionStat struct {
+	Date      time.Time
+	Sets      int
+	TopWeight float64 // heaviest set
+	TopReps   int     // reps of the heaviest set
+	E1RM      float64 // best estimate of the session
+	Volume    float64 // reps x weight
+}
+
+// Sessions sums sets of one exercise up per session, oldest first, leaving out
+// warm-ups.
+func Sessions(formula string, sets []wodb.PerformedSet) []SessionStat {
+	byKey := make(map[string]*SessionStat)
+	stats := make([]*SessionStat, 0, 50)
+
+	for _, s := range sets {
+		if s.Reps <= 0 || s.IsWarmup() {
+			continue
+		}
+
+		k := sessionKey(s)
+		st, ok := byKey[k]
+		if !ok {
+			st = &SessionStat{Date: s.PerformedDate}
+			byKey[k] = st
+			stats = append(stats, st)
+		}
+
+		st.Sets++
+		st.Volume += float64(s.Reps) * s.Weight
+		st.E1RM = max(st.E1RM, EstimateOneRepMax(formula, s.Reps, s.Weight))
+		if s.Weight > st.TopWeight || (s.Weight == st.TopWeight && s.Reps > st.TopReps) {
+			st.TopWeight = s.Weight
+			st.TopReps = s.Reps
+		}
+		if s.PerformedDate.Before(st.Date) {
+			st.Date = s.PerformedDate
+		}
+	}
+
+	result := make([]SessionStat, len(stats))
+	for i, st := range stats {
+		result[i] = *st
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Date.Before(result[j].Date) })
+	return result
+}
+
+// GroupSessions splits sets into sessions, in the order the sessions first
+// appear in sets. The sets of a session are ordered by set number.
+func GroupSessions(sets []wodb.PerformedSet) [][]wodb.PerformedSet {
+	byKey := make(map[string]int)
+	sessions := make([][]wodb.PerformedSet, 0, 20)
+	for _, s := range sets {
+		k := sessionKey(s)
+		i, ok := byKey[k]
+		if !ok {
+			i = len(sessions)
+			byKey[k] = i
+			sessions = append(sessions, make([]wodb.PerformedSet, 0, 10))
+		}
+		sessions[i] = append(sessions[i], s)
+	}
+
+	for _, session := range sessions {
+		sort.SliceStable(session, func(i, j int) bool { return session[i].SetNo < session[j].SetNo })
+	}
+	return sessions
+}
+
+// LastSession picks the sets of the most recent session from sets given newest
+// first, ordered by set number.
+func LastSession(sets []wodb.PerformedSet) []wodb.PerformedSet {
+	sessions := GroupSessions(sets)
+	if len(sessions) == 0 {
+		return nil
+	}
+	return sessions[0]
+}
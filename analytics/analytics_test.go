@@ -0,0 +1,230 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+var day = time.Date(2026, 3, 2, 18, 0, 0, 0, time.Local)
+
+func set(session uint, reps int, weight float64) wodb.PerformedSet {
+	return wodb.PerformedSet{ExerciseID: "Barbell_Squat", SessionID: session, PerformedDate: day.AddDate(0, 0, int(session)), Reps: reps, Weight: weight}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
+
+func TestEstimateOneRepMax(t *testing.T) {
+	tests := []struct {
+		formula string
+		reps    int
+		weight  float64
+		want    float64
+	}{
+		{EPLEY, 1, 100, 100},
+		{EPLEY, 5, 100, 116.67},
+		{EPLEY, 10, 60, 80},
+		{BRZYCKI, 5, 100, 112.5},
+		{BRZYCKI, 10, 100, 133.33},
+		{BRZYCKI, 40, 10, 23.33}, // beyond Brzycki, Epley takes over
+		{EPLEY, 0, 100, 0},
+		{EPLEY, 5, 0, 0},
+		{EPLEY, 5, -10, 0},
+	}
+	for _, tt := range tests {
+		if got := EstimateOneRepMax(tt.formula, tt.reps, tt.weight); !near(got, tt.want) {
+			t.Errorf("EstimateOneRepMax(%v, %v, %v) = %v, want %v", tt.formula, tt.reps, tt.weight, got, tt.want)
+		}
+	}
+}
+
+func TestBest(t *testing.T) {
+	warmup := set(1, 10, 60)
+	warmup.Type = wodb.SET_WARMUP
+	sets := []wodb.PerformedSet{warmup, set(1, 5, 100), set(1, 5, 100), set(2, 3, 110), set(2, 8, 80)}
+
+	rs := Best(EPLEY, sets)
+	tests := []struct {
+		kind string
+		want float64
+	}{
+		{RepMaxKind(1), 110},
+		{RepMaxKind(3), 110},
+		{RepMaxKind(5), 100},
+		{E1RM, 121},
+		{REPS, 8},
+		{SET_VOLUME, 640},
+		{SESSION_VOLUME, 1000},
+	}
+	for _, tt := range tests {
+		if got := rs[tt.kind].Value; !near(got, tt.want) {
+			t.Errorf("%v = %v, want %v", tt.kind, got, tt.want)
+		}
+	}
+	if _, ok := rs[RepMaxKind(10)]; ok {
+		t.Errorf("10RM from a warm-up: %v", rs[RepMaxKind(10)])
+	}
+}
+
+func TestNewPRs(t *testing.T) {
+	history := []wodb.PerformedSet{set(1, 5, 100)}
+
+	if prs := NewPRs(EPLEY, history, []wodb.PerformedSet{set(2, 5, 90)}); len(prs) != 0 {
+		t.Errorf("lighter session broke %v", prs)
+	}
+
+	prs := NewPRs(EPLEY, history, []wodb.PerformedSet{set(2, 5, 105)})
+	kinds := make(map[string]bool)
+	for _, pr := range prs {
+		kinds[pr.Record.Kind] = true
+	}
+	for _, k := range []string{RepMaxKind(1), RepMaxKind(3), RepMaxKind(5), E1RM, SET_VOLUME, SESSION_VOLUME} {
+		if !kinds[k] {
+			t.Errorf("heavier 5 reps didn't break %v: %v", k, prs)
+		}
+	}
+	if kinds[REPS] {
+		t.Error("as many reps broke the reps record")
+	}
+
+	first := []wodb.PerformedSet{{ExerciseID: "Pullups", Reps: 10, PerformedDate: day}}
+	if prs := NewPRs(EPLEY, history, first); len(prs) != 0 {
+		t.Errorf("first session of an exercise set records %v", prs)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	prs := []PR{
+		{ExerciseID: "a", Record: Record{Kind: RepMaxKind(5), Value: 100}},
+		{ExerciseID: "a", Record: Record{Kind: E1RM, Value: 116.67}},
+		{ExerciseID: "b", Record: Record{Kind: REPS, Value: 12}},
+	}
+	got := Summary(prs, "kg", func(id string) string { return id })
+	if want := "a: 5RM 100, e1RM 116.7; b: 12 reps"; got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+	if got := (Record{Kind: RepMaxKind(5), Value: 100}).Format("lb"); got != "5RM 220.5" {
+		t.Errorf("Format in lb = %q", got)
+	}
+}
+
+func TestSessions(t *testing.T) {
+	stats := Sessions(EPLEY, []wodb.PerformedSet{set(2, 3, 110), set(1, 5, 100), set(1, 6, 100), set(2, 0, 200)})
+	if len(stats) != 2 {
+		t.Fatalf("%v sessions, want 2", len(stats))
+	}
+	if s := stats[0]; s.Sets != 2 || s.TopWeight != 100 || s.TopReps != 6 || s.Volume != 1100 {
+		t.Errorf("first session = %+v", s)
+	}
+	if s := stats[1]; s.Sets != 1 || s.TopWeight != 110 || !near(s.E1RM, 121) {
+		t.Errorf("second session = %+v", s)
+	}
+}
+
+func TestLastSession(t *testing.T) {
+	newest := []wodb.PerformedSet{set(2, 5, 100), set(2, 5, 100), set(1, 5, 90)}
+	newest[0].SetNo, newest[1].SetNo = 1, 0
+
+	last := LastSession(newest)
+	if len(last) != 2 || last[0].SetNo != 0 || last[1].SetNo != 1 {
+		t.Errorf("LastSession = %v", last)
+	}
+	if LastSession(nil) != nil {
+		t.Error("LastSession of nothing")
+	}
+}
+
+func TestRPE(t *testing.T) {
+	tests := []struct {
+		reps int
+		rpe  float64
+		want float64
+		ok   bool
+	}{
+		{1, 10, 100, true},
+		{1, 9.5, 97.8, true},
+		{2, 10, 95.5, true},
+		{5, 8, 81.1, true},
+		{12, 6, 57.4, true},
+		{13, 10, 0, false},
+		{5, 5.5, 0, false},
+		{5, 8.25, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := RPEPercent(tt.reps, tt.rpe)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("RPEPercent(%v, %v) = %v, %v, want %v, %v", tt.reps, tt.rpe, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	e1rm := EstimateOneRepMaxRPE(5, 100, 8)
+	if !near(LoadForRPE(e1rm, 5, 8), 100) {
+		t.Errorf("LoadForRPE doesn't invert EstimateOneRepMaxRPE: %v", LoadForRPE(e1rm, 5, 8))
+	}
+}
+
+func TestRecentE1RM(t *testing.T) {
+	rated := set(1, 5, 100)
+	rated.RPE = 8
+	rir := 0
+	failure := set(0, 1, 100)
+	failure.RIR = &rir
+
+	if got := RecentE1RM([]wodb.PerformedSet{set(2, 5, 120), rated, failure}); !near(got, 100*100/81.1) {
+		t.Errorf("RecentE1RM = %v, want the unrated session skipped", got)
+	}
+	if got := RecentE1RM([]wodb.PerformedSet{set(2, 5, 120)}); got != 0 {
+		t.Errorf("RecentE1RM without ratings = %v", got)
+	}
+}
+
+func TestISOWeek(t *testing.T) {
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), "2026-W01"},
+		{time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC), "2026-W53"},
+		{time.Date(2026, 2, 16, 12, 0, 0, 0, time.UTC), "2026-W08"},
+	}
+	for _, tt := range tests {
+		if got := ISOWeek(tt.t); got != tt.want {
+			t.Errorf("ISOWeek(%v) = %v, want %v", tt.t, got, tt.want)
+		}
+	}
+	if got := WeeksBack(time.Date(2026, 2, 16, 12, 0, 0, 0, time.UTC), 4); got != "2026-W05" {
+		t.Errorf("WeeksBack 4 = %v", got)
+	}
+}
+
+func TestMuscleVolumes(t *testing.T) {
+	exercises := []wodb.Exercise{
+		{ID: "row", Data: `{"primaryMuscles": ["lats"], "secondaryMuscles": ["biceps"]}`},
+		{ID: "curl", Data: `{"primaryMuscles": ["biceps"], "secondaryMuscles": []}`},
+	}
+	weekly := []wodb.WeeklyVolume{
+		{ExerciseID: "row", CalendarWeek: "2026-W08", SetCount: 4, Tonnage: 2000},
+		{ExerciseID: "curl", CalendarWeek: "2026-W08", SetCount: 3, Tonnage: 600},
+		{ExerciseID: "unknown", CalendarWeek: "2026-W08", SetCount: 9, Tonnage: 900},
+		{ExerciseID: "curl", CalendarWeek: "2026-W07", SetCount: 2, Tonnage: 400},
+	}
+
+	got := MuscleVolumes(weekly, exercises)
+	want := []MuscleVolume{
+		{"2026-W08", "biceps", 5, 1600},
+		{"2026-W08", "lats", 4, 2000},
+		{"2026-W07", "biceps", 2, 400},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("MuscleVolumes = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("MuscleVolumes[%v] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
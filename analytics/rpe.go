@@ -0,0 +1,74 @@
+package analytics
+
+import (
+	"math"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+// RPE chart bounds
+const (
+	RPE_MIN  = 6.0
+	RPE_MAX  = 10.0
+	RPE_REPS = 12
+)
+
+// rpeChart holds the share of the one rep max in percent that can be lifted
+// for a set, after Tuchscherer's RPE chart. The chart only depends on reps
+// plus reps in reserve, so it is kept as a single row indexed by both in half
+// rep steps: 1 rep at RPE 10 is 100%, 1 rep at RPE 9.5 and 2 reps at RPE 10
+// are one and two steps down.
+var rpeChart = []float64{
+	100, 97.8, 95.5, 93.9, 92.2, 90.7, 89.2, 87.8, 86.3, 85.0, 83.7,
+	82.4, 81.1, 79.9, 78.6, 77.4, 76.2, 75.1, 73.9, 72.3, 70.7,
+	69.4, 68.0, 66.7, 65.3, 64.0, 62.6, 61.3, 59.9, 58.6, 57.4,
+}
+
+// RPEPercent is the share of the one rep max in percent reps at rpe are done
+// with. ok is false outside the chart: 1 to 12 reps at RPE 6 to 10 in half
+// steps.
+func RPEPercent(reps int, rpe float64) (float64, bool) {
+	if reps < 1 || reps > RPE_REPS || rpe < RPE_MIN || rpe > RPE_MAX || math.Mod(rpe*2, 1) != 0 {
+		return 0, false
+	}
+	return rpeChart[2*(reps-1)+int((RPE_MAX-rpe)*2)], true
+}
+
+// EstimateOneRepMaxRPE estimates the one rep max from reps at weight and the
+// RPE they were done at, 0 outside the chart.
+func EstimateOneRepMaxRPE(reps int, weight, rpe float64) float64 {
+	pct, ok := RPEPercent(reps, rpe)
+	if !ok || weight <= 0 {
+		return 0
+	}
+	return weight * 100 / pct
+}
+
+// LoadForRPE is the weight reps can be done with at rpe given a one rep max,
+// 0 outside the chart.
+func LoadForRPE(oneRepMax float64, reps int, rpe float64) float64 {
+	pct, ok := RPEPercent(reps, rpe)
+	if !ok {
+		return 0
+	}
+	return oneRepMax * pct / 100
+}
+
+// RecentE1RM estimates the one rep max from the newest session of sets, given
+// newest first and of one exercise, that has working sets rated by effort.
+// The best estimate of that session counts, 0 if no session qualifies.
+func RecentE1RM(sets []wodb.PerformedSet) float64 {
+	for _, session := range GroupSessions(sets) {
+		best := 0.0
+		for _, s := range session {
+			if s.IsWarmup() {
+				continue
+			}
+			best = max(best, EstimateOneRepMaxRPE(s.Reps, s.Weight, s.Effort()))
+		}
+		if best > 0 {
+			return best
+		}
+	}
+	return 0
+}
@@ -0,0 +1,84 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+// SECONDARY_SHARE is how much a set counts for the secondary muscles of an
+// exercise. A row counts fully for the lats, half for the biceps.
+const SECONDARY_SHARE = 0.5
+
+// MuscleVolume is the work a muscle got in one ISO week.
+type MuscleVolume struct {
+	Week    string // e.g. "2026-W07"
+	Muscle  string
+	Sets    float64 // hard sets, secondaries counted by SECONDARY_SHARE
+	Tonnage float64 // reps x weight, secondaries counted by SECONDARY_SHARE
+}
+
+// ISOWeek formats t like SQLite's strftime('%G-W%V').
+func ISOWeek(t time.Time) string {
+	y, w := t.ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", y, w)
+}
+
+// WeeksBack is the ISO week n-1 weeks before the one of t, so that the weeks
+// from there to t are n.
+func WeeksBack(t time.Time, n int) string {
+	return ISOWeek(t.AddDate(0, 0, -7*(max(n, 1)-1)))
+}
+
+// MuscleVolumes spreads the weekly volume of exercises over their primary and
+// secondary muscles. The result is sorted by week, newest first, then by sets.
+func MuscleVolumes(weekly []wodb.WeeklyVolume, exercises []wodb.Exercise) []MuscleVolume {
+	byID := make(map[string]wodb.Exercise, len(exercises))
+	for _, e := range exercises {
+		byID[e.ID] = e
+	}
+
+	type key struct{ week, muscle string }
+	volumes := make(map[key]*MuscleVolume)
+	add := func(week, muscle string, share float64, v wodb.WeeklyVolume) {
+		k := key{week, muscle}
+		mv, ok := volumes[k]
+		if !ok {
+			mv = &MuscleVolume{Week: week, Muscle: muscle}
+			volumes[k] = mv
+		}
+		mv.Sets += share * float64(v.SetCount)
+		mv.Tonnage += share * v.Tonnage
+	}
+
+	for _, v := range weekly {
+		e, ok := byID[v.ExerciseID]
+		if !ok {
+			continue
+		}
+		for _, m := range e.GetPrimaryMuscles() {
+			add(v.CalendarWeek, m, 1, v)
+		}
+		for _, m := range e.GetSecondaryMuscles() {
+			add(v.CalendarWeek, m, SECONDARY_SHARE, v)
+		}
+	}
+
+	result := make([]MuscleVolume, 0, len(volumes))
+	for _, mv := range volumes {
+		result = append(result, *mv)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Week != b.Week {
+			return a.Week > b.Week
+		}
+		if a.Sets != b.Sets {
+			return a.Sets > b.Sets
+		}
+		return a.Muscle < b.Muscle
+	})
+	return result
+}
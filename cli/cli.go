@@ -0,0 +1,104 @@
+// Package cli implements the headless subcommands of clift. They work on the
+// same store as the TUI and are meant for scripts, shell aliases and the like.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/zmnpl/clift/config"
+	wodb "github.com/zmnpl/clift/db"
+)
+
+// output goes here; handy to redirect when embedding
+var stdout io.Writer = os.Stdout
+var stderr io.Writer = os.Stderr
+var stdin io.Reader = os.Stdin
+
+// settings of the current run
+var cfg = config.Default()
+
+type command struct {
+	name  string
+	args  string
+	short string
+	run   func(store wodb.Store, args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"log", "[-date YYYY-MM-DD] [-workout ID] [-notes TEXT] [-bodyweight WEIGHT] <exercise> <sets, e.g. 5x5@100>", "log sets of an exercise", runLog},
+		{"workouts list", "", "list workout templates", runWorkoutsList},
+		{"journal", "[-n N] [-exercise EXERCISE]", "show logged sets, newest first", runJournal},
+		{"export", "[-format json|csv] [-o PATH] [-from YYYY-MM-DD] [-to YYYY-MM-DD] [-exercise A,B]", "export history, templates and custom exercises", runExport},
+		{"import", "[-format F] [-unit kg|lb] [-yes] <FILE>", "import from clift JSON or Strong, Hevy, FitNotes CSV", runImport},
+		{"volume", "[-weeks N] [-format table|csv] [-o PATH]", "sets and tonnage per muscle and week", runVolume},
+		{"exercises search", "<QUERY>", "find exercises by id, name or muscle", runExercisesSearch},
+	}
+}
+
+// Run executes the subcommand given by args (without the program name). The
+// store is only opened once args name a command, help and typos leave the
+// database alone.
+func Run(open func() wodb.Store, c config.Config, args []string) error {
+	cfg = c
+
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stdout)
+		return nil
+	}
+
+	// longest match first, so "workouts list" beats a plain "workouts"
+	var match *command
+	for i := range commands {
+		words := strings.Fields(commands[i].name)
+		if len(args) < len(words) || strings.Join(args[:len(words)], " ") != commands[i].name {
+			continue
+		}
+		if match == nil || len(words) > len(strings.Fields(match.name)) {
+			match = &commands[i]
+		}
+	}
+
+	if match == nil {
+		usage(stderr)
+		return fmt.Errorf("unknown command: %v", strings.Join(args, " "))
+	}
+
+	err := match.run(open(), args[len(strings.Fields(match.name)):])
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	return err
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: clift [command]")
+	fmt.Fprintln(w, "\nwithout a command the interactive UI starts\n\ncommands:")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(tw, "  %v %v\t%v\n", c.name, c.args, c.short)
+	}
+	tw.Flush()
+}
+
+func newFlagSet(c string) *flag.FlagSet {
+	fs := flag.NewFlagSet("clift "+c, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+func newTable() *tabwriter.Writer {
+	return newTableTo(stdout)
+}
+
+func newTableTo(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+}
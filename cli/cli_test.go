@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zmnpl/clift/config"
+	wodb "github.com/zmnpl/clift/db"
+)
+
+// run executes args against store with stdin fed from input and returns what
+// went to stdout and stderr.
+func run(t *testing.T, store wodb.Store, input string, args ...string) (string, string, error) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	stdout, stderr, stdin = &out, &errOut, strings.NewReader(input)
+	t.Cleanup(func() { stdout, stderr, stdin = os.Stdout, os.Stderr, os.Stdin })
+
+	err := Run(func() wodb.Store { return store }, config.Default(), args)
+	return out.String(), errOut.String(), err
+}
+
+func TestRunHelp(t *testing.T) {
+	opened := false
+	open := func() wodb.Store {
+		opened = true
+		return nil
+	}
+	var out bytes.Buffer
+	stdout = &out
+	t.Cleanup(func() { stdout = os.Stdout })
+
+	if err := Run(open, config.Default(), []string{"help"}); err != nil || !strings.Contains(out.String(), "usage: clift") {
+		t.Errorf("help = %q, %v", out.String(), err)
+	}
+	if _, errOut, err := run(t, nil, "", "lgo", "squat"); err == nil || !strings.Contains(errOut, "usage: clift") {
+		t.Errorf("unknown command = %q, %v", errOut, err)
+	}
+	if opened {
+		t.Error("help opened the store")
+	}
+}
+
+func TestRunLogAndJournal(t *testing.T) {
+	store := wodb.NewInMemoryTrainingDB()
+
+	out, _, err := run(t, store, "", "log", "-date", "2025-01-06", "-notes", "heavy", "barbell squat", "3x5@100", "1x3@110")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, "logged 4 sets of Barbell Squat\n") {
+		t.Errorf("log = %q", out)
+	}
+
+	out, _, err = run(t, store, "", "log", "-date", "2025-01-08", "Barbell_Squat", "5@110")
+	if err != nil || !strings.Contains(out, "new PR!") {
+		t.Errorf("log a PR = %q, %v", out, err)
+	}
+
+	if _, _, err := run(t, store, "", "log", "Barbell_Squat", "0@100"); err == nil {
+		t.Error("logged nothing without complaint")
+	}
+	if _, _, err := run(t, store, "", "log", "Squash", "5@100"); err == nil {
+		t.Error("logged an unknown exercise")
+	}
+
+	out, _, err = run(t, store, "", "journal", "-n", "3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "DATE") {
+		t.Fatalf("journal = %q", out)
+	}
+	if !strings.HasPrefix(lines[1], "2025-01-08") || !strings.Contains(lines[1], "Barbell Squat") {
+		t.Errorf("newest journal line = %q", lines[1])
+	}
+}
+
+func TestRunImport(t *testing.T) {
+	store := wodb.NewInMemoryTrainingDB()
+	file := filepath.Join(t.TempDir(), "strong.csv")
+	csv := `Date,Workout Name,Duration,Exercise Name,Set Order,Weight,Reps,Distance,Seconds,Notes,Workout Notes,RPE
+2025-01-06 18:00:00,Legs,1h,Squat (Barbell),1,100,5,0,0,,,
+2025-01-06 18:00:00,Legs,1h,Zercher Squat Thing,1,60,8,0,0,,,
+`
+	if err := os.WriteFile(file, []byte(csv), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the unknown name is mapped to the first candidate by pressing enter
+	out, _, err := run(t, store, "\n", "import", "-unit", "kg", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `"Zercher Squat Thing"`) || !strings.Contains(out, "imported 2 sets from strong") {
+		t.Errorf("import = %q", out)
+	}
+	if aliases, _ := store.GetExerciseAliases(); aliases["zercher squat thing"] == "" {
+		t.Errorf("confirmed match not remembered: %v", aliases)
+	}
+
+	out, _, err = run(t, store, "", "import", file)
+	if err != nil || !strings.Contains(out, "imported 0 sets") || !strings.Contains(out, "skipped 2 already imported") {
+		t.Errorf("import again = %q, %v", out, err)
+	}
+}
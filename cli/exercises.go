@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+func runExercisesSearch(store wodb.Store, args []string) error {
+	fs := newFlagSet("exercises search")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("missing search query")
+	}
+
+	exercises, err := store.GetAllExercises()
+	if err != nil {
+		return err
+	}
+
+	query := strings.ToLower(strings.Join(fs.Args(), " "))
+	tw := newTable()
+	for _, e := range exercises {
+		haystack := strings.ToLower(e.ID + " " + e.GetName() + " " + strings.Join(e.GetPrimaryMuscles(), " ") + " " + strings.Join(e.GetSecondaryMuscles(), " "))
+		if !strings.Contains(haystack, query) {
+			continue
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\n", e.ID, e.GetName(), strings.Join(e.GetPrimaryMuscles(), ", "))
+	}
+	return tw.Flush()
+}
+
+// findExercise resolves an exercise by its ID or its display name, both
+// case-insensitive.
+func findExercise(store wodb.Store, query string) (wodb.Exercise, error) {
+	exercises, err := store.GetAllExercises()
+	if err != nil {
+		return wodb.Exercise{}, err
+	}
+
+	if e, ok := wodb.FindExercise(exercises, query); ok {
+		return e, nil
+	}
+
+	candidates := make([]string, 0, 5)
+	for _, e := range exercises {
+		if len(candidates) == cap(candidates) {
+			break
+		}
+		if strings.Contains(strings.ToLower(e.GetName()), strings.ToLower(query)) {
+			candidates = append(candidates, e.ID)
+		}
+	}
+	if len(candidates) > 0 {
+		return wodb.Exercise{}, fmt.Errorf("unknown exercise %q, did you mean: %v", query, strings.Join(candidates, ", "))
+	}
+	return wodb.Exercise{}, fmt.Errorf("unknown exercise %q", query)
+}
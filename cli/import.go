@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/importer"
+)
+
+// candidates below this score are not taken without asking
+const autoAcceptScore = 0.5
+
+func runImport(store wodb.Store, args []string) error {
+	fs := newFlagSet("import")
+	format := fs.String("format", "", "one of "+strings.Join(importer.Formats, ", ")+"; detected if empty")
+	unit := fs.String("unit", cfg.Units, "unit of the weights if the file doesn't name it")
+	yes := fs.Bool("yes", false, "don't ask, take the best match for unknown exercises without remembering it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("need exactly one file to import")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	src, err := importer.Read(f, *format)
+	if err != nil {
+		return fmt.Errorf("reading %v: %v", fs.Arg(0), err)
+	}
+
+	matcher, err := importer.NewMatcher(store)
+	if err != nil {
+		return err
+	}
+	mapping, unknown := matcher.Mapping(src)
+
+	if len(unknown) > 0 {
+		fmt.Fprintf(stdout, "%v exercise names need a match\n", len(unknown))
+	}
+	in := bufio.NewScanner(stdin)
+	for _, name := range unknown {
+		id, ok := review(in, matcher, name, *yes)
+		if !ok {
+			continue
+		}
+		mapping[strings.ToLower(name)] = id
+		// only matches the user confirmed are remembered for the next import
+		if *yes {
+			continue
+		}
+		if err := store.SaveExerciseAlias(name, id); err != nil {
+			return err
+		}
+	}
+
+	result, err := importer.Apply(store, src, mapping, *unit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "imported %v sets from %v; skipped %v already imported, %v of unmatched exercises, %v rows without a set\n",
+		result.Imported, src.Format, result.Duplicates, result.Unmapped, src.Skipped)
+	return nil
+}
+
+// review asks which exercise name stands for. With auto set, the best
+// candidate is taken if it is close enough.
+func review(in *bufio.Scanner, matcher *importer.Matcher, name string, auto bool) (string, bool) {
+	candidates := matcher.Candidates(name, 5)
+
+	if auto {
+		if len(candidates) > 0 && candidates[0].Score >= autoAcceptScore {
+			fmt.Fprintf(stdout, "%q -> %v\n", name, candidates[0].Name)
+			return candidates[0].ID, true
+		}
+		fmt.Fprintf(stdout, "%q skipped, no close match\n", name)
+		return "", false
+	}
+
+	fmt.Fprintf(stdout, "\n%q\n", name)
+	for i, c := range candidates {
+		fmt.Fprintf(stdout, "  %v) %v (%.0f%%)\n", i+1, c.Name, c.Score*100)
+	}
+
+	for {
+		fmt.Fprint(stdout, "pick a number, type an exercise id or s to skip [1]: ")
+		if !in.Scan() {
+			return "", false
+		}
+		answer := strings.TrimSpace(in.Text())
+
+		switch {
+		case answer == "" && len(candidates) > 0:
+			return candidates[0].ID, true
+		case answer == "s":
+			return "", false
+		}
+
+		if i, err := strconv.Atoi(answer); err == nil && i >= 1 && i <= len(candidates) {
+			return candidates[i-1].ID, true
+		}
+		if id, ok := matcher.Match(answer); ok {
+			return id, true
+		}
+		fmt.Fprintf(stdout, "no exercise %q\n", answer)
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/notation"
+)
+
+func runJournal(store wodb.Store, args []string) error {
+	fs := newFlagSet("journal")
+	limit := fs.Int("n", 50, "number of sets to show, 0 for all")
+	exerciseQuery := fs.String("exercise", "", "only show this exercise")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	filter := wodb.SetFilter{}
+	if *exerciseQuery != "" {
+		e, err := findExercise(store, *exerciseQuery)
+		if err != nil {
+			return err
+		}
+		filter.ExerciseIDs = []string{e.ID}
+	}
+
+	performedSets, err := store.GetPerformedSets(filter, *limit, 0)
+	if err != nil {
+		return err
+	}
+	exercises, err := store.GetAllExercises()
+	if err != nil {
+		return err
+	}
+	names := make(map[string]string, len(exercises))
+	for _, e := range exercises {
+		names[e.ID] = e.GetName()
+	}
+
+	tw := newTable()
+	fmt.Fprintln(tw, "DATE\tEXERCISE\tSET\tTYPE\tREPS\tWEIGHT\tEFFORT\tTIME\tKM\tPACE")
+	for _, s := range performedSets {
+		duration, distance, pace := "", "", ""
+		if s.Duration > 0 {
+			duration = notation.FormatDuration(s.Duration)
+		}
+		if s.Distance > 0 {
+			distance = strconv.FormatFloat(s.Distance, 'f', -1, 64)
+		}
+		if s.Pace() > 0 {
+			pace = notation.FormatPace(s.Pace())
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\t%v\n", s.PerformedDate.Format("2006-01-02"), names[s.ExerciseID], s.SetNo+1, s.Type, s.Reps, notation.FromKg(s.Weight, cfg.Units), notation.FormatEffort(s.RPE, s.RIR), duration, distance, pace)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/zmnpl/clift/analytics"
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/notation"
+)
+
+func runLog(store wodb.Store, args []string) error {
+	fs := newFlagSet("log")
+	date := fs.String("date", "", "date of the sets (YYYY-MM-DD), defaults to now")
+	workoutID := fs.Uint("workout", 0, "id of the workout the sets belong to")
+	notes := fs.String("notes", "", "notes on the session")
+	bodyweight := fs.Float64("bodyweight", 0, "bodyweight on that day, in the configured unit")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 2 {
+		return fmt.Errorf("need an exercise and at least one set")
+	}
+
+	datum, err := parseDate(*date)
+	if err != nil {
+		return err
+	}
+
+	exercise, err := findExercise(store, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	sets, err := notation.ParsePerformed(strings.Join(fs.Args()[1:], " "), cfg.Units, *workoutID, exercise.ID, datum)
+	if err != nil {
+		return err
+	}
+	// empty sets are not logged
+	sets = slices.DeleteFunc(sets, wodb.PerformedSet.IsEmpty)
+	if len(sets) == 0 {
+		return fmt.Errorf("no sets to log")
+	}
+
+	history, err := store.GetPerformedSets(wodb.SetFilter{ExerciseIDs: []string{exercise.ID}}, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	session := &wodb.Session{
+		WorkoutID:  *workoutID,
+		StartedAt:  datum,
+		EndedAt:    datum,
+		Notes:      *notes,
+		Bodyweight: notation.ToKg(*bodyweight, cfg.Units),
+	}
+	if err := store.LogSession(session, sets); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "logged %v sets of %v\n", len(sets), exercise.GetName())
+
+	for i := range sets {
+		sets[i].SessionID = session.ID
+	}
+	if prs := analytics.NewPRs(cfg.E1RMFormula, history, sets); len(prs) > 0 {
+		fmt.Fprintf(stdout, "new PR! %v\n", analytics.Summary(prs, cfg.Units, func(string) string { return exercise.GetName() }))
+	}
+	return nil
+}
+
+// parseDate turns YYYY-MM-DD into a local date. Empty means now.
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Now(), nil
+	}
+	d, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return d, fmt.Errorf("invalid date %q, want YYYY-MM-DD", s)
+	}
+	return d, nil
+}
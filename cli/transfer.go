@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/export"
+)
+
+func runExport(store wodb.Store, args []string) error {
+	fs := newFlagSet("export")
+	format := fs.String("format", export.JSON, "json or csv")
+	output := fs.String("o", "", "file (json) or folder (csv) to write to; json defaults to stdout")
+	from := fs.String("from", "", "first day to export (YYYY-MM-DD)")
+	to := fs.String("to", "", "last day to export (YYYY-MM-DD)")
+	exerciseList := fs.String("exercise", "", "comma separated exercises to export, by id or name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	filter := wodb.SetFilter{}
+	var err error
+	if *from != "" {
+		if filter.From, err = parseDate(*from); err != nil {
+			return err
+		}
+	}
+	if *to != "" {
+		if filter.To, err = parseDate(*to); err != nil {
+			return err
+		}
+	}
+	if *exerciseList != "" {
+		for _, q := range strings.Split(*exerciseList, ",") {
+			e, err := findExercise(store, strings.TrimSpace(q))
+			if err != nil {
+				return err
+			}
+			filter.ExerciseIDs = append(filter.ExerciseIDs, e.ID)
+		}
+	}
+
+	doc, err := export.Build(store, filter)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		if *format != export.JSON {
+			return fmt.Errorf("%v export needs a folder (-o)", *format)
+		}
+		return export.WriteJSON(stdout, doc)
+	}
+
+	if err := export.Save(doc, *format, *output); err != nil {
+		return err
+	}
+	fmt.Fprintf(stderr, "exported %v sets and %v workouts to %v\n", len(doc.PerformedSets), len(doc.Workouts), *output)
+	return nil
+}
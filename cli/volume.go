@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/zmnpl/clift/analytics"
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/notation"
+)
+
+func runVolume(store wodb.Store, args []string) error {
+	fs := newFlagSet("volume")
+	weeks := fs.Int("weeks", 8, "number of weeks to report, 0 for all")
+	format := fs.String("format", "table", "table or csv")
+	output := fs.String("o", "", "file to write to, defaults to stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "table" && *format != "csv" {
+		return fmt.Errorf("unknown format %q, want table or csv", *format)
+	}
+
+	fromWeek := ""
+	if *weeks > 0 {
+		fromWeek = analytics.WeeksBack(time.Now(), *weeks)
+	}
+	weekly, err := store.GetWeeklyVolume(fromWeek)
+	if err != nil {
+		return err
+	}
+	exercises, err := store.GetAllExercises()
+	if err != nil {
+		return err
+	}
+	volumes := analytics.MuscleVolumes(weekly, exercises)
+
+	var w io.Writer = stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *format == "csv" {
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"week", "muscle", "sets", "tonnage"})
+		for _, v := range volumes {
+			cw.Write([]string{v.Week, v.Muscle, strconv.FormatFloat(v.Sets, 'f', -1, 64), strconv.FormatFloat(notation.FromKg(v.Tonnage, cfg.Units), 'f', 2, 64)})
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	tw := newTableTo(w)
+	fmt.Fprintln(tw, "WEEK\tMUSCLE\tSETS\tTONNAGE")
+	for _, v := range volumes {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%.0f\n", v.Week, v.Muscle, v.Sets, notation.FromKg(v.Tonnage, cfg.Units))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+func runWorkoutsList(store wodb.Store, args []string) error {
+	fs := newFlagSet("workouts list")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	workouts, err := store.GetAllWorkouts()
+	if err != nil {
+		return err
+	}
+
+	tw := newTable()
+	for _, w := range workouts {
+		names := make([]string, len(w.WorkoutExercises))
+		for i, we := range w.WorkoutExercises {
+			names[i] = we.Exercise.GetName()
+		}
+		fmt.Fprintf(tw, "%v\t%v\t%v\n", w.ID, w.Name, strings.Join(names, ", "))
+	}
+	return tw.Flush()
+}
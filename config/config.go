@@ -0,0 +1,173 @@
+// Package config loads the user settings of clift.
+//
+// Settings are read from $XDG_CONFIG_HOME/clift/config.toml (falling back to
+// ~/.config/clift/config.toml). The database location can additionally be
+// overridden by the CLIFT_DB environment variable and the --db flag, in that
+// order of precedence.
+//
+// Example config.toml:
+//
+//	db = "~/Documents/training.db"
+//	units = "kg"                 # kg or lb, for showing and entering weights
+//	theme = "hachikoo"           # hachikoo, blackmetal or terafox
+//	default_set_count = 3        # sets offered when logging a single exercise
+//	default_reps = 10            # reps placeholder of new sets
+//	e1rm_formula = "epley"       # epley or brzycki, for estimated one rep maxes
+//	prefill_last = false         # placeholders from the last session instead of the plan
+//	default_rest = 90            # seconds of rest between sets in a workout, 0 no timer
+//	bar_weight = 20              # of barbell exercises, in units; 20 kg or 45 lb if unset
+//	plates = "20x4 10x2 5x2"     # plates at hand by size and count, see package plates
+//	warmup = "barx10 40x5 60x3"  # warm-up ramp in percent of the working weight x reps
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/zmnpl/clift/analytics"
+	"github.com/zmnpl/clift/notation"
+	"github.com/zmnpl/clift/plates"
+	"github.com/zmnpl/clift/progression"
+)
+
+const (
+	ENV_DB    = "CLIFT_DB"
+	FILE_NAME = "config.toml"
+)
+
+type Config struct {
+	DB              string  `toml:"db"`
+	Units           string  `toml:"units"`
+	Theme           string  `toml:"theme"`
+	DefaultSetCount int     `toml:"default_set_count"`
+	DefaultReps     int     `toml:"default_reps"`
+	E1RMFormula     string  `toml:"e1rm_formula"`
+	PrefillLast     bool    `toml:"prefill_last"`
+	DefaultRest     int     `toml:"default_rest"`
+	BarWeight       float64 `toml:"bar_weight"`
+	Plates          string  `toml:"plates"`
+	Warmup          string  `toml:"warmup"`
+}
+
+var Themes = []string{"hachikoo", "blackmetal", "terafox"}
+
+func Default() Config {
+	return Config{
+		DB:              filepath.Join("~", "Documents", "training.db"),
+		Units:           notation.KG,
+		Theme:           "hachikoo",
+		DefaultSetCount: 3,
+		DefaultReps:     10,
+		E1RMFormula:     analytics.EPLEY,
+		DefaultRest:     90,
+		Warmup:          progression.DEFAULT_RAMP,
+	}
+}
+
+// Dir is the folder holding the config file.
+func Dir() (string, error) {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, "clift"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "clift"), nil
+}
+
+// Load reads the config file if there is one and applies the environment on
+// top. A missing file is not an error, the defaults are used then.
+func Load() (Config, error) {
+	c := Default()
+
+	dir, err := Dir()
+	if err != nil {
+		return c, err
+	}
+
+	_, err = toml.DecodeFile(filepath.Join(dir, FILE_NAME), &c)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return c, fmt.Errorf("reading config: %v", err)
+	}
+
+	if db := os.Getenv(ENV_DB); db != "" {
+		c.DB = db
+	}
+
+	return c, c.Validate()
+}
+
+// DBPath is the database location with a leading ~ expanded, see ExpandHome.
+func (c Config) DBPath() (string, error) {
+	return ExpandHome(c.DB)
+}
+
+// ExpandHome replaces a leading ~ or ~/ of path with the home of the user.
+// Other users' homes like ~user/ are not looked up.
+func ExpandHome(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~")
+	if !ok || (rest != "" && rest[0] != '/' && rest[0] != filepath.Separator) {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot find user home: %v", err)
+	}
+	return filepath.Join(home, rest), nil
+}
+
+// PlateInventory is the bar and the plates to load it with, the ones of a
+// commercial gym in the units of c where not set.
+func (c Config) PlateInventory() (plates.Inventory, error) {
+	bar, spec := plates.Defaults(c.Units)
+	if c.BarWeight > 0 {
+		bar = c.BarWeight
+	}
+	if c.Plates != "" {
+		spec = c.Plates
+	}
+	return plates.Parse(bar, spec)
+}
+
+func (c Config) Validate() error {
+	if c.DB == "" {
+		return fmt.Errorf("config: db must not be empty")
+	}
+	if !slices.Contains(notation.Units, c.Units) {
+		return fmt.Errorf("config: units must be one of %v, got %q", strings.Join(notation.Units, ", "), c.Units)
+	}
+	if !slices.Contains(Themes, c.Theme) {
+		return fmt.Errorf("config: theme must be one of %v, got %q", strings.Join(Themes, ", "), c.Theme)
+	}
+	if c.DefaultSetCount < 1 {
+		return fmt.Errorf("config: default_set_count must be at least 1")
+	}
+	if c.DefaultReps < 1 {
+		return fmt.Errorf("config: default_reps must be at least 1")
+	}
+	if !slices.Contains(analytics.Formulas, c.E1RMFormula) {
+		return fmt.Errorf("config: e1rm_formula must be one of %v, got %q", strings.Join(analytics.Formulas, ", "), c.E1RMFormula)
+	}
+	if c.DefaultRest < 0 {
+		return fmt.Errorf("config: default_rest must not be negative")
+	}
+	if c.BarWeight < 0 {
+		return fmt.Errorf("config: bar_weight must not be negative")
+	}
+	if _, err := c.PlateInventory(); err != nil {
+		return fmt.Errorf("config: %v", err)
+	}
+	if _, err := progression.ParseRamp(c.Warmup); err != nil {
+		return fmt.Errorf("config: %v", err)
+	}
+	return nil
+}
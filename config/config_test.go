@@ -0,0 +1,84 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	tests := []struct{ path, want string }{
+		{"~", home},
+		{"~/Documents/training.db", filepath.Join(home, "Documents", "training.db")},
+		{"~user/training.db", "~user/training.db"},
+		{"/var/lib/clift.db", "/var/lib/clift.db"},
+		{"training.db", "training.db"},
+	}
+	for _, tt := range tests {
+		got, err := ExpandHome(tt.path)
+		if err != nil || got != tt.want {
+			t.Errorf("ExpandHome(%q) = %q, %v, want %q", tt.path, got, err, tt.want)
+		}
+	}
+
+	if got, err := (Config{DB: "~/training.db"}).DBPath(); err != nil || got != filepath.Join(home, "training.db") {
+		t.Errorf("DBPath = %q, %v", got, err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("defaults are invalid: %v", err)
+	}
+
+	invalid := map[string]func(*Config){
+		"db":                func(c *Config) { c.DB = "" },
+		"units":             func(c *Config) { c.Units = "stone" },
+		"theme":             func(c *Config) { c.Theme = "neon" },
+		"default_set_count": func(c *Config) { c.DefaultSetCount = 0 },
+		"default_reps":      func(c *Config) { c.DefaultReps = 0 },
+		"e1rm_formula":      func(c *Config) { c.E1RMFormula = "guess" },
+		"default_rest":      func(c *Config) { c.DefaultRest = -1 },
+		"bar_weight":        func(c *Config) { c.BarWeight = -20 },
+		"plates":            func(c *Config) { c.Plates = "20x1" },
+		"warmup":            func(c *Config) { c.Warmup = "120x1" },
+	}
+	for key, change := range invalid {
+		c := Default()
+		change(&c)
+		if err := c.Validate(); err == nil {
+			t.Errorf("invalid %v accepted", key)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv(ENV_DB, "")
+
+	c, err := Load()
+	if err != nil || c != Default() {
+		t.Fatalf("Load without a file = %+v, %v, want the defaults", c, err)
+	}
+
+	os.MkdirAll(filepath.Join(dir, "clift"), 0755)
+	err = os.WriteFile(filepath.Join(dir, "clift", FILE_NAME), []byte("units = \"lb\"\ndb = \"/tmp/a.db\"\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ENV_DB, "/tmp/b.db")
+
+	c, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Units != "lb" || c.DB != "/tmp/b.db" {
+		t.Errorf("Load = %+v, want units from the file and db from %v", c, ENV_DB)
+	}
+}
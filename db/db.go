@@ -1,7 +1,10 @@
 package db
 
 import (
-	"context"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/tidwall/gjson"
@@ -39,6 +42,18 @@ type WorkoutExercise struct {
 	Sets    []Set `gorm:"constraint:OnDelete:CASCADE"`
 	Note    string
 	Deleted gorm.DeletedAt
+
+	// progression rule, see package progression
+	Progression   string    `gorm:"default:null"`
+	Increment     float64   `gorm:"default:null"`
+	RepsMin       int       `gorm:"default:null"`
+	RepsMax       int       `gorm:"default:null"`
+	TrainingMax   float64   `gorm:"default:null"`
+	DeloadAfter   int       `gorm:"default:null"` // failed sessions in a row, 0 never
+	DeloadPercent float64   `gorm:"default:null"`
+	ProgressedAt  time.Time `gorm:"default:null"` // newest session the targets were updated from
+
+	Rest int `gorm:"default:null"` // seconds between sets, 0 uses the default
 }
 
 type Set struct {
@@ -46,8 +61,24 @@ type Set struct {
 	WorkoutExerciseID uint    `gorm:"not null"`
 	Reps              int     `gorm:"not null"`
 	Weight            float64 `gorm:"not null"`
+	Duration          int     `gorm:"default:null"` // seconds
+	Distance          float64 `gorm:"default:null"` // kilometres
+	Type              string  `gorm:"default:null"` // see SetTypes
+	TargetRPE         float64 `gorm:"default:null"`
 }
 
+// set types, working sets have none
+const (
+	SET_WORKING = ""
+	SET_WARMUP  = "warmup"
+	SET_DROP    = "drop"
+	SET_AMRAP   = "amrap"
+	SET_FAILURE = "failure"
+)
+
+// SetTypes in the order they are toggled through.
+var SetTypes = []string{SET_WORKING, SET_WARMUP, SET_DROP, SET_AMRAP, SET_FAILURE}
+
 type PerformedSet struct {
 	ID            uint `gorm:"primaryKey;not null"`
 	WorkoutID     uint `gorm:"default:null"`
@@ -55,7 +86,106 @@ type PerformedSet struct {
 	PerformedDate time.Time
 	SetNo         int
 	Reps          int
-	Weight        float64
+	Weight        float64 // kilograms, like all weights
+	Unit          string  `gorm:"default:null"` // kg or lb, as the weight was entered
+	ImportKey     string  `gorm:"default:null"` // set for sets imported from other apps
+	SessionID     uint    `gorm:"default:null"`
+	Type          string  `gorm:"default:null"` // see SetTypes
+
+	// effort, either of them or none
+	RPE float64 `gorm:"default:null"` // 6 to 10 in half steps
+	RIR *int    `gorm:"default:null"` // reps in reserve, 0 is to failure
+
+	// cardio and timed sets
+	Duration  int     `gorm:"default:null"` // seconds
+	Distance  float64 `gorm:"default:null"` // kilometres
+	HeartRate int     `gorm:"default:null"` // average bpm
+	Calories  int     `gorm:"default:null"`
+}
+
+// IsEmpty reports whether nothing was done in the set: neither reps nor time
+// nor distance.
+func (s PerformedSet) IsEmpty() bool {
+	return s.Reps <= 0 && s.Duration <= 0 && s.Distance <= 0
+}
+
+// IsWarmup reports whether the set only prepared the working sets. Warm-ups
+// don't count for volume and records.
+func (s PerformedSet) IsWarmup() bool {
+	return s.Type == SET_WARMUP
+}
+
+// Effort is the RPE of the set, taken from the reps in reserve if only those
+// were given. 0 is unknown.
+func (s PerformedSet) Effort() float64 {
+	if s.RPE > 0 {
+		return s.RPE
+	}
+	if s.RIR != nil {
+		return max(0, 10-float64(*s.RIR))
+	}
+	return 0
+}
+
+// Pace is the time per kilometre, 0 without duration or distance.
+func (s PerformedSet) Pace() time.Duration {
+	if s.Duration <= 0 || s.Distance <= 0 {
+		return 0
+	}
+	return time.Duration(float64(s.Duration) / s.Distance * float64(time.Second))
+}
+
+// Speed is the average speed in km/h, 0 without duration or distance.
+func (s PerformedSet) Speed() float64 {
+	if s.Duration <= 0 || s.Distance <= 0 {
+		return 0
+	}
+	return s.Distance / (float64(s.Duration) / 3600)
+}
+
+// Session is one training: the sets performed together, when and how it went.
+type Session struct {
+	ID            uint `gorm:"primaryKey;not null"`
+	WorkoutID     uint `gorm:"default:null"` // template the session came from, if any
+	StartedAt     time.Time
+	EndedAt       time.Time
+	Notes         string
+	Bodyweight    float64
+	ProgramDayID  uint           `gorm:"default:null"` // program day the session was scheduled by, if any
+	PerformedSets []PerformedSet `gorm:"foreignKey:SessionID"`
+}
+
+// Program schedules workouts over weeks. Week and Day are the position of the
+// next training, both counted from 0.
+type Program struct {
+	ID     uint   `gorm:"primaryKey;not null"`
+	Name   string `gorm:"not null"`
+	Week   int
+	Day    int
+	Active bool
+	Weeks  []ProgramWeek `gorm:"foreignKey:ProgramID;constraint:OnDelete:CASCADE"`
+}
+
+type ProgramWeek struct {
+	ID        uint `gorm:"primaryKey;not null"`
+	ProgramID uint `gorm:"not null"`
+	WeekNo    int  `gorm:"not null"`
+	Name      string
+	Scheme    string       // percentages of the training max, see progression.ParseScheme
+	Days      []ProgramDay `gorm:"foreignKey:ProgramWeekID;constraint:OnDelete:CASCADE"`
+}
+
+type ProgramDay struct {
+	ID            uint    `gorm:"primaryKey;not null"`
+	ProgramWeekID uint    `gorm:"not null"`
+	DayNo         int     `gorm:"not null"`
+	WorkoutID     uint    `gorm:"not null"`
+	Workout       Workout `gorm:"foreignKey:WorkoutID;references:ID"`
+}
+
+type ExerciseAlias struct {
+	Name       string `gorm:"primaryKey;not null"`
+	ExerciseID string `gorm:"not null"`
 }
 
 // Exercise
@@ -64,6 +194,16 @@ func (e Exercise) GetName() string {
 	return gjson.Get(e.Data, "name").String()
 }
 
+// GetCategory is e.g. "strength", "cardio" or "stretching".
+func (e Exercise) GetCategory() string {
+	return gjson.Get(e.Data, "category").String()
+}
+
+// GetEquipment is e.g. "barbell", "dumbbell" or "body only", empty if unknown.
+func (e Exercise) GetEquipment() string {
+	return gjson.Get(e.Data, "equipment").String()
+}
+
 func (e Exercise) GetMusclesString() []gjson.Result {
 	// TODO: Maybe merge secondary muscles
 	primary := gjson.Get(e.Data, "primaryMuscles").Array()
@@ -89,8 +229,48 @@ func (e Exercise) GetSecondaryMuscles() []string {
 	return result
 }
 
+// IsSeeded reports whether the exercise ships with clift (see INSERT_DATA)
+// rather than being added by the user.
+func (e Exercise) IsSeeded() bool {
+	seededOnce.Do(func() {
+		seeded = make(map[string]bool, 1000)
+		for _, m := range seededIDs.FindAllStringSubmatch(INSERT_DATA, -1) {
+			seeded[m[1]] = true
+		}
+	})
+	return seeded[e.ID]
+}
+
+var (
+	seededIDs  = regexp.MustCompile(`VALUES \(\s*'([^']*)'`)
+	seededOnce sync.Once
+	seeded     map[string]bool
+)
+
+// FindExercise looks up an exercise by ID or by display name, both case
+// insensitive. IDs take precedence.
+func FindExercise(exercises []Exercise, query string) (Exercise, bool) {
+	for _, e := range exercises {
+		if strings.EqualFold(e.ID, query) {
+			return e, true
+		}
+	}
+	for _, e := range exercises {
+		if strings.EqualFold(e.GetName(), query) {
+			return e, true
+		}
+	}
+	return Exercise{}, false
+}
+
 // --- DB manager functions ---
 
+func (t *TrainingDB) Transaction(fn func(Store) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&TrainingDB{db: tx})
+	})
+}
+
 func (t *TrainingDB) CreateWorkout(name string) (*Workout, error) {
 	w := &Workout{Name: name}
 	result := t.db.Create(w)
@@ -207,6 +387,49 @@ func (t *TrainingDB) UpdateWorkoutExerciseSets(weID uint, newSets []Set) error {
 	})
 }
 
+// UpdateProgression saves the progression rule of a workout exercise.
+func (t *TrainingDB) UpdateProgression(we WorkoutExercise) error {
+	return t.db.Model(&WorkoutExercise{}).Where("id = ?", we.ID).Updates(map[string]any{
+		"progression":    we.Progression,
+		"increment":      we.Increment,
+		"reps_min":       we.RepsMin,
+		"reps_max":       we.RepsMax,
+		"training_max":   we.TrainingMax,
+		"deload_after":   we.DeloadAfter,
+		"deload_percent": we.DeloadPercent,
+	}).Error
+}
+
+// UpdateRest saves the rest between the sets of a workout exercise.
+func (t *TrainingDB) UpdateRest(weID uint, seconds int) error {
+	return t.db.Model(&WorkoutExercise{}).Where("id = ?", weID).Update("rest", seconds).Error
+}
+
+// ApplyProgression replaces the set targets of a workout exercise with
+// progressed ones and remembers the newest session they were derived from.
+func (t *TrainingDB) ApplyProgression(weID uint, sets []Set, trainingMax float64, progressedAt time.Time) error {
+	newSets := make([]Set, len(sets))
+	for i, s := range sets {
+		newSets[i] = Set{WorkoutExerciseID: weID, Reps: s.Reps, Weight: s.Weight, Duration: s.Duration, Distance: s.Distance, Type: s.Type, TargetRPE: s.TargetRPE}
+	}
+
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("workout_exercise_id = ?", weID).Delete(&Set{}).Error
+		if err != nil {
+			return err
+		}
+		if len(newSets) > 0 {
+			if err := tx.Create(&newSets).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&WorkoutExercise{}).Where("id = ?", weID).Updates(map[string]any{
+			"training_max":  trainingMax,
+			"progressed_at": progressedAt,
+		}).Error
+	})
+}
+
 func (t *TrainingDB) RemoveExerciseFromWorkout(weID uint) error {
 	return t.db.Delete(&WorkoutExercise{}, weID).Error
 }
@@ -240,6 +463,39 @@ func (t *TrainingDB) GetAllPerformedSets() ([]PerformedSet, error) {
 	return s, err
 }
 
+// SetFilter narrows down performed sets. Zero values don't filter.
+type SetFilter struct {
+	From        time.Time // inclusive
+	To          time.Time // inclusive, the whole day
+	ExerciseIDs []string
+	WorkoutID   uint
+}
+
+// GetPerformedSets returns the sets matching filter, newest first, skipping
+// offset sets. limit <= 0 returns all of them.
+func (t *TrainingDB) GetPerformedSets(filter SetFilter, limit, offset int) ([]PerformedSet, error) {
+	q := t.db.Order("performed_date desc, id asc")
+	if !filter.From.IsZero() {
+		q = q.Where("performed_date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q = q.Where("performed_date < ?", filter.To.AddDate(0, 0, 1))
+	}
+	if len(filter.ExerciseIDs) > 0 {
+		q = q.Where("exercise_id IN ?", filter.ExerciseIDs)
+	}
+	if filter.WorkoutID != 0 {
+		q = q.Where("workout_id = ?", filter.WorkoutID)
+	}
+	if limit > 0 {
+		q = q.Limit(limit).Offset(offset)
+	}
+
+	var s []PerformedSet
+	err := q.Find(&s).Error
+	return s, err
+}
+
 func (t *TrainingDB) LogPerformedSet(workoutID uint, exerciseID string, setNo, reps int, weight float64, performedDate time.Time) error {
 	if reps <= 0 {
 		return nil
@@ -257,7 +513,7 @@ func (t *TrainingDB) LogPerformedSet(workoutID uint, exerciseID string, setNo, r
 }
 
 func (t *TrainingDB) LogSet(set PerformedSet) error {
-	if set.Reps <= 0 {
+	if set.IsEmpty() {
 		return nil
 	}
 
@@ -267,8 +523,8 @@ func (t *TrainingDB) LogSet(set PerformedSet) error {
 func (t *TrainingDB) LogSetsTransaction(sets []PerformedSet) error {
 	return t.db.Transaction(func(tx *gorm.DB) error {
 		for _, set := range sets {
-			// zero reps are not logged
-			if set.Reps <= 0 {
+			// empty sets are not logged
+			if set.IsEmpty() {
 				continue
 			}
 
@@ -281,27 +537,385 @@ func (t *TrainingDB) LogSetsTransaction(sets []PerformedSet) error {
 	})
 }
 
-// raw stuff
+// UpdatePerformedSet changes date, set number, reps, weight, type, effort and
+// the cardio values of a logged set.
+func (t *TrainingDB) UpdatePerformedSet(set PerformedSet) error {
+	return t.db.Model(&PerformedSet{ID: set.ID}).
+		Select("performed_date", "set_no", "reps", "weight", "duration", "distance", "heart_rate", "calories", "type", "rpe", "rir", "unit").
+		Updates(set).Error
+}
+
+// DeletePerformedSets deletes the sets with ids. Sessions left without sets
+// are deleted as well and returned, to be restored by RestorePerformedSets.
+func (t *TrainingDB) DeletePerformedSets(ids []uint) ([]Session, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var sessions []Session
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		var sessionIDs []uint
+		err := tx.Model(&PerformedSet{}).Where("id IN ? AND session_id IS NOT NULL", ids).
+			Distinct().Pluck("session_id", &sessionIDs).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&PerformedSet{}, ids).Error; err != nil {
+			return err
+		}
+		sessions, err = deleteEmptySessions(tx, sessionIDs)
+		return err
+	})
+	return sessions, err
+}
+
+// DeleteDay deletes all sets performed on the day of day, in its location,
+// and the sessions left without sets. It returns what was deleted.
+func (t *TrainingDB) DeleteDay(day time.Time) ([]PerformedSet, []Session, error) {
+	from := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+
+	var sets []PerformedSet
+	var sessions []Session
+	err := t.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("performed_date >= ? AND performed_date < ?", from, from.AddDate(0, 0, 1)).
+			Order("performed_date, id").Find(&sets).Error
+		if err != nil || len(sets) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(sets))
+		sessionIDs := make([]uint, 0, 5)
+		for i, s := range sets {
+			ids[i] = s.ID
+			if s.SessionID != 0 && !slices.Contains(sessionIDs, s.SessionID) {
+				sessionIDs = append(sessionIDs, s.SessionID)
+			}
+		}
+		if err := tx.Delete(&PerformedSet{}, ids).Error; err != nil {
+			return err
+		}
+		sessions, err = deleteEmptySessions(tx, sessionIDs)
+		return err
+	})
+	return sets, sessions, err
+}
 
-func (t *TrainingDB) MyRaw() [][]string {
-	rows, err := gorm.G[any](t.db).Raw("select exercise_id, set_count, calendar_week from vw_weekly_volume where exercise_id = ?", "Pullups").Rows(context.Background())
+// deleteEmptySessions deletes those of the sessions with ids that have no
+// sets anymore and returns them.
+func deleteEmptySessions(tx *gorm.DB, ids []uint) ([]Session, error) {
+	var empty []Session
+	if len(ids) == 0 {
+		return empty, nil
+	}
+	err := tx.Where("id IN ?", ids).
+		Where("NOT EXISTS (SELECT 1 FROM performed_sets WHERE performed_sets.session_id = sessions.id)").
+		Find(&empty).Error
+	if err != nil || len(empty) == 0 {
+		return empty, err
+	}
+
+	emptyIDs := make([]uint, len(empty))
+	for i, s := range empty {
+		emptyIDs[i] = s.ID
+	}
+	return empty, tx.Delete(&Session{}, emptyIDs).Error
+}
+
+// RestorePerformedSets writes sessions and sets back as they are, with their
+// IDs. Deleted ones are inserted again, edited sets get their old values back.
+func (t *TrainingDB) RestorePerformedSets(sets []PerformedSet, sessions []Session) error {
+	if len(sets) == 0 && len(sessions) == 0 {
+		return nil
+	}
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		for _, session := range sessions {
+			err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Omit(clause.Associations).Create(&session).Error
+			if err != nil {
+				return err
+			}
+		}
+		// replaced rather than updated, fields that were empty before have
+		// to become empty again
+		for _, set := range sets {
+			if err := tx.Delete(&PerformedSet{}, set.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&set).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetImportKeys returns the keys of all imported sets.
+func (t *TrainingDB) GetImportKeys() (map[string]bool, error) {
+	var keys []string
+	err := t.db.Model(&PerformedSet{}).Where("import_key IS NOT NULL").Pluck("import_key", &keys).Error
+
+	result := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		result[k] = true
+	}
+	return result, err
+}
+
+// GetExerciseAliases maps lower case foreign exercise names to exercise IDs.
+func (t *TrainingDB) GetExerciseAliases() (map[string]string, error) {
+	var aliases []ExerciseAlias
+	err := t.db.Find(&aliases).Error
+
+	result := make(map[string]string, len(aliases))
+	for _, a := range aliases {
+		result[a.Name] = a.ExerciseID
+	}
+	return result, err
+}
+
+func (t *TrainingDB) SaveExerciseAlias(name string, exerciseID string) error {
+	alias := ExerciseAlias{Name: strings.ToLower(name), ExerciseID: exerciseID}
+	return t.db.Save(&alias).Error
+}
+
+// Session
+
+func (s Session) Duration() time.Duration {
+	if s.EndedAt.Before(s.StartedAt) {
+		return 0
+	}
+	return s.EndedAt.Sub(s.StartedAt)
+}
+
+func (t *TrainingDB) GetSessions(ids []uint) ([]Session, error) {
+	var s []Session
+	if len(ids) == 0 {
+		return s, nil
+	}
+	err := t.db.Find(&s, ids).Error
+	return s, err
+}
+
+// LogSession stores session together with its sets in one transaction. Empty
+// sets are not logged; if none are left, neither is the session.
+func (t *TrainingDB) LogSession(session *Session, sets []PerformedSet) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		toLog := make([]PerformedSet, 0, len(sets))
+		for _, set := range sets {
+			if !set.IsEmpty() {
+				toLog = append(toLog, set)
+			}
+		}
+		if len(toLog) == 0 {
+			return nil
+		}
+
+		if err := tx.Omit(clause.Associations).Create(session).Error; err != nil {
+			return err
+		}
+		for i := range toLog {
+			toLog[i].SessionID = session.ID
+			if err := tx.Create(&toLog[i]).Error; err != nil {
+				return err
+			}
+		}
+		if session.ProgramDayID != 0 {
+			return advanceProgram(tx, session.ProgramDayID)
+		}
+		return nil
+	})
+}
+
+// WeeklyVolume is a row of vw_weekly_volume: what was done of an exercise in
+// an ISO week like "2026-W07".
+type WeeklyVolume struct {
+	ExerciseID   string
+	CalendarWeek string
+	SetCount     int
+	Tonnage      float64
+}
+
+// GetWeeklyVolume returns the volume per exercise and week, from fromWeek on,
+// newest week first. An empty fromWeek returns all weeks.
+func (t *TrainingDB) GetWeeklyVolume(fromWeek string) ([]WeeklyVolume, error) {
+	var v []WeeklyVolume
+	err := t.db.Table("vw_weekly_volume").
+		Where("calendar_week >= ?", fromWeek).
+		Order("calendar_week desc, exercise_id asc").
+		Find(&v).Error
+	return v, err
+}
+
+// Program
+
+// Position is where the program stands. A position out of range, e.g. after
+// weeks were removed, starts over at the first day. -1, -1 without any days.
+func (p Program) Position() (int, int) {
+	week, day := p.Week, p.Day
+	if week < 0 || week >= len(p.Weeks) || day < 0 || day >= len(p.Weeks[week].Days) {
+		return p.Next(-1, 0)
+	}
+	return week, day
+}
+
+// Today is the week and day at the position of the program.
+func (p Program) Today() (ProgramWeek, ProgramDay, bool) {
+	week, day := p.Position()
+	if week < 0 {
+		return ProgramWeek{}, ProgramDay{}, false
+	}
+	return p.Weeks[week], p.Weeks[week].Days[day], true
+}
+
+// Next is the position of the day after the given one, skipping weeks without
+// days and starting over after the last week. -1, -1 if there are no days.
+func (p Program) Next(week, day int) (int, int) {
+	day++
+	for range len(p.Weeks) + 1 {
+		if week >= 0 && week < len(p.Weeks) && day < len(p.Weeks[week].Days) {
+			return week, day
+		}
+		week, day = (week+1)%max(len(p.Weeks), 1), 0
+	}
+	return -1, -1
+}
+
+// find is the position of a program day, -1, -1 if it is not part of p.
+func (p Program) find(dayID uint) (int, int) {
+	for w, week := range p.Weeks {
+		for d, day := range week.Days {
+			if day.ID == dayID {
+				return w, d
+			}
+		}
+	}
+	return -1, -1
+}
+
+func preloadProgram(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Weeks", func(db *gorm.DB) *gorm.DB { return db.Order("week_no, id") }).
+		Preload("Weeks.Days", func(db *gorm.DB) *gorm.DB { return db.Order("day_no, id") }).
+		Preload("Weeks.Days.Workout", func(db *gorm.DB) *gorm.DB { return db.Unscoped() })
+}
+
+func (t *TrainingDB) CreateProgram(name string) (*Program, error) {
+	p := &Program{Name: name}
+	if err := t.db.Create(p).Error; err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (t *TrainingDB) GetPrograms() ([]Program, error) {
+	var ps []Program
+	err := preloadProgram(t.db).Order("name").Find(&ps).Error
+	return ps, err
+}
+
+func (t *TrainingDB) GetProgram(id uint) (Program, error) {
+	var p Program
+	err := preloadProgram(t.db).First(&p, id).Error
+	return p, err
+}
+
+// GetActiveProgram returns the program that is being followed, nil if there
+// is none.
+func (t *TrainingDB) GetActiveProgram() (*Program, error) {
+	var ps []Program
+	err := preloadProgram(t.db).Where("active").Limit(1).Find(&ps).Error
+	if err != nil || len(ps) == 0 {
+		return nil, err
+	}
+	return &ps[0], nil
+}
+
+func (t *TrainingDB) RemoveProgram(id uint) error {
+	return t.db.Delete(&Program{}, id).Error
+}
+
+// ActivateProgram makes the program the one being followed, deactivating all
+// others.
+func (t *TrainingDB) ActivateProgram(id uint) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Program{}).Where("active").Update("active", false).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&Program{}).Where("id = ?", id).Update("active", true).Error
+	})
+}
+
+func (t *TrainingDB) SetProgramPosition(id uint, week, day int) error {
+	return t.db.Model(&Program{}).Where("id = ?", id).
+		Updates(map[string]any{"week": week, "day": day}).Error
+}
+
+// AddProgramWeek appends a week with a day for each of workoutIDs.
+func (t *TrainingDB) AddProgramWeek(programID uint, name, scheme string, workoutIDs []uint) (*ProgramWeek, error) {
+	var weekNo int
+	err := t.db.Model(&ProgramWeek{}).Where("program_id = ?", programID).
+		Select("coalesce(max(week_no), 0) + 1").Scan(&weekNo).Error
 	if err != nil {
+		return nil, err
+	}
 
+	week := &ProgramWeek{ProgramID: programID, WeekNo: weekNo, Name: name, Scheme: scheme}
+	for i, id := range workoutIDs {
+		week.Days = append(week.Days, ProgramDay{DayNo: i + 1, WorkoutID: id})
+	}
+	if err := t.db.Create(week).Error; err != nil {
+		return nil, err
 	}
-	defer rows.Close()
+	return week, nil
+}
+
+// UpdateProgramWeek saves name and scheme of a week.
+func (t *TrainingDB) UpdateProgramWeek(week ProgramWeek) error {
+	return t.db.Model(&ProgramWeek{}).Where("id = ?", week.ID).
+		Updates(map[string]any{"name": week.Name, "scheme": week.Scheme}).Error
+}
 
-	foo := make([][]string, 0, 100)
-	for rows.Next() {
-		var exercise_id, set_count, calendar_week string
-		r := make([]string, 3)
+func (t *TrainingDB) RemoveProgramWeek(weekID uint) error {
+	return t.db.Delete(&ProgramWeek{}, weekID).Error
+}
 
-		rows.Scan(&exercise_id, &set_count, &calendar_week)
-		r[0] = exercise_id
-		r[1] = set_count
-		r[2] = calendar_week
+func (t *TrainingDB) AddProgramDay(weekID uint, workoutID uint) (*ProgramDay, error) {
+	var dayNo int
+	err := t.db.Model(&ProgramDay{}).Where("program_week_id = ?", weekID).
+		Select("coalesce(max(day_no), 0) + 1").Scan(&dayNo).Error
+	if err != nil {
+		return nil, err
+	}
 
-		foo = append(foo, r)
-		// do something
+	day := &ProgramDay{ProgramWeekID: weekID, DayNo: dayNo, WorkoutID: workoutID}
+	if err := t.db.Omit(clause.Associations).Create(day).Error; err != nil {
+		return nil, err
+	}
+	return day, nil
+}
+
+func (t *TrainingDB) RemoveProgramDay(dayID uint) error {
+	return t.db.Delete(&ProgramDay{}, dayID).Error
+}
+
+// advanceProgram moves the program of a day to the day after it.
+func advanceProgram(tx *gorm.DB, dayID uint) error {
+	var week ProgramWeek
+	err := tx.Joins("JOIN program_days ON program_days.program_week_id = program_weeks.id").
+		Where("program_days.id = ?", dayID).First(&week).Error
+	if err != nil {
+		return err
+	}
+
+	var p Program
+	if err := preloadProgram(tx).First(&p, week.ProgramID).Error; err != nil {
+		return err
+	}
+	w, d := p.find(dayID)
+	if w < 0 {
+		return nil
 	}
-	return foo
+	w, d = p.Next(w, d)
+	return tx.Model(&Program{}).Where("id = ?", p.ID).
+		Updates(map[string]any{"week": w, "day": d}).Error
 }
@@ -5,32 +5,19 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"sync"
 
-	//"gorm.io/driver/sqlite"
 	"github.com/glebarez/sqlite" // use this for pure go (no c)
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-var lock = &sync.Mutex{}
-
 type TrainingDB struct {
 	db *gorm.DB
-
-	// err = db.SetupJoinTable(&Workout{}, "WorkoutExercises", &WorkoutExercise{})
-	// if err != nil {
-	// 	log.Fatalf("failed to migrate: %v", err)
-	// }
-}
-
-var instance *TrainingDB
-
-func Instance() *TrainingDB {
-	return instance
 }
 
-func Init(path string) {
+// Init opens the training database at path. Missing folders are created and a
+// new database is filled with the basic exercise data.
+func Init(path string) *TrainingDB {
 	dir := filepath.Dir(path)
 
 	// if db not existing
@@ -48,68 +35,81 @@ func Init(path string) {
 		log.Fatalf("Cannot access db path (%v): %v", path, err)
 	}
 
-	if instance == nil {
-		lock.Lock()
-		defer lock.Unlock()
-		if instance == nil {
-			dsn := path + "?_pragma=foreign_keys(1)"
-
-			db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
-			if err != nil {
-				log.Fatalf("failed to open DB: %v", err)
-			}
-
-			err = db.AutoMigrate(&Workout{}, &Exercise{}, &PerformedSet{}, &WorkoutExercise{}, &Set{})
-			if err != nil {
-				log.Fatalf("failed to migrate: %v", err)
-			}
-
-			// if first time, fill up with basic data
-			if firstTime {
-				// use session here to create silent logger; somehow prints all statements to stdout if using blunt exec
-				err := db.Session(&gorm.Session{Logger: db.Logger.LogMode(logger.Silent)}).Exec(INSERT_DATA).Error
-
-				if err != nil {
-					log.Fatalf("failed to insert initial data: %v", err)
-				}
-				//db.Exec(INSERT_DATA)
-			}
-
-			instance = &TrainingDB{db: db}
+	dsn := path + "?_pragma=foreign_keys(1)"
+
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		log.Fatalf("failed to open DB: %v", err)
+	}
+
+	// a fresh database has nothing worth backing up
+	backupPath := path
+	if firstTime {
+		backupPath = ""
+	}
+	err = migrate(db, backupPath)
+	if err != nil {
+		log.Fatalf("failed to migrate: %v", err)
+	}
+
+	// if first time, fill up with basic data
+	if firstTime {
+		if err := insertInitialData(db); err != nil {
+			log.Fatalf("failed to insert initial data: %v", err)
 		}
 	}
 
+	return &TrainingDB{db: db}
+}
+
+// NewInMemoryTrainingDB returns a migrated and seeded database that lives in
+// memory only. Meant for tests and throwaway sessions.
+func NewInMemoryTrainingDB() *TrainingDB {
+	db, err := gorm.Open(sqlite.Open("file::memory:?_pragma=foreign_keys(1)"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		log.Fatalf("failed to open DB: %v", err)
+	}
+
+	// every pooled connection would get its own empty memory database
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("failed to get sql.DB: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := migrate(db, ""); err != nil {
+		log.Fatalf("failed to migrate: %v", err)
+	}
+	if err := insertInitialData(db); err != nil {
+		log.Fatalf("failed to insert initial data: %v", err)
+	}
+
+	return &TrainingDB{db: db}
 }
 
+func insertInitialData(db *gorm.DB) error {
+	// use session here to create silent logger; somehow prints all statements to stdout if using blunt exec
+	return db.Session(&gorm.Session{Logger: db.Logger.LogMode(logger.Silent)}).Exec(INSERT_DATA).Error
+}
+
+// NewTrainingDB opens the existing training database at path and migrates it
+// to the latest schema, backing it up first.
 func NewTrainingDB(path string) *TrainingDB {
-	//dsn := path + "?_pragma=foreign_keys(1)"
-	//dsn := path + "?_foreign_keys=on"
 	dsn := path + "?_pragma=foreign_keys(1)"
 
+	backupPath := ""
+	if fi, err := os.Stat(path); err == nil && fi.Size() > 0 {
+		backupPath = path
+	}
+
 	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
 	if err != nil {
 		log.Fatalf("failed to open DB: %v", err)
 	}
 
-	// // 💡 Step 1: Get the underlying database connection pool
-	// sqlDB, err := db.DB()
-	// if err != nil {
-	// 	log.Fatalf("failed to get sql.DB: %v", err)
-	// }
-
-	// // SQLite usually runs fine with a single connection (default).
-	// // If you explicitly set MaxOpenConns to 1, you guarantee
-	// // that the subsequent Exec will target the only active connection.
-	// //sqlDB.SetMaxOpenConns(1)
-
-	// // 💡 Step 2: Manually enforce the PRAGMA on the primary connection.
-	// // This is run once during initialization.
-	// if _, err := sqlDB.Exec("PRAGMA foreign_keys = ON"); err != nil {
-	// 	log.Fatalf("failed to enforce PRAGMA foreign_keys = ON: %v", err)
-	// }
-
-	// Step 3: Run migrations (ensure foreign keys are created correctly)
-	err = db.AutoMigrate(&Workout{}, &Exercise{}, &PerformedSet{}, &WorkoutExercise{}, &Set{})
+	err = migrate(db, backupPath)
 	if err != nil {
 		log.Fatalf("failed to migrate: %v", err)
 	}
@@ -0,0 +1,159 @@
+package db
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Up-migrations live in migrations/ and are named NNNN_description.sql.
+// The number is the schema version the file upgrades to. Files are applied
+// in order, each one in its own transaction, and never edited after release;
+// schema changes always go into a new file.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+type SchemaVersion struct {
+	Version   int       `gorm:"primaryKey;not null"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaVersion) TableName() string {
+	return "schema_version"
+}
+
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]migration, 0, len(entries))
+	for _, e := range entries {
+		base := strings.TrimSuffix(e.Name(), ".sql")
+		number, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %v is not named NNNN_description.sql", e.Name())
+		}
+		version, err := strconv.Atoi(number)
+		if err != nil {
+			return nil, fmt.Errorf("migration %v has no valid version: %v", e.Name(), err)
+		}
+
+		sql, err := migrationFiles.ReadFile(path.Join("migrations", e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, migration{version: version, name: name, sql: string(sql)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	for i := range migrations {
+		if i > 0 && migrations[i].version == migrations[i-1].version {
+			return nil, fmt.Errorf("duplicate migration version %v", migrations[i].version)
+		}
+	}
+
+	return migrations, nil
+}
+
+// LatestSchemaVersion is the version a database has after all migrations
+// shipped with this binary are applied.
+func LatestSchemaVersion() int {
+	migrations, err := loadMigrations()
+	if err != nil || len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].version
+}
+
+func currentSchemaVersion(db *gorm.DB) (int, error) {
+	if err := db.AutoMigrate(&SchemaVersion{}); err != nil {
+		return 0, err
+	}
+
+	var version int
+	err := db.Model(&SchemaVersion{}).Select("coalesce(max(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+// migrate brings the schema up to the latest version. If backupPath is set
+// and there is anything to apply, a copy of the database is written there
+// first (see backup).
+func migrate(db *gorm.DB, backupPath string) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	current, err := currentSchemaVersion(db)
+	if err != nil {
+		return fmt.Errorf("reading schema version: %v", err)
+	}
+
+	pending := make([]migration, 0, len(migrations))
+	for _, m := range migrations {
+		if m.version > current {
+			pending = append(pending, m)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if backupPath != "" {
+		if _, err := backup(db, backupPath, current); err != nil {
+			return fmt.Errorf("backup before migration failed: %v", err)
+		}
+	}
+
+	// migration files are long; don't echo them
+	quiet := db.Session(&gorm.Session{Logger: db.Logger.LogMode(logger.Silent)})
+	for _, m := range pending {
+		err := quiet.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(m.sql).Error; err != nil {
+				return err
+			}
+			return tx.Create(&SchemaVersion{
+				Version:   m.version,
+				Name:      m.name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %04d_%v failed: %v", m.version, m.name, err)
+		}
+	}
+
+	return nil
+}
+
+// backup writes a consistent copy of the database next to dbPath, tagged with
+// the schema version it was taken at. Returns the path of the copy.
+func backup(db *gorm.DB, dbPath string, version int) (string, error) {
+	target := fmt.Sprintf("%v.v%04d-%v.bak", dbPath, version, time.Now().Format("20060102-150405"))
+	if _, err := os.Stat(target); err == nil {
+		return "", fmt.Errorf("backup file already exists: %v", target)
+	}
+
+	// VACUUM INTO cannot run inside a transaction, so this happens up front
+	return target, db.Exec("VACUUM INTO ?", target).Error
+}
@@ -0,0 +1,175 @@
+package db
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func open(t *testing.T, path string) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(path+"?_pragma=foreign_keys(1)"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { closeDB(db) })
+	return db
+}
+
+func closeDB(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
+
+func schemaVersion(t *testing.T, db *gorm.DB) int {
+	t.Helper()
+	version, err := currentSchemaVersion(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return version
+}
+
+func backups(t *testing.T, path string) []string {
+	t.Helper()
+	files, err := filepath.Glob(path + ".v*.bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("migration %v_%v is number %v", m.version, m.name, i+1)
+		}
+	}
+	if LatestSchemaVersion() != len(migrations) {
+		t.Errorf("LatestSchemaVersion = %v, want %v", LatestSchemaVersion(), len(migrations))
+	}
+}
+
+func TestMigrateFresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "new", "training.db")
+
+	store := Init(path)
+	t.Cleanup(func() { closeDB(store.db) })
+
+	if v := schemaVersion(t, store.db); v != LatestSchemaVersion() {
+		t.Errorf("schema version %v, want %v", v, LatestSchemaVersion())
+	}
+	if b := backups(t, path); len(b) != 0 {
+		t.Errorf("fresh database backed up to %v", b)
+	}
+	if exercises, err := store.GetAllExercises(); err != nil || len(exercises) == 0 {
+		t.Errorf("fresh database has %v exercises, %v", len(exercises), err)
+	}
+
+	// opening it again has nothing to do
+	again := NewTrainingDB(path)
+	t.Cleanup(func() { closeDB(again.db) })
+	if b := backups(t, path); len(b) != 0 {
+		t.Errorf("up to date database backed up to %v", b)
+	}
+}
+
+// TestMigrateExisting upgrades a database as releases before versioned
+// migrations left it: the baseline schema without schema_version.
+func TestMigrateExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "training.db")
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := open(t, path)
+	statements := []string{
+		migrations[0].sql,
+		`INSERT INTO exercises (id, data) VALUES ('Barbell_Squat', '{"name": "Barbell Squat"}')`,
+		`INSERT INTO workouts (id, name) VALUES (1, 'Legs')`,
+		`INSERT INTO performed_sets (workout_id, exercise_id, performed_date, set_no, reps, weight) VALUES
+			(1, 'Barbell_Squat', '2025-01-06 18:00:00+01:00', 0, 5, 100),
+			(1, 'Barbell_Squat', '2025-01-06 18:00:00+01:00', 1, 5, 100),
+			(NULL, 'Barbell_Squat', '2025-01-08 07:00:00+01:00', 0, 3, 110)`,
+	}
+	for _, s := range statements {
+		if err := old.Exec(s).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	closeDB(old)
+
+	store := NewTrainingDB(path)
+	t.Cleanup(func() { closeDB(store.db) })
+
+	if v := schemaVersion(t, store.db); v != LatestSchemaVersion() {
+		t.Errorf("schema version %v, want %v", v, LatestSchemaVersion())
+	}
+
+	sets, err := store.GetAllPerformedSets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sets) != 3 {
+		t.Fatalf("%v sets after migrating, want 3", len(sets))
+	}
+	sessions := make(map[uint]int)
+	for _, s := range sets {
+		if s.Unit != "kg" {
+			t.Errorf("set %v has unit %q, want kg", s.ID, s.Unit)
+		}
+		if s.SessionID == 0 {
+			t.Errorf("set %v has no session", s.ID)
+		}
+		sessions[s.SessionID]++
+	}
+	if len(sessions) != 2 {
+		t.Errorf("sets grouped into %v sessions, want 2", len(sessions))
+	}
+
+	b := backups(t, path)
+	if len(b) != 1 || !strings.HasPrefix(filepath.Base(b[0]), "training.db.v0000-") {
+		t.Fatalf("backups %v, want one of version 0", b)
+	}
+	saved := open(t, b[0])
+	var count int
+	if err := saved.Raw("SELECT count(*) FROM performed_sets").Scan(&count).Error; err != nil || count != 3 {
+		t.Errorf("backup holds %v sets, %v", count, err)
+	}
+	if saved.Migrator().HasColumn("performed_sets", "session_id") {
+		t.Error("backup was taken after migrating")
+	}
+}
+
+func TestBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "training.db")
+	db := open(t, path)
+	if err := db.Exec("CREATE TABLE t (x integer); INSERT INTO t VALUES (1)").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	target, err := backup(db, path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := backups(t, path); len(b) != 1 || b[0] != target {
+		t.Fatalf("backups %v, want %v", b, target)
+	}
+
+	var x int
+	if err := open(t, target).Raw("SELECT x FROM t").Scan(&x).Error; err != nil || x != 1 {
+		t.Errorf("backup holds %v, %v", x, err)
+	}
+}
@@ -0,0 +1,45 @@
+-- Baseline schema. Matches what earlier releases created through gorm's
+-- AutoMigrate, so databases created before versioned migrations existed pass
+-- through unchanged.
+
+CREATE TABLE IF NOT EXISTS `workouts` (
+    `id` integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    `name` text NOT NULL,
+    `deleted` datetime
+);
+
+CREATE TABLE IF NOT EXISTS `exercises` (
+    `id` text NOT NULL,
+    `data` text,
+    PRIMARY KEY (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `performed_sets` (
+    `id` integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    `workout_id` integer DEFAULT null,
+    `exercise_id` text,
+    `performed_date` datetime,
+    `set_no` integer,
+    `reps` integer,
+    `weight` real,
+    CONSTRAINT `fk_exercises_performed_sets` FOREIGN KEY (`exercise_id`) REFERENCES `exercises`(`id`),
+    CONSTRAINT `fk_workouts_performed_sets` FOREIGN KEY (`workout_id`) REFERENCES `workouts`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `workout_exercises` (
+    `id` integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    `workout_id` integer NOT NULL,
+    `exercise_id` text NOT NULL,
+    `note` text,
+    `deleted` datetime,
+    CONSTRAINT `fk_exercises_workout_exercises` FOREIGN KEY (`exercise_id`) REFERENCES `exercises`(`id`),
+    CONSTRAINT `fk_workouts_workout_exercises` FOREIGN KEY (`workout_id`) REFERENCES `workouts`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `sets` (
+    `id` integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    `workout_exercise_id` integer NOT NULL,
+    `reps` integer NOT NULL,
+    `weight` real NOT NULL,
+    CONSTRAINT `fk_workout_exercises_sets` FOREIGN KEY (`workout_exercise_id`) REFERENCES `workout_exercises`(`id`) ON DELETE CASCADE
+);
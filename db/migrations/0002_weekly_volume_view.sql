@@ -0,0 +1,9 @@
+-- Number of logged sets per exercise and ISO calendar week.
+
+CREATE VIEW IF NOT EXISTS `vw_weekly_volume` AS
+SELECT
+    `exercise_id`,
+    COUNT(*) AS `set_count`,
+    strftime('%G-W%V', `performed_date`) AS `calendar_week`
+FROM `performed_sets`
+GROUP BY `exercise_id`, `calendar_week`;
@@ -0,0 +1,17 @@
+-- Sets imported from other apps carry a key derived from the source row, so
+-- importing the same file twice doesn't duplicate them.
+
+ALTER TABLE `performed_sets` ADD COLUMN `import_key` text DEFAULT null;
+
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_performed_sets_import_key`
+    ON `performed_sets`(`import_key`)
+    WHERE `import_key` IS NOT NULL;
+
+-- Exercise names of other apps mapped to our exercises, remembered across
+-- imports. Names are stored lower case.
+
+CREATE TABLE IF NOT EXISTS `exercise_aliases` (
+    `name` text PRIMARY KEY NOT NULL,
+    `exercise_id` text NOT NULL,
+    CONSTRAINT `fk_exercises_exercise_aliases` FOREIGN KEY (`exercise_id`) REFERENCES `exercises`(`id`) ON DELETE CASCADE
+);
@@ -0,0 +1,32 @@
+-- Sessions group the sets of one training, with start/end, notes and
+-- bodyweight. Existing sets are grouped by workout and performed date, which
+-- is what one submit of a workout used to write.
+
+CREATE TABLE IF NOT EXISTS `sessions` (
+    `id` integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    `workout_id` integer DEFAULT null,
+    `started_at` datetime,
+    `ended_at` datetime,
+    `notes` text,
+    `bodyweight` real,
+    CONSTRAINT `fk_workouts_sessions` FOREIGN KEY (`workout_id`) REFERENCES `workouts`(`id`)
+);
+
+ALTER TABLE `performed_sets` ADD COLUMN `session_id` integer DEFAULT null
+    CONSTRAINT `fk_sessions_performed_sets` REFERENCES `sessions`(`id`) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS `idx_performed_sets_session_id` ON `performed_sets`(`session_id`);
+
+INSERT INTO `sessions` (`workout_id`, `started_at`, `ended_at`, `notes`, `bodyweight`)
+SELECT `workout_id`, `performed_date`, `performed_date`, '', 0
+FROM `performed_sets`
+GROUP BY `workout_id`, `performed_date`
+ORDER BY `performed_date`;
+
+UPDATE `performed_sets`
+SET `session_id` = (
+    SELECT `s`.`id`
+    FROM `sessions` `s`
+    WHERE `s`.`workout_id` IS `performed_sets`.`workout_id`
+      AND `s`.`started_at` = `performed_sets`.`performed_date`
+);
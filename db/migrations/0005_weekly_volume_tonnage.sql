@@ -0,0 +1,14 @@
+-- Weekly volume per exercise now also sums up tonnage (reps x weight) and
+-- weeks follow the local time of the performed sets.
+
+DROP VIEW IF EXISTS `vw_weekly_volume`;
+
+CREATE VIEW `vw_weekly_volume` AS
+SELECT
+    `exercise_id`,
+    strftime('%G-W%V', `performed_date`, 'localtime') AS `calendar_week`,
+    COUNT(*) AS `set_count`,
+    SUM(`reps` * `weight`) AS `tonnage`
+FROM `performed_sets`
+WHERE `reps` > 0
+GROUP BY `exercise_id`, `calendar_week`;
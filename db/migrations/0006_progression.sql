@@ -0,0 +1,12 @@
+-- Progression rules of workout exercises. The rule decides how the set
+-- targets change after each session; progressed_at is the newest session the
+-- targets were last updated from, only later sessions are evaluated.
+
+ALTER TABLE `workout_exercises` ADD COLUMN `progression` text DEFAULT null;
+ALTER TABLE `workout_exercises` ADD COLUMN `increment` real DEFAULT null;
+ALTER TABLE `workout_exercises` ADD COLUMN `reps_min` integer DEFAULT null;
+ALTER TABLE `workout_exercises` ADD COLUMN `reps_max` integer DEFAULT null;
+ALTER TABLE `workout_exercises` ADD COLUMN `training_max` real DEFAULT null;
+ALTER TABLE `workout_exercises` ADD COLUMN `deload_after` integer DEFAULT null;
+ALTER TABLE `workout_exercises` ADD COLUMN `deload_percent` real DEFAULT null;
+ALTER TABLE `workout_exercises` ADD COLUMN `progressed_at` datetime DEFAULT null;
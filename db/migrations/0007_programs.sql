@@ -0,0 +1,33 @@
+-- Programs schedule workouts over weeks, e.g. a 4 week block of 5/3/1. Each
+-- week has days pointing to workout templates and an optional scheme of
+-- percentages of the training max. week and day of a program are the position
+-- of the next training, sessions remember the program day they came from.
+
+CREATE TABLE IF NOT EXISTS `programs` (
+    `id` integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    `name` text NOT NULL,
+    `week` integer NOT NULL DEFAULT 0,
+    `day` integer NOT NULL DEFAULT 0,
+    `active` numeric NOT NULL DEFAULT false
+);
+
+CREATE TABLE IF NOT EXISTS `program_weeks` (
+    `id` integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    `program_id` integer NOT NULL,
+    `week_no` integer NOT NULL,
+    `name` text,
+    `scheme` text,
+    CONSTRAINT `fk_programs_weeks` FOREIGN KEY (`program_id`) REFERENCES `programs`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `program_days` (
+    `id` integer PRIMARY KEY AUTOINCREMENT NOT NULL,
+    `program_week_id` integer NOT NULL,
+    `day_no` integer NOT NULL,
+    `workout_id` integer NOT NULL,
+    CONSTRAINT `fk_program_weeks_days` FOREIGN KEY (`program_week_id`) REFERENCES `program_weeks`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_workouts_program_days` FOREIGN KEY (`workout_id`) REFERENCES `workouts`(`id`)
+);
+
+ALTER TABLE `sessions` ADD COLUMN `program_day_id` integer DEFAULT null
+    CONSTRAINT `fk_program_days_sessions` REFERENCES `program_days`(`id`) ON DELETE SET NULL;
@@ -0,0 +1,4 @@
+-- Rest between the sets of a workout exercise in seconds; null or 0 falls
+-- back to the default rest of the config.
+
+ALTER TABLE `workout_exercises` ADD COLUMN `rest` integer DEFAULT null;
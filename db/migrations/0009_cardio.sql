@@ -0,0 +1,11 @@
+-- Cardio and timed sets: duration in seconds and distance in kilometres on
+-- set targets and performed sets, heart rate (average bpm) and calories on
+-- performed sets only. All of them are null for sets of reps and weight.
+
+ALTER TABLE `sets` ADD COLUMN `duration` integer DEFAULT null;
+ALTER TABLE `sets` ADD COLUMN `distance` real DEFAULT null;
+
+ALTER TABLE `performed_sets` ADD COLUMN `duration` integer DEFAULT null;
+ALTER TABLE `performed_sets` ADD COLUMN `distance` real DEFAULT null;
+ALTER TABLE `performed_sets` ADD COLUMN `heart_rate` integer DEFAULT null;
+ALTER TABLE `performed_sets` ADD COLUMN `calories` integer DEFAULT null;
@@ -0,0 +1,17 @@
+-- Set types: warmup, drop, amrap or failure; null is a regular working set.
+-- Weekly volume leaves warm-ups out.
+
+ALTER TABLE `sets` ADD COLUMN `type` text DEFAULT null;
+ALTER TABLE `performed_sets` ADD COLUMN `type` text DEFAULT null;
+
+DROP VIEW IF EXISTS `vw_weekly_volume`;
+
+CREATE VIEW `vw_weekly_volume` AS
+SELECT
+    `exercise_id`,
+    strftime('%G-W%V', `performed_date`, 'localtime') AS `calendar_week`,
+    COUNT(*) AS `set_count`,
+    SUM(`reps` * `weight`) AS `tonnage`
+FROM `performed_sets`
+WHERE `reps` > 0 AND `type` IS NOT 'warmup'
+GROUP BY `exercise_id`, `calendar_week`;
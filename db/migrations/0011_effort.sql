@@ -0,0 +1,7 @@
+-- Effort of performed sets as RPE (6 to 10 in half steps) or reps in reserve,
+-- and a target RPE for set templates. Null where not given.
+
+ALTER TABLE `performed_sets` ADD COLUMN `rpe` real DEFAULT null;
+ALTER TABLE `performed_sets` ADD COLUMN `rir` integer DEFAULT null;
+
+ALTER TABLE `sets` ADD COLUMN `target_rpe` real DEFAULT null;
@@ -0,0 +1,6 @@
+-- Weights are stored in kilograms. The unit a performed set was entered in is
+-- kept along, sets logged so far were all taken as kilograms.
+
+ALTER TABLE `performed_sets` ADD COLUMN `unit` text DEFAULT null;
+
+UPDATE `performed_sets` SET `unit` = 'kg';
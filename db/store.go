@@ -0,0 +1,73 @@
+package db
+
+import "time"
+
+// Store is everything the application needs from a training database.
+// *TrainingDB is the SQLite backed implementation.
+type Store interface {
+	// Transaction runs fn against a store whose changes are committed
+	// together if fn returns nil, and all rolled back otherwise.
+	Transaction(fn func(Store) error) error
+
+	// workouts
+	CreateWorkout(name string) (*Workout, error)
+	RemoveWorkout(id uint) error
+	GetAllWorkouts() ([]Workout, error)
+	GetAllPerformedWorkouts() ([]Workout, error)
+	GetWorkoutWithExercises(workoutID uint) (Workout, error)
+
+	// exercises
+	GetAllExercises() ([]Exercise, error)
+	AddExerciseToWorkout(workoutID uint, exerciseID string, note string) (*WorkoutExercise, error)
+	RemoveExerciseFromWorkout(weID uint) error
+
+	// set templates
+	UpdateWorkoutExerciseSets(weID uint, newSets []Set) error
+	AddSetTemplate(weID uint, reps int, weight float64) (*Set, error)
+	UpdateSetTemplate(setID uint, reps int, weight float64) error
+	GetSetsForWorkoutExercise(weID uint) ([]Set, error)
+	UpdateRest(weID uint, seconds int) error
+
+	// progression
+	UpdateProgression(we WorkoutExercise) error
+	ApplyProgression(weID uint, sets []Set, trainingMax float64, progressedAt time.Time) error
+
+	// performed sets
+	GetAllPerformedSets() ([]PerformedSet, error)
+	GetPerformedSets(filter SetFilter, limit, offset int) ([]PerformedSet, error)
+	LogPerformedSet(workoutID uint, exerciseID string, setNo, reps int, weight float64, performedDate time.Time) error
+	LogSet(set PerformedSet) error
+	LogSetsTransaction(sets []PerformedSet) error
+	UpdatePerformedSet(set PerformedSet) error
+	DeletePerformedSets(ids []uint) ([]Session, error)
+	DeleteDay(day time.Time) ([]PerformedSet, []Session, error)
+	RestorePerformedSets(sets []PerformedSet, sessions []Session) error
+
+	// sessions
+	LogSession(session *Session, sets []PerformedSet) error
+	GetSessions(ids []uint) ([]Session, error)
+
+	// programs
+	CreateProgram(name string) (*Program, error)
+	GetPrograms() ([]Program, error)
+	GetProgram(id uint) (Program, error)
+	GetActiveProgram() (*Program, error)
+	RemoveProgram(id uint) error
+	ActivateProgram(id uint) error
+	SetProgramPosition(id uint, week, day int) error
+	AddProgramWeek(programID uint, name, scheme string, workoutIDs []uint) (*ProgramWeek, error)
+	UpdateProgramWeek(week ProgramWeek) error
+	RemoveProgramWeek(weekID uint) error
+	AddProgramDay(weekID uint, workoutID uint) (*ProgramDay, error)
+	RemoveProgramDay(dayID uint) error
+
+	// reports
+	GetWeeklyVolume(fromWeek string) ([]WeeklyVolume, error)
+
+	// imports
+	GetImportKeys() (map[string]bool, error)
+	GetExerciseAliases() (map[string]string, error)
+	SaveExerciseAlias(name string, exerciseID string) error
+}
+
+var _ Store = (*TrainingDB)(nil)
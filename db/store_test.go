@@ -0,0 +1,376 @@
+package db
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+const (
+	SQUAT = "Barbell_Squat"
+	BENCH = "Barbell_Bench_Press_-_Medium_Grip"
+)
+
+var monday = time.Date(2026, 3, 2, 18, 0, 0, 0, time.Local)
+
+func day(n int) time.Time {
+	return monday.AddDate(0, 0, n)
+}
+
+func sets(exerciseID string, date time.Time, reps ...int) []PerformedSet {
+	s := make([]PerformedSet, len(reps))
+	for i, r := range reps {
+		s[i] = PerformedSet{ExerciseID: exerciseID, PerformedDate: date, SetNo: i, Reps: r, Weight: 100}
+	}
+	return s
+}
+
+func logSession(t *testing.T, store Store, session Session, s []PerformedSet) Session {
+	t.Helper()
+	if err := store.LogSession(&session, s); err != nil {
+		t.Fatalf("LogSession: %v", err)
+	}
+	return session
+}
+
+func ids(s []PerformedSet) []uint {
+	result := make([]uint, len(s))
+	for i := range s {
+		result[i] = s[i].ID
+	}
+	return result
+}
+
+func TestLogSession(t *testing.T) {
+	store := NewInMemoryTrainingDB()
+
+	session := logSession(t, store, Session{StartedAt: day(0), Notes: "heavy"}, sets(SQUAT, day(0), 5, 0, 3))
+	if session.ID == 0 {
+		t.Fatal("session got no ID")
+	}
+
+	logged, err := store.GetAllPerformedSets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(logged) != 2 {
+		t.Fatalf("logged %v sets, want 2 without the empty one", len(logged))
+	}
+	for _, s := range logged {
+		if s.SessionID != session.ID {
+			t.Errorf("set %v belongs to session %v, want %v", s.ID, s.SessionID, session.ID)
+		}
+	}
+
+	stored, err := store.GetSessions([]uint{session.ID})
+	if err != nil || len(stored) != 1 || stored[0].Notes != "heavy" {
+		t.Errorf("GetSessions = %v, %v", stored, err)
+	}
+}
+
+func TestLogSessionWithoutSets(t *testing.T) {
+	store := NewInMemoryTrainingDB()
+
+	session := logSession(t, store, Session{StartedAt: day(0)}, sets(SQUAT, day(0), 0, 0))
+	if session.ID != 0 {
+		t.Errorf("session of empty sets stored as %v", session.ID)
+	}
+	if logged, _ := store.GetAllPerformedSets(); len(logged) != 0 {
+		t.Errorf("logged %v empty sets", len(logged))
+	}
+}
+
+func TestGetPerformedSets(t *testing.T) {
+	store := NewInMemoryTrainingDB()
+	workout, err := store.CreateWorkout("Legs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logSession(t, store, Session{StartedAt: day(0)}, sets(SQUAT, day(0), 5, 5, 5))
+	logSession(t, store, Session{StartedAt: day(1)}, sets(BENCH, day(1), 8, 8))
+	legs := sets(SQUAT, day(2), 3, 3)
+	for i := range legs {
+		legs[i].WorkoutID = workout.ID
+	}
+	logSession(t, store, Session{StartedAt: day(2), WorkoutID: workout.ID}, legs)
+
+	tests := []struct {
+		name   string
+		filter SetFilter
+		want   int
+	}{
+		{"all", SetFilter{}, 7},
+		{"from", SetFilter{From: day(1)}, 4},
+		{"to the whole day", SetFilter{To: day(1).Truncate(24 * time.Hour)}, 5},
+		{"from and to", SetFilter{From: day(1), To: day(1)}, 2},
+		{"exercise", SetFilter{ExerciseIDs: []string{SQUAT}}, 5},
+		{"exercises", SetFilter{ExerciseIDs: []string{SQUAT, BENCH}}, 7},
+		{"workout", SetFilter{WorkoutID: workout.ID}, 2},
+		{"exercise and from", SetFilter{ExerciseIDs: []string{SQUAT}, From: day(1)}, 2},
+	}
+	for _, tt := range tests {
+		got, err := store.GetPerformedSets(tt.filter, 0, 0)
+		if err != nil || len(got) != tt.want {
+			t.Errorf("%v: got %v sets, %v, want %v", tt.name, len(got), err, tt.want)
+		}
+	}
+
+	all, _ := store.GetPerformedSets(SetFilter{}, 0, 0)
+	for i := 1; i < len(all); i++ {
+		if all[i].PerformedDate.After(all[i-1].PerformedDate) {
+			t.Fatalf("sets not newest first: %v after %v", all[i].PerformedDate, all[i-1].PerformedDate)
+		}
+	}
+
+	var paged []PerformedSet
+	for offset := 0; ; offset += 3 {
+		page, err := store.GetPerformedSets(SetFilter{}, 3, offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paged = append(paged, page...)
+		if len(page) < 3 {
+			break
+		}
+	}
+	if !slices.Equal(ids(paged), ids(all)) {
+		t.Errorf("pages %v, want %v", ids(paged), ids(all))
+	}
+}
+
+func TestUpdatePerformedSet(t *testing.T) {
+	store := NewInMemoryTrainingDB()
+	logSession(t, store, Session{StartedAt: day(0)}, sets(SQUAT, day(0), 5))
+
+	logged, _ := store.GetAllPerformedSets()
+	old := logged[0]
+	rir := 2
+	changed := old
+	changed.Reps, changed.Weight, changed.Unit, changed.Type, changed.RIR = 4, 102.5, "lb", SET_AMRAP, &rir
+	changed.PerformedDate = day(1)
+	if err := store.UpdatePerformedSet(changed); err != nil {
+		t.Fatal(err)
+	}
+
+	logged, _ = store.GetAllPerformedSets()
+	got := logged[0]
+	if got.Reps != 4 || got.Weight != 102.5 || got.Unit != "lb" || got.Type != SET_AMRAP || got.RIR == nil || *got.RIR != 2 || !got.PerformedDate.Equal(day(1)) {
+		t.Errorf("updated set = %+v", got)
+	}
+
+	if err := store.RestorePerformedSets([]PerformedSet{old}, nil); err != nil {
+		t.Fatal(err)
+	}
+	logged, _ = store.GetAllPerformedSets()
+	if logged[0].Reps != 5 || logged[0].Weight != 100 || logged[0].Type != SET_WORKING || logged[0].RIR != nil {
+		t.Errorf("restored set = %+v, want %+v", logged[0], old)
+	}
+}
+
+func TestDeleteAndRestorePerformedSets(t *testing.T) {
+	store := NewInMemoryTrainingDB()
+	session := logSession(t, store, Session{StartedAt: day(0), Notes: "gone"}, sets(SQUAT, day(0), 5, 5))
+	other := logSession(t, store, Session{StartedAt: day(1)}, sets(SQUAT, day(1), 5, 5))
+
+	logged, _ := store.GetPerformedSets(SetFilter{From: day(0), To: day(0)}, 0, 0)
+
+	// a session keeps its other sets
+	deleted, err := store.DeletePerformedSets([]uint{logged[0].ID})
+	if err != nil || len(deleted) != 0 {
+		t.Fatalf("deleting one set: %v, removed sessions %v", err, deleted)
+	}
+
+	// and goes with its last one
+	deleted, err = store.DeletePerformedSets([]uint{logged[1].ID})
+	if err != nil || len(deleted) != 1 || deleted[0].ID != session.ID {
+		t.Fatalf("deleting the last set: %v, removed sessions %v", err, deleted)
+	}
+	if left, _ := store.GetSessions([]uint{session.ID, other.ID}); len(left) != 1 {
+		t.Errorf("%v sessions left, want 1", len(left))
+	}
+
+	if err := store.RestorePerformedSets(logged, deleted); err != nil {
+		t.Fatal(err)
+	}
+	restored, _ := store.GetPerformedSets(SetFilter{From: day(0), To: day(0)}, 0, 0)
+	if !slices.Equal(ids(restored), ids(logged)) {
+		t.Errorf("restored %v, want %v", ids(restored), ids(logged))
+	}
+	if s, _ := store.GetSessions([]uint{session.ID}); len(s) != 1 || s[0].Notes != "gone" {
+		t.Errorf("restored session = %v", s)
+	}
+}
+
+func TestDeleteDay(t *testing.T) {
+	store := NewInMemoryTrainingDB()
+	morning := logSession(t, store, Session{StartedAt: day(0)}, sets(SQUAT, day(0).Add(-10*time.Hour), 5))
+	evening := logSession(t, store, Session{StartedAt: day(0)}, append(sets(SQUAT, day(0), 5), sets(BENCH, day(0), 8)...))
+	logSession(t, store, Session{StartedAt: day(1)}, sets(SQUAT, day(1), 5))
+
+	deleted, sessions, err := store.DeleteDay(day(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 3 || len(sessions) != 2 {
+		t.Errorf("deleted %v sets and %v sessions, want 3 and 2", len(deleted), len(sessions))
+	}
+	if left, _ := store.GetAllPerformedSets(); len(left) != 1 || !left[0].PerformedDate.Equal(day(1)) {
+		t.Errorf("left %v", left)
+	}
+
+	if err := store.RestorePerformedSets(deleted, sessions); err != nil {
+		t.Fatal(err)
+	}
+	if all, _ := store.GetAllPerformedSets(); len(all) != 4 {
+		t.Errorf("%v sets after restoring, want 4", len(all))
+	}
+	if s, _ := store.GetSessions([]uint{morning.ID, evening.ID}); len(s) != 2 {
+		t.Errorf("%v sessions after restoring, want 2", len(s))
+	}
+}
+
+func TestTransaction(t *testing.T) {
+	store := NewInMemoryTrainingDB()
+	failure := errors.New("failure")
+
+	err := store.Transaction(func(tx Store) error {
+		logSession(t, tx, Session{StartedAt: day(0)}, sets(SQUAT, day(0), 5))
+		if _, err := tx.CreateWorkout("Legs"); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Transaction = %v, want %v", err, failure)
+	}
+	if logged, _ := store.GetAllPerformedSets(); len(logged) != 0 {
+		t.Errorf("%v sets kept after rollback", len(logged))
+	}
+	if workouts, _ := store.GetAllWorkouts(); len(workouts) != 0 {
+		t.Errorf("%v workouts kept after rollback", len(workouts))
+	}
+
+	err = store.Transaction(func(tx Store) error {
+		logSession(t, tx, Session{StartedAt: day(0)}, sets(SQUAT, day(0), 5))
+		return nil
+	})
+	if logged, _ := store.GetAllPerformedSets(); err != nil || len(logged) != 1 {
+		t.Errorf("committed %v sets, %v", len(logged), err)
+	}
+}
+
+// program makes an active program of weeks, each with days workouts.
+func program(t *testing.T, store Store, weeks, days int) Program {
+	t.Helper()
+	p, err := store.CreateProgram("Test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range weeks {
+		workoutIDs := make([]uint, days)
+		for d := range workoutIDs {
+			w, err := store.CreateWorkout("Day")
+			if err != nil {
+				t.Fatal(err)
+			}
+			workoutIDs[d] = w.ID
+		}
+		if _, err := store.AddProgramWeek(p.ID, "", "", workoutIDs); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.ActivateProgram(p.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := store.GetProgram(p.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loaded
+}
+
+func TestAdvanceProgram(t *testing.T) {
+	store := NewInMemoryTrainingDB()
+	p := program(t, store, 2, 2)
+
+	tests := []struct {
+		week, day         int // day logged
+		wantWeek, wantDay int
+	}{
+		{0, 0, 0, 1},
+		{0, 1, 1, 0},
+		{1, 1, 0, 0}, // starts over after the last day
+		{1, 0, 1, 1}, // a day other than the position counts as well
+	}
+	for _, tt := range tests {
+		d := p.Weeks[tt.week].Days[tt.day]
+		logSession(t, store, Session{StartedAt: day(0), ProgramDayID: d.ID, WorkoutID: d.WorkoutID}, sets(SQUAT, day(0), 5))
+
+		active, err := store.GetActiveProgram()
+		if err != nil || active == nil {
+			t.Fatalf("GetActiveProgram = %v, %v", active, err)
+		}
+		if week, day := active.Position(); week != tt.wantWeek || day != tt.wantDay {
+			t.Errorf("after logging %v/%v the program is at %v/%v, want %v/%v", tt.week, tt.day, week, day, tt.wantWeek, tt.wantDay)
+		}
+	}
+
+	// nothing logged, nothing advanced
+	if err := store.SetProgramPosition(p.ID, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	logSession(t, store, Session{StartedAt: day(0), ProgramDayID: p.Weeks[0].Days[0].ID}, sets(SQUAT, day(0), 0))
+	if active, _ := store.GetActiveProgram(); active.Week != 0 || active.Day != 0 {
+		t.Errorf("empty session moved the program to %v/%v", active.Week, active.Day)
+	}
+}
+
+func TestProgramNext(t *testing.T) {
+	p := Program{Weeks: []ProgramWeek{
+		{Days: []ProgramDay{{ID: 1}, {ID: 2}}},
+		{},
+		{Days: []ProgramDay{{ID: 3}}},
+	}}
+
+	tests := []struct{ week, day, wantWeek, wantDay int }{
+		{0, 0, 0, 1},
+		{0, 1, 2, 0}, // the empty week is skipped
+		{2, 0, 0, 0},
+		{-1, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		if w, d := p.Next(tt.week, tt.day); w != tt.wantWeek || d != tt.wantDay {
+			t.Errorf("Next(%v, %v) = %v, %v, want %v, %v", tt.week, tt.day, w, d, tt.wantWeek, tt.wantDay)
+		}
+	}
+
+	if w, d := (Program{}).Next(0, 0); w != -1 || d != -1 {
+		t.Errorf("Next without days = %v, %v", w, d)
+	}
+	if w, d := (Program{Weeks: p.Weeks, Week: 5}).Position(); w != 0 || d != 0 {
+		t.Errorf("Position out of range = %v, %v", w, d)
+	}
+}
+
+func TestImportKeysAndAliases(t *testing.T) {
+	store := NewInMemoryTrainingDB()
+	s := sets(SQUAT, day(0), 5)
+	s[0].ImportKey = "strong:abc"
+	logSession(t, store, Session{StartedAt: day(0)}, append(s, sets(SQUAT, day(0), 5)...))
+
+	keys, err := store.GetImportKeys()
+	if err != nil || len(keys) != 1 || !keys["strong:abc"] {
+		t.Errorf("GetImportKeys = %v, %v", keys, err)
+	}
+
+	if err := store.SaveExerciseAlias("Squat (Barbell)", SQUAT); err != nil {
+		t.Fatal(err)
+	}
+	aliases, err := store.GetExerciseAliases()
+	if err != nil || aliases["squat (barbell)"] != SQUAT {
+		t.Errorf("GetExerciseAliases = %v, %v", aliases, err)
+	}
+}
@@ -0,0 +1,139 @@
+package export
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	CSV_PERFORMED_SETS = "performed_sets.csv"
+	CSV_SESSIONS       = "sessions.csv"
+	CSV_WORKOUTS       = "workouts.csv"
+	CSV_EXERCISES      = "exercises.csv"
+)
+
+// WriteCSV writes doc as one CSV file per section into dir, creating it if
+// needed. Existing files are overwritten.
+func WriteCSV(dir string, doc Document) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	performedSets := [][]string{{"date", "session_id", "exercise_id", "exercise_name", "workout_id", "workout_name", "set_no", "type", "reps", "weight", "unit", "rpe", "rir", "duration", "distance", "heart_rate", "calories"}}
+	for _, s := range doc.PerformedSets {
+		rir := ""
+		if s.RIR != nil {
+			rir = strconv.Itoa(*s.RIR)
+		}
+		performedSets = append(performedSets, []string{
+			s.Date.Format(time.RFC3339),
+			formatID(s.SessionID),
+			s.ExerciseID,
+			s.ExerciseName,
+			formatID(s.WorkoutID),
+			s.WorkoutName,
+			strconv.Itoa(s.SetNo),
+			s.Type,
+			strconv.Itoa(s.Reps),
+			formatFloat(s.Weight),
+			s.Unit,
+			formatOptional(s.RPE),
+			rir,
+			formatOptional(float64(s.Duration)),
+			formatOptional(s.Distance),
+			formatOptional(float64(s.HeartRate)),
+			formatOptional(float64(s.Calories)),
+		})
+	}
+
+	sessions := [][]string{{"id", "started_at", "ended_at", "workout_id", "workout_name", "notes", "bodyweight"}}
+	for _, s := range doc.Sessions {
+		sessions = append(sessions, []string{
+			formatID(s.ID),
+			s.StartedAt.Format(time.RFC3339),
+			s.EndedAt.Format(time.RFC3339),
+			formatID(s.WorkoutID),
+			s.WorkoutName,
+			s.Notes,
+			formatOptional(s.Bodyweight),
+		})
+	}
+
+	workouts := [][]string{{"workout_id", "workout_name", "exercise_id", "exercise_name", "note", "set_no", "type", "reps", "weight", "target_rpe", "duration", "distance"}}
+	for _, w := range doc.Workouts {
+		for _, we := range w.Exercises {
+			for i, s := range we.Sets {
+				workouts = append(workouts, []string{
+					strconv.FormatUint(uint64(w.ID), 10),
+					w.Name,
+					we.ExerciseID,
+					we.ExerciseName,
+					we.Note,
+					strconv.Itoa(i),
+					s.Type,
+					strconv.Itoa(s.Reps),
+					formatFloat(s.Weight),
+					formatOptional(s.TargetRPE),
+					formatOptional(float64(s.Duration)),
+					formatOptional(s.Distance),
+				})
+			}
+		}
+	}
+
+	exercises := [][]string{{"id", "data"}}
+	for _, e := range doc.Exercises {
+		exercises = append(exercises, []string{e.ID, string(e.Data)})
+	}
+
+	files := map[string][][]string{
+		CSV_PERFORMED_SETS: performedSets,
+		CSV_SESSIONS:       sessions,
+		CSV_WORKOUTS:       workouts,
+		CSV_EXERCISES:      exercises,
+	}
+	for name, records := range files {
+		if err := writeCSVFile(filepath.Join(dir, name), records); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeCSVFile(path string, records [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(records); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// formatID leaves a missing ID, 0, empty.
+func formatID(id uint) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// formatOptional leaves zero, i.e. unknown, values empty.
+func formatOptional(f float64) string {
+	if f == 0 {
+		return ""
+	}
+	return formatFloat(f)
+}
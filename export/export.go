@@ -0,0 +1,312 @@
+// Package export writes the training history out of the database.
+//
+// # JSON format
+//
+// A JSON export is a single object:
+//
+//	{
+//	  "format": "clift-export",
+//	  "version": 1,
+//	  "exported_at": "2025-01-31T18:00:00+01:00",
+//	  "performed_sets": [
+//	    {
+//	      "date": "2025-01-30T18:12:00+01:00",
+//	      "session_id": 12,           // see sessions, omitted for sets logged before sessions existed
+//	      "exercise_id": "Barbell_Squat",
+//	      "exercise_name": "Barbell Squat",
+//	      "workout_id": 1,            // omitted for sets logged outside a workout
+//	      "workout_name": "Legs",     // omitted as well
+//	      "set_no": 0,                // zero based position within the exercise
+//	      "type": "warmup",           // warmup, drop, amrap or failure, omitted for working sets
+//	      "reps": 5,
+//	      "weight": 100,              // kilograms, like all weights
+//	      "unit": "lb",               // unit the weight was entered in, if known
+//	      "rpe": 8.5,                 // if rated, or
+//	      "rir": 2,                   // reps in reserve
+//	      "duration": 1800,           // seconds, cardio and timed sets only
+//	      "distance": 5,              // kilometres, cardio only
+//	      "heart_rate": 145,          // average bpm, if known
+//	      "calories": 300             // if known
+//	    }
+//	  ],
+//	  "sessions": [
+//	    {
+//	      "id": 12,
+//	      "started_at": "2025-01-30T18:00:00+01:00",
+//	      "ended_at": "2025-01-30T19:05:00+01:00",
+//	      "workout_id": 1,            // template the session came from, omitted if none
+//	      "workout_name": "Legs",     // omitted as well
+//	      "notes": "knees felt good", // omitted if empty
+//	      "bodyweight": 82.5          // kilograms, omitted if not weighed
+//	    }
+//	  ],
+//	  "workouts": [
+//	    {
+//	      "id": 1,
+//	      "name": "Legs",
+//	      "exercises": [
+//	        {
+//	          "exercise_id": "Barbell_Squat",
+//	          "exercise_name": "Barbell Squat",
+//	          "note": "",
+//	          "sets": [{"reps": 5, "weight": 100, "target_rpe": 8}]  // or duration and distance
+//	        }
+//	      ]
+//	    }
+//	  ],
+//	  "exercises": [
+//	    {"id": "My_Exercise", "data": {"name": "My Exercise", ...}}
+//	  ]
+//	}
+//
+// "sessions" holds the sessions of the exported sets, i.e. what was trained
+// together. "exercises" only holds exercises added by the user; the ones
+// shipped with clift are referenced by ID. Dates are RFC 3339.
+//
+// # CSV format
+//
+// A CSV export is a folder with one file per section: performed_sets.csv,
+// sessions.csv, workouts.csv (one row per template set) and exercises.csv. Each file starts
+// with a header row naming the same fields as the JSON format.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+const (
+	FORMAT_NAME    = "clift-export"
+	FORMAT_VERSION = 1
+)
+
+type Document struct {
+	Format        string         `json:"format"`
+	Version       int            `json:"version"`
+	ExportedAt    time.Time      `json:"exported_at"`
+	PerformedSets []PerformedSet `json:"performed_sets"`
+	Sessions      []Session      `json:"sessions"`
+	Workouts      []Workout      `json:"workouts"`
+	Exercises     []Exercise     `json:"exercises"`
+}
+
+type PerformedSet struct {
+	Date         time.Time `json:"date"`
+	SessionID    uint      `json:"session_id,omitempty"`
+	ExerciseID   string    `json:"exercise_id"`
+	ExerciseName string    `json:"exercise_name"`
+	WorkoutID    uint      `json:"workout_id,omitempty"`
+	WorkoutName  string    `json:"workout_name,omitempty"`
+	SetNo        int       `json:"set_no"`
+	Type         string    `json:"type,omitempty"`
+	Reps         int       `json:"reps"`
+	Weight       float64   `json:"weight"`
+	Unit         string    `json:"unit,omitempty"`
+	RPE          float64   `json:"rpe,omitempty"`
+	RIR          *int      `json:"rir,omitempty"`
+	Duration     int       `json:"duration,omitempty"`
+	Distance     float64   `json:"distance,omitempty"`
+	HeartRate    int       `json:"heart_rate,omitempty"`
+	Calories     int       `json:"calories,omitempty"`
+}
+
+type Session struct {
+	ID          uint      `json:"id"`
+	StartedAt   time.Time `json:"started_at"`
+	EndedAt     time.Time `json:"ended_at"`
+	WorkoutID   uint      `json:"workout_id,omitempty"`
+	WorkoutName string    `json:"workout_name,omitempty"`
+	Notes       string    `json:"notes,omitempty"`
+	Bodyweight  float64   `json:"bodyweight,omitempty"`
+}
+
+type Workout struct {
+	ID        uint              `json:"id"`
+	Name      string            `json:"name"`
+	Exercises []WorkoutExercise `json:"exercises"`
+}
+
+type WorkoutExercise struct {
+	ExerciseID   string `json:"exercise_id"`
+	ExerciseName string `json:"exercise_name"`
+	Note         string `json:"note"`
+	Sets         []Set  `json:"sets"`
+}
+
+type Set struct {
+	Type      string  `json:"type,omitempty"`
+	Reps      int     `json:"reps"`
+	Weight    float64 `json:"weight"`
+	TargetRPE float64 `json:"target_rpe,omitempty"`
+	Duration  int     `json:"duration,omitempty"`
+	Distance  float64 `json:"distance,omitempty"`
+}
+
+type Exercise struct {
+	ID   string          `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Build collects the performed sets matching filter from store, along with the
+// workout templates and user exercises of the exercises it asks for.
+func Build(store wodb.Store, filter wodb.SetFilter) (Document, error) {
+	matchesExercise := func(id string) bool {
+		return len(filter.ExerciseIDs) == 0 || slices.Contains(filter.ExerciseIDs, id)
+	}
+
+	doc := Document{
+		Format:        FORMAT_NAME,
+		Version:       FORMAT_VERSION,
+		ExportedAt:    time.Now(),
+		PerformedSets: make([]PerformedSet, 0, 1000),
+		Sessions:      make([]Session, 0, 100),
+		Workouts:      make([]Workout, 0, 20),
+		Exercises:     make([]Exercise, 0),
+	}
+
+	exercises, err := store.GetAllExercises()
+	if err != nil {
+		return doc, err
+	}
+	names := make(map[string]string, len(exercises))
+	for _, e := range exercises {
+		names[e.ID] = e.GetName()
+		if !e.IsSeeded() && matchesExercise(e.ID) {
+			doc.Exercises = append(doc.Exercises, Exercise{ID: e.ID, Data: json.RawMessage(e.Data)})
+		}
+	}
+
+	workouts, err := store.GetAllWorkouts()
+	if err != nil {
+		return doc, err
+	}
+	workoutNames := make(map[uint]string, len(workouts))
+	for _, w := range workouts {
+		workoutNames[w.ID] = w.Name
+
+		ew := Workout{ID: w.ID, Name: w.Name, Exercises: make([]WorkoutExercise, 0, len(w.WorkoutExercises))}
+		for _, we := range w.WorkoutExercises {
+			if !matchesExercise(we.ExerciseID) {
+				continue
+			}
+			ewe := WorkoutExercise{
+				ExerciseID:   we.ExerciseID,
+				ExerciseName: we.Exercise.GetName(),
+				Note:         we.Note,
+				Sets:         make([]Set, len(we.Sets)),
+			}
+			for i, s := range we.Sets {
+				ewe.Sets[i] = Set{Type: s.Type, Reps: s.Reps, Weight: s.Weight, TargetRPE: s.TargetRPE, Duration: s.Duration, Distance: s.Distance}
+			}
+			ew.Exercises = append(ew.Exercises, ewe)
+		}
+
+		// with an exercise filter, workouts without any of them are noise
+		if len(filter.ExerciseIDs) > 0 && len(ew.Exercises) == 0 {
+			continue
+		}
+		doc.Workouts = append(doc.Workouts, ew)
+	}
+
+	performedSets, err := store.GetPerformedSets(filter, 0, 0)
+	if err != nil {
+		return doc, err
+	}
+	sessionIDs := make([]uint, 0, 100)
+	for _, s := range performedSets {
+		if s.SessionID != 0 && !slices.Contains(sessionIDs, s.SessionID) {
+			sessionIDs = append(sessionIDs, s.SessionID)
+		}
+		doc.PerformedSets = append(doc.PerformedSets, PerformedSet{
+			Date:         s.PerformedDate,
+			SessionID:    s.SessionID,
+			ExerciseID:   s.ExerciseID,
+			ExerciseName: names[s.ExerciseID],
+			WorkoutID:    s.WorkoutID,
+			WorkoutName:  workoutNames[s.WorkoutID],
+			SetNo:        s.SetNo,
+			Type:         s.Type,
+			Reps:         s.Reps,
+			Weight:       s.Weight,
+			Unit:         s.Unit,
+			RPE:          s.RPE,
+			RIR:          s.RIR,
+			Duration:     s.Duration,
+			Distance:     s.Distance,
+			HeartRate:    s.HeartRate,
+			Calories:     s.Calories,
+		})
+	}
+
+	sessions, err := store.GetSessions(sessionIDs)
+	if err != nil {
+		return doc, err
+	}
+	// newest first like the sets
+	slices.SortFunc(sessions, func(a, b wodb.Session) int { return b.StartedAt.Compare(a.StartedAt) })
+	for _, s := range sessions {
+		doc.Sessions = append(doc.Sessions, Session{
+			ID:          s.ID,
+			StartedAt:   s.StartedAt,
+			EndedAt:     s.EndedAt,
+			WorkoutID:   s.WorkoutID,
+			WorkoutName: workoutNames[s.WorkoutID],
+			Notes:       s.Notes,
+			Bodyweight:  s.Bodyweight,
+		})
+	}
+
+	return doc, nil
+}
+
+func WriteJSON(w io.Writer, doc Document) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// ReadJSON reads a document written by WriteJSON.
+func ReadJSON(r io.Reader) (Document, error) {
+	var doc Document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return doc, err
+	}
+	if doc.Format != FORMAT_NAME {
+		return doc, fmt.Errorf("not a clift export (format %q)", doc.Format)
+	}
+	if doc.Version > FORMAT_VERSION {
+		return doc, fmt.Errorf("export version %v is newer than supported version %v", doc.Version, FORMAT_VERSION)
+	}
+	return doc, nil
+}
+
+const (
+	JSON = "json"
+	CSV  = "csv"
+)
+
+// Save writes doc in the given format to path; a file for JSON, a folder for
+// CSV.
+func Save(doc Document, format, path string) error {
+	switch format {
+	case JSON:
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := WriteJSON(f, doc); err != nil {
+			return err
+		}
+		return f.Close()
+	case CSV:
+		return WriteCSV(path, doc)
+	}
+	return fmt.Errorf("unknown export format %q, use %v or %v", format, JSON, CSV)
+}
@@ -0,0 +1,168 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+// history logs a squat session and a bench session to a new store and adds a
+// workout template with both exercises.
+func history(t *testing.T) wodb.Store {
+	t.Helper()
+	store := wodb.NewInMemoryTrainingDB()
+	monday := time.Date(2025, 1, 6, 18, 0, 0, 0, time.Local)
+	wednesday := monday.AddDate(0, 0, 2)
+	rir := 2
+
+	squats := []wodb.PerformedSet{
+		{ExerciseID: "Barbell_Squat", PerformedDate: monday, SetNo: 0, Type: wodb.SET_WARMUP, Reps: 5, Weight: 60},
+		{ExerciseID: "Barbell_Squat", PerformedDate: monday, SetNo: 1, Reps: 5, Weight: 100, Unit: "lb", RIR: &rir},
+	}
+	if err := store.LogSession(&wodb.Session{StartedAt: monday, EndedAt: monday.Add(time.Hour), Notes: "heavy", Bodyweight: 82.5}, squats); err != nil {
+		t.Fatal(err)
+	}
+	bench := []wodb.PerformedSet{{ExerciseID: "Barbell_Bench_Press_-_Medium_Grip", PerformedDate: wednesday, Reps: 8, Weight: 60, RPE: 8.5}}
+	if err := store.LogSession(&wodb.Session{StartedAt: wednesday}, bench); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := store.CreateWorkout("Full Body")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"Barbell_Squat", "Barbell_Bench_Press_-_Medium_Grip"} {
+		we, err := store.AddExerciseToWorkout(w.ID, id, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.UpdateWorkoutExerciseSets(we.ID, []wodb.Set{{WorkoutExerciseID: we.ID, Reps: 5, Weight: 100, TargetRPE: 8}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func TestBuild(t *testing.T) {
+	store := history(t)
+
+	tests := []struct {
+		name     string
+		filter   wodb.SetFilter
+		sets     int
+		workouts int
+		first    string
+	}{
+		{"everything", wodb.SetFilter{}, 3, 1, "Barbell_Bench_Press_-_Medium_Grip"},
+		{"from wednesday", wodb.SetFilter{From: time.Date(2025, 1, 8, 0, 0, 0, 0, time.Local)}, 1, 1, "Barbell_Bench_Press_-_Medium_Grip"},
+		{"until monday", wodb.SetFilter{To: time.Date(2025, 1, 6, 0, 0, 0, 0, time.Local)}, 2, 1, "Barbell_Squat"},
+		{"squats", wodb.SetFilter{ExerciseIDs: []string{"Barbell_Squat"}}, 2, 1, "Barbell_Squat"},
+		{"deadlifts", wodb.SetFilter{ExerciseIDs: []string{"Barbell_Deadlift"}}, 0, 0, ""},
+	}
+	for _, tt := range tests {
+		doc, err := Build(store, tt.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(doc.PerformedSets) != tt.sets || len(doc.Workouts) != tt.workouts {
+			t.Errorf("%v: %v sets and %v workouts, want %v and %v", tt.name, len(doc.PerformedSets), len(doc.Workouts), tt.sets, tt.workouts)
+			continue
+		}
+		if tt.sets > 0 && doc.PerformedSets[0].ExerciseID != tt.first {
+			t.Errorf("%v: first set of %v, want %v", tt.name, doc.PerformedSets[0].ExerciseID, tt.first)
+		}
+	}
+
+	doc, _ := Build(store, wodb.SetFilter{ExerciseIDs: []string{"Barbell_Squat"}})
+	if exercises := doc.Workouts[0].Exercises; len(exercises) != 1 || exercises[0].ExerciseName != "Barbell Squat" {
+		t.Errorf("filtered workout = %+v", doc.Workouts[0])
+	}
+	if len(doc.Sessions) != 1 || doc.Sessions[0].Notes != "heavy" || doc.Sessions[0].Bodyweight != 82.5 {
+		t.Fatalf("sessions of the squats = %+v", doc.Sessions)
+	}
+	for _, s := range doc.PerformedSets {
+		if s.SessionID != doc.Sessions[0].ID {
+			t.Errorf("set in session %v, want %v", s.SessionID, doc.Sessions[0].ID)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	doc, err := Build(history(t), wodb.SetFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, doc); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !read.ExportedAt.Equal(doc.ExportedAt) {
+		t.Errorf("exported at %v, want %v", read.ExportedAt, doc.ExportedAt)
+	}
+	read.ExportedAt = doc.ExportedAt
+	for i := range read.PerformedSets {
+		if !read.PerformedSets[i].Date.Equal(doc.PerformedSets[i].Date) {
+			t.Errorf("set %v on %v, want %v", i, read.PerformedSets[i].Date, doc.PerformedSets[i].Date)
+		}
+		read.PerformedSets[i].Date = doc.PerformedSets[i].Date
+	}
+	for i := range read.Sessions {
+		if !read.Sessions[i].StartedAt.Equal(doc.Sessions[i].StartedAt) || !read.Sessions[i].EndedAt.Equal(doc.Sessions[i].EndedAt) {
+			t.Errorf("session %v = %+v, want %+v", i, read.Sessions[i], doc.Sessions[i])
+		}
+		read.Sessions[i].StartedAt, read.Sessions[i].EndedAt = doc.Sessions[i].StartedAt, doc.Sessions[i].EndedAt
+	}
+	if !reflect.DeepEqual(read, doc) {
+		t.Errorf("ReadJSON = %+v\nwant %+v", read, doc)
+	}
+
+	if _, err := ReadJSON(bytes.NewReader([]byte(`{"format": "strong"}`))); err == nil {
+		t.Error("read a document of another format")
+	}
+	if _, err := ReadJSON(bytes.NewReader([]byte(`{"format": "clift-export", "version": 99}`))); err == nil {
+		t.Error("read a document of a newer version")
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	doc, err := Build(history(t), wodb.SetFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(t.TempDir(), "export")
+	if err := Save(doc, CSV, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		file string
+		rows int
+	}{
+		{CSV_PERFORMED_SETS, 4},
+		{CSV_SESSIONS, 3},
+		{CSV_WORKOUTS, 3},
+		{CSV_EXERCISES, 1},
+	}
+	for _, tt := range tests {
+		f, err := os.Open(filepath.Join(dir, tt.file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, err := csv.NewReader(f).ReadAll()
+		f.Close()
+		if err != nil || len(rows) != tt.rows {
+			t.Errorf("%v: %v rows, %v, want %v", tt.file, len(rows), err, tt.rows)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/export"
+	"github.com/zmnpl/clift/notation"
+)
+
+// Strong:
+// Date,Workout Name,Duration,Exercise Name,Set Order,Weight,Reps,Distance,Seconds,Notes,Workout Notes,RPE
+// Older versions add "Weight Unit" and "Distance Unit" columns, some locales
+// separate by ";".
+func parseStrong(cols columns, record []string) (Row, bool, error) {
+	// rest timers show up as sets of their own
+	if strings.EqualFold(cols.get(record, "set order"), "rest timer") {
+		return Row{}, false, nil
+	}
+
+	distance, err := parseDistance(cols.get(record, "distance"), cols.get(record, "distance unit"))
+	if err != nil {
+		return Row{}, false, err
+	}
+	duration, err := parseSeconds(cols.get(record, "seconds"))
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	// cardio and timed sets come without reps
+	reps, ok := parseReps(cols.get(record, "reps"))
+	if !ok && distance <= 0 && duration <= 0 {
+		return Row{}, false, nil
+	}
+
+	date, err := parseTime(cols.get(record, "date"), "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02")
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	weight, err := parseWeight(cols.get(record, "weight"))
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	unit := ""
+	switch strings.ToLower(cols.get(record, "weight unit")) {
+	case "kg", "kgs":
+		unit = "kg"
+	case "lb", "lbs":
+		unit = "lb"
+	}
+
+	return Row{
+		Date:     date,
+		Stamp:    cols.get(record, "date"),
+		Workout:  cols.get(record, "workout name"),
+		Exercise: cols.get(record, "exercise name"),
+		Reps:     reps,
+		Weight:   weight,
+		Unit:     unit,
+		RPE:      parseRPE(cols.get(record, "rpe")),
+		Duration: duration,
+		Distance: distance,
+	}, true, nil
+}
+
+// Hevy:
+// "title","start_time","end_time","description","exercise_title","superset_id",
+// "exercise_notes","set_index","set_type","weight_kg","reps","distance_km",
+// "duration_seconds","rpe"
+// Accounts using pounds get "weight_lbs" instead of "weight_kg".
+func parseHevy(cols columns, record []string) (Row, bool, error) {
+	distance, err := parseDecimal(cols.get(record, "distance_km"))
+	if err != nil {
+		return Row{}, false, fmt.Errorf("invalid distance %q", cols.get(record, "distance_km"))
+	}
+	duration, err := parseDecimal(cols.get(record, "duration_seconds"))
+	if err != nil {
+		return Row{}, false, fmt.Errorf("invalid duration %q", cols.get(record, "duration_seconds"))
+	}
+
+	// cardio and timed sets come without reps
+	reps, ok := parseReps(cols.get(record, "reps"))
+	if !ok && distance <= 0 && duration <= 0 {
+		return Row{}, false, nil
+	}
+
+	date, err := parseTime(cols.get(record, "start_time"), "2 Jan 2006, 15:04", "2 Jan 2006 15:04", "2006-01-02 15:04:05", time.RFC3339)
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	unit := "kg"
+	weightText := cols.get(record, "weight_kg")
+	if cols.has("weight_lbs") {
+		unit = "lb"
+		weightText = cols.get(record, "weight_lbs")
+	}
+	weight, err := parseWeight(weightText)
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	return Row{
+		Date:     date,
+		Stamp:    cols.get(record, "start_time"),
+		Workout:  cols.get(record, "title"),
+		Exercise: cols.get(record, "exercise_title"),
+		Reps:     reps,
+		Weight:   weight,
+		Unit:     unit,
+		Type:     hevySetTypes[cols.get(record, "set_type")],
+		RPE:      parseRPE(cols.get(record, "rpe")),
+		Duration: int(duration),
+		Distance: distance,
+	}, true, nil
+}
+
+// hevySetTypes maps Hevy's set types, "normal" ones are working sets.
+var hevySetTypes = map[string]string{
+	"warmup":  wodb.SET_WARMUP,
+	"dropset": wodb.SET_DROP,
+	"failure": wodb.SET_FAILURE,
+}
+
+// FitNotes:
+// Date,Exercise,Category,Weight (kgs),Reps,Distance,Distance Unit,Time,Comment
+// or "Weight (lbs)" depending on the app settings. Time is h:mm:ss.
+func parseFitNotes(cols columns, record []string) (Row, bool, error) {
+	distance, err := parseDistance(cols.get(record, "distance"), cols.get(record, "distance unit"))
+	if err != nil {
+		return Row{}, false, err
+	}
+	duration, err := parseSeconds(cols.get(record, "time"))
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	// cardio and timed sets come without reps
+	reps, ok := parseReps(cols.get(record, "reps"))
+	if !ok && distance <= 0 && duration <= 0 {
+		return Row{}, false, nil
+	}
+
+	date, err := parseTime(cols.get(record, "date"), "2006-01-02")
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	unit := "kg"
+	weightText := cols.get(record, "weight (kgs)", "weight (kg)")
+	if cols.has("weight (lbs)") {
+		unit = "lb"
+		weightText = cols.get(record, "weight (lbs)")
+	}
+	weight, err := parseWeight(weightText)
+	if err != nil {
+		return Row{}, false, err
+	}
+
+	return Row{
+		Date:     date,
+		Stamp:    cols.get(record, "date"),
+		Exercise: cols.get(record, "exercise"),
+		Reps:     reps,
+		Weight:   weight,
+		Unit:     unit,
+		Duration: duration,
+		Distance: distance,
+	}, true, nil
+}
+
+// readClift reads clift's own JSON export. Exercise names are exercise IDs
+// there, so they always match.
+func readClift(r io.Reader) (Source, error) {
+	doc, err := export.ReadJSON(r)
+	if err != nil {
+		return Source{}, err
+	}
+
+	src := Source{
+		Rows:     make([]Row, 0, len(doc.PerformedSets)),
+		Sessions: make(map[uint]wodb.Session, len(doc.Sessions)),
+	}
+	for _, s := range doc.Sessions {
+		src.Sessions[s.ID] = wodb.Session{StartedAt: s.StartedAt, EndedAt: s.EndedAt, Notes: s.Notes, Bodyweight: s.Bodyweight}
+	}
+	for i, s := range doc.PerformedSets {
+		if s.Reps <= 0 && s.Duration <= 0 && s.Distance <= 0 {
+			src.Skipped++
+			continue
+		}
+		src.Rows = append(src.Rows, Row{
+			Line:      i + 1,
+			Date:      s.Date,
+			Stamp:     s.Date.Format(time.RFC3339Nano),
+			Workout:   s.WorkoutName,
+			Session:   s.SessionID,
+			Exercise:  s.ExerciseID,
+			Reps:      s.Reps,
+			Weight:    s.Weight,
+			Unit:      notation.KG,
+			Duration:  s.Duration,
+			Distance:  s.Distance,
+			HeartRate: s.HeartRate,
+			Calories:  s.Calories,
+			Type:      s.Type,
+			RPE:       s.RPE,
+			RIR:       s.RIR,
+		})
+	}
+	return src, nil
+}
+
+func parseReps(s string) (int, bool) {
+	reps, err := strconv.ParseFloat(s, 64)
+	if err != nil || reps <= 0 {
+		return 0, false
+	}
+	return int(reps), true
+}
+
+func parseWeight(s string) (float64, error) {
+	w, err := parseDecimal(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid weight %q", s)
+	}
+	return w, nil
+}
+
+// parseRPE reads an RPE, anything off the scale is dropped.
+func parseRPE(s string) float64 {
+	rpe, _, err := notation.ParseEffort(s)
+	if err != nil {
+		return 0
+	}
+	return rpe
+}
+
+// distanceUnits are the kilometres in a distance unit of the sources.
+var distanceUnits = map[string]float64{
+	"":      1,
+	"km":    1,
+	"m":     0.001,
+	"mi":    1.609344,
+	"miles": 1.609344,
+}
+
+// parseDistance reads an optional distance in unit as kilometres.
+func parseDistance(s, unit string) (float64, error) {
+	d, err := parseDecimal(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid distance %q", s)
+	}
+	factor, ok := distanceUnits[strings.ToLower(unit)]
+	if !ok {
+		return 0, fmt.Errorf("unknown distance unit %q", unit)
+	}
+	return d * factor, nil
+}
+
+// parseSeconds reads an optional duration as seconds ("90") or h:mm:ss.
+func parseSeconds(s string) (int, error) {
+	seconds, err := notation.ParseDuration(s, time.Second)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+	return seconds, nil
+}
+
+// parseDecimal reads an optional number, empty is 0.
+func parseDecimal(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	// some locales export decimal commas
+	return strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+}
+
+func parseTime(s string, layouts ...string) (time.Time, error) {
+	for _, l := range layouts {
+		if t, err := time.ParseInLocation(l, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
@@ -0,0 +1,393 @@
+// Package importer reads the CSV exports of other workout loggers (Strong,
+// Hevy and FitNotes) as well as clift's own JSON export and logs them as
+// performed sets.
+//
+// Importing is a two step process. Read parses a file into rows carrying the
+// exercise names of the source app. Those names are resolved to exercise IDs
+// by a Matcher; names it cannot resolve have to be mapped by the caller, for
+// example after asking the user to pick one of Matcher.Candidates. Apply then
+// logs the rows.
+//
+// Every imported set carries a key derived from its source row. Rows whose
+// key is already in the database are skipped, so importing the same file (or
+// a newer export of the same app) again only adds what is new. Sets of clift's
+// own export are also skipped if the very same set is in the database, which
+// it is when importing an export back into the database it came from.
+//
+// Imported sets are grouped into sessions like logged ones, one per workout
+// of the source, or per day if the source doesn't have workouts. clift's own
+// export brings its sessions along, with their notes and bodyweight.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/notation"
+)
+
+const (
+	STRONG   = "strong"
+	HEVY     = "hevy"
+	FITNOTES = "fitnotes"
+	CLIFT    = "clift"
+)
+
+var Formats = []string{STRONG, HEVY, FITNOTES, CLIFT}
+
+// Row is one set as found in the source file.
+type Row struct {
+	Line     int
+	Date     time.Time
+	Stamp    string // date and time as written in the source
+	Workout  string
+	Session  uint   // ID of the session in the source, clift exports only
+	Exercise string // name as used by the source app
+	SetNo    int
+	Reps     int
+	Weight   float64
+	Unit     string // "kg", "lb" or "" if the source doesn't say
+	Type     string // see wodb.SetTypes
+	RPE      float64
+	RIR      *int
+
+	// cardio and timed sets
+	Duration  int     // seconds
+	Distance  float64 // kilometres
+	HeartRate int
+	Calories  int
+}
+
+type Source struct {
+	Format string
+	Rows   []Row
+	// Sessions are the sessions of the source by their ID, if it has any.
+	Sessions map[uint]wodb.Session
+	// Skipped counts rows that can't be stored as sets, e.g. rest timer
+	// entries.
+	Skipped int
+}
+
+type Result struct {
+	Imported   int
+	Duplicates int
+	Unmapped   int
+}
+
+// Read parses r. With an empty format it is detected from the content.
+func Read(r io.Reader, format string) (Source, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Source{}, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // utf-8 byte order mark
+
+	if format == "" {
+		format, err = Detect(data)
+		if err != nil {
+			return Source{}, err
+		}
+	}
+
+	var src Source
+	switch format {
+	case CLIFT:
+		src, err = readClift(bytes.NewReader(data))
+	case STRONG, HEVY, FITNOTES:
+		src, err = readCSV(data, format)
+	default:
+		return Source{}, fmt.Errorf("unknown import format %q", format)
+	}
+	if err != nil {
+		return src, err
+	}
+
+	src.Format = format
+	numberSets(src.Rows)
+	return src, nil
+}
+
+// Detect guesses the format from the header line.
+func Detect(data []byte) (string, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return CLIFT, nil
+	}
+
+	header, _, _ := strings.Cut(string(data), "\n")
+	header = strings.ToLower(header)
+	switch {
+	case strings.Contains(header, "exercise_title") && strings.Contains(header, "set_index"):
+		return HEVY, nil
+	case strings.Contains(header, "exercise name") && strings.Contains(header, "set order"):
+		return STRONG, nil
+	case strings.Contains(header, "exercise") && strings.Contains(header, "category") && strings.Contains(header, "weight ("):
+		return FITNOTES, nil
+	}
+	return "", fmt.Errorf("cannot detect the format, pass one of %v", strings.Join(Formats, ", "))
+}
+
+// columns maps lower case header names to their index.
+type columns map[string]int
+
+func (c columns) get(record []string, names ...string) string {
+	for _, n := range names {
+		if i, ok := c[n]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+	}
+	return ""
+}
+
+func (c columns) has(name string) bool {
+	_, ok := c[name]
+	return ok
+}
+
+func readCSV(data []byte, format string) (Source, error) {
+	src := Source{Rows: make([]Row, 0, 1000)}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.Comma = detectDelimiter(data)
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return src, err
+	}
+	if len(records) == 0 {
+		return src, fmt.Errorf("file is empty")
+	}
+
+	cols := make(columns, len(records[0]))
+	for i, h := range records[0] {
+		cols[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	parse := map[string]func(columns, []string) (Row, bool, error){
+		STRONG:   parseStrong,
+		HEVY:     parseHevy,
+		FITNOTES: parseFitNotes,
+	}[format]
+
+	for i, record := range records[1:] {
+		row, ok, err := parse(cols, record)
+		if err != nil {
+			return src, fmt.Errorf("line %v: %v", i+2, err)
+		}
+		if !ok {
+			src.Skipped++
+			continue
+		}
+		row.Line = i + 2
+		src.Rows = append(src.Rows, row)
+	}
+
+	return src, nil
+}
+
+// detectDelimiter tells comma from semicolon separated files by their header.
+func detectDelimiter(data []byte) rune {
+	header, _, _ := bufio.NewReader(bytes.NewReader(data)).ReadLine()
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+// numberSets assigns set numbers by order of appearance per day and exercise.
+// Source files number sets in different ways, some not at all.
+func numberSets(rows []Row) {
+	counter := make(map[string]int)
+	for i := range rows {
+		k := rows[i].Date.Format("2006-01-02") + "|" + strings.ToLower(rows[i].Exercise)
+		rows[i].SetNo = counter[k]
+		counter[k]++
+	}
+}
+
+// Key identifies a row independently of how its exercise gets mapped. It is
+// made of the timestamp as written in the source, so it stays the same when
+// the file is imported again in another time zone.
+func (r Row) Key(format string) string {
+	stamp := r.Stamp
+	if stamp == "" {
+		stamp = r.Date.Format(time.DateTime)
+	}
+	h := sha1.Sum([]byte(fmt.Sprintf("%v|%v|%v|%v|%v|%v|%v|%v",
+		format,
+		stamp,
+		strings.ToLower(r.Exercise),
+		r.SetNo,
+		r.Reps,
+		r.Weight,
+		r.Duration,
+		r.Distance)))
+	return format + ":" + hex.EncodeToString(h[:12])
+}
+
+// Names returns the distinct exercise names of src in order of appearance.
+func (src Source) Names() []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0, 50)
+	for _, r := range src.Rows {
+		if !seen[strings.ToLower(r.Exercise)] {
+			seen[strings.ToLower(r.Exercise)] = true
+			names = append(names, r.Exercise)
+		}
+	}
+	return names
+}
+
+// Apply logs all rows of src. mapping resolves source exercise names (lower
+// case) to exercise IDs; rows of unmapped names are left out. Weights are
+// stored in kilograms, those of sources not stating their unit count in unit.
+// Either all new sets are logged or, on an error, none.
+func Apply(store wodb.Store, src Source, mapping map[string]string, unit string) (Result, error) {
+	result := Result{}
+
+	existing, err := store.GetImportKeys()
+	if err != nil {
+		return result, err
+	}
+	var logged map[string]int
+	if src.Format == CLIFT {
+		if logged, err = loggedSets(store); err != nil {
+			return result, err
+		}
+	}
+
+	sessions := make([]*session, 0, 100)
+	byWorkout := make(map[string]*session)
+	for _, r := range src.Rows {
+		exerciseID, ok := mapping[strings.ToLower(r.Exercise)]
+		if !ok {
+			result.Unmapped++
+			continue
+		}
+
+		key := r.Key(src.Format)
+		if existing[key] {
+			result.Duplicates++
+			continue
+		}
+		existing[key] = true
+
+		set := wodb.PerformedSet{
+			ExerciseID:    exerciseID,
+			PerformedDate: r.Date,
+			SetNo:         r.SetNo,
+			Reps:          r.Reps,
+			Weight:        notation.ToKg(r.Weight, r.unitOr(unit)),
+			Unit:          r.unitOr(unit),
+			Duration:      r.Duration,
+			Distance:      r.Distance,
+			HeartRate:     r.HeartRate,
+			Calories:      r.Calories,
+			Type:          r.Type,
+			RPE:           r.RPE,
+			RIR:           r.RIR,
+			ImportKey:     key,
+		}
+		if logged[sameSet(set)] > 0 {
+			logged[sameSet(set)]--
+			result.Duplicates++
+			continue
+		}
+
+		workout := r.workout(src.Format)
+		s, ok := byWorkout[workout]
+		if !ok {
+			s = &session{Session: wodb.Session{StartedAt: r.Date, EndedAt: r.Date}}
+			if exported, ok := src.Sessions[r.Session]; ok {
+				s.StartedAt, s.EndedAt = exported.StartedAt, exported.EndedAt
+				s.Notes, s.Bodyweight = exported.Notes, exported.Bodyweight
+			}
+			byWorkout[workout] = s
+			sessions = append(sessions, s)
+		}
+		s.StartedAt = minTime(s.StartedAt, r.Date)
+		s.EndedAt = maxTime(s.EndedAt, r.Date)
+		s.sets = append(s.sets, set)
+		result.Imported++
+	}
+
+	err = store.Transaction(func(tx wodb.Store) error {
+		for _, s := range sessions {
+			if err := tx.LogSession(&s.Session, s.sets); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
+
+// session is a session to be logged with its sets.
+type session struct {
+	wodb.Session
+	sets []wodb.PerformedSet
+}
+
+// workout tells which rows were done together. clift exports name the
+// session, Strong and Hevy share the start time between the sets of a workout,
+// FitNotes only has the day.
+func (r Row) workout(format string) string {
+	if r.Session != 0 {
+		return fmt.Sprintf("session|%v", r.Session)
+	}
+	if format == FITNOTES {
+		return r.Date.Format(time.DateOnly)
+	}
+	return r.Date.Format(time.RFC3339Nano) + "|" + r.Workout
+}
+
+// loggedSets counts the sets in store by sameSet.
+func loggedSets(store wodb.Store) (map[string]int, error) {
+	sets, err := store.GetAllPerformedSets()
+	if err != nil {
+		return nil, err
+	}
+	logged := make(map[string]int, len(sets))
+	for _, s := range sets {
+		logged[sameSet(s)]++
+	}
+	return logged, nil
+}
+
+// sameSet is equal for sets that only differ in where they are stored.
+func sameSet(s wodb.PerformedSet) string {
+	return fmt.Sprintf("%v|%v|%v|%v|%v|%v|%v", s.ExerciseID, s.PerformedDate.Unix(), s.Type, s.Reps, s.Weight, s.Duration, s.Distance)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// unitOr is the unit of the row, unit if the source doesn't say.
+func (r Row) unitOr(unit string) string {
+	if r.Unit != "" {
+		return r.Unit
+	}
+	return unit
+}
@@ -0,0 +1,348 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/export"
+)
+
+const strongCSV = `Date,Workout Name,Duration,Exercise Name,Set Order,Weight,Reps,Distance,Seconds,Notes,Workout Notes,RPE
+2025-01-06 18:00:00,Legs,1h,Squat (Barbell),1,100,5,0,0,,,8
+2025-01-06 18:00:00,Legs,1h,Squat (Barbell),2,100,5,0,0,,,
+2025-01-06 18:00:00,Legs,1h,Squat (Barbell),Rest Timer,0,0,0,90,,,
+2025-01-08 07:00:00,Push,45m,Bench Press (Barbell),1,60,8,0,0,,,
+`
+
+const hevyCSV = `"title","start_time","end_time","description","exercise_title","superset_id","exercise_notes","set_index","set_type","weight_lbs","reps","distance_km","duration_seconds","rpe"
+"Legs","6 Jan 2025, 18:00","6 Jan 2025, 19:00","","Squat (Barbell)","","","0","warmup","135","10","","",""
+"Legs","6 Jan 2025, 18:00","6 Jan 2025, 19:00","","Squat (Barbell)","","","1","normal","225","5","","","8.5"
+"Run","7 Jan 2025, 07:00","7 Jan 2025, 07:30","","Running","","","0","normal","","","5","1800",""
+`
+
+const fitNotesCSV = `Date;Exercise;Category;Weight (kgs);Reps;Distance;Distance Unit;Time;Comment
+2025-01-06;Squat;Legs;100,0;5;;;;
+2025-01-06;Squat;Legs;102,5;3;;;;
+2025-01-07;Deadlift;Legs;140,0;5;;;;
+`
+
+func read(t *testing.T, data, format string) Source {
+	t.Helper()
+	src, err := Read(strings.NewReader(data), format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
+
+// inLocation runs f with the local time zone set to name.
+func inLocation(t *testing.T, name string, f func()) {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skip(err)
+	}
+	local := time.Local
+	time.Local = loc
+	defer func() { time.Local = local }()
+	f()
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		data, want string
+	}{
+		{strongCSV, STRONG},
+		{hevyCSV, HEVY},
+		{fitNotesCSV, FITNOTES},
+		{` {"format": "clift-export"}`, CLIFT},
+	}
+	for _, tt := range tests {
+		if got, err := Detect([]byte(tt.data)); err != nil || got != tt.want {
+			t.Errorf("Detect = %v, %v, want %v", got, err, tt.want)
+		}
+	}
+	if _, err := Detect([]byte("a,b,c\n1,2,3")); err == nil {
+		t.Error("detected a format of an unknown file")
+	}
+}
+
+func TestRead(t *testing.T) {
+	strong := read(t, "\xef\xbb\xbf"+strongCSV, "")
+	if strong.Format != STRONG || len(strong.Rows) != 3 || strong.Skipped != 1 {
+		t.Fatalf("Strong: %v rows, %v skipped", len(strong.Rows), strong.Skipped)
+	}
+	if r := strong.Rows[1]; r.Exercise != "Squat (Barbell)" || r.SetNo != 1 || r.Reps != 5 || r.Weight != 100 || r.Workout != "Legs" || r.Stamp != "2025-01-06 18:00:00" {
+		t.Errorf("Strong row = %+v", r)
+	}
+	if strong.Rows[0].RPE != 8 {
+		t.Errorf("Strong RPE = %v", strong.Rows[0].RPE)
+	}
+
+	hevy := read(t, hevyCSV, "")
+	if len(hevy.Rows) != 3 {
+		t.Fatalf("Hevy: %v rows", len(hevy.Rows))
+	}
+	if r := hevy.Rows[0]; r.Type != wodb.SET_WARMUP || r.Unit != "lb" || r.Weight != 135 {
+		t.Errorf("Hevy warm-up = %+v", r)
+	}
+	if r := hevy.Rows[1]; r.Type != wodb.SET_WORKING || r.RPE != 8.5 || r.SetNo != 1 {
+		t.Errorf("Hevy working set = %+v", r)
+	}
+	if r := hevy.Rows[2]; r.Distance != 5 || r.Duration != 1800 || r.Reps != 0 {
+		t.Errorf("Hevy run = %+v", r)
+	}
+
+	fitNotes := read(t, fitNotesCSV, "")
+	if len(fitNotes.Rows) != 3 || fitNotes.Rows[1].Weight != 102.5 || fitNotes.Rows[1].Unit != "kg" {
+		t.Errorf("FitNotes rows = %+v", fitNotes.Rows)
+	}
+	if names := fitNotes.Names(); len(names) != 2 || names[0] != "Squat" || names[1] != "Deadlift" {
+		t.Errorf("Names = %v", names)
+	}
+
+	if _, err := Read(strings.NewReader(strongCSV), "endomondo"); err == nil {
+		t.Error("read an unknown format")
+	}
+	bad := strings.Replace(strongCSV, "60,8", "sixty,8", 1)
+	if _, err := Read(strings.NewReader(bad), STRONG); err == nil || !strings.Contains(err.Error(), "line 5") {
+		t.Errorf("bad weight: %v", err)
+	}
+}
+
+func TestReadCardio(t *testing.T) {
+	strong := read(t, `Date,Workout Name,Duration,Exercise Name,Set Order,Weight,Reps,Distance,Seconds,Notes,Workout Notes,RPE
+2025-01-07 07:00:00,Run,30m,Running (Outdoor),1,0,0,5.2,1800,,,
+2025-01-07 07:00:00,Run,30m,Plank,2,0,,,60,,,
+2025-01-07 07:00:00,Run,30m,Plank,3,0,,,,,,
+`, STRONG)
+	if len(strong.Rows) != 2 || strong.Skipped != 1 {
+		t.Fatalf("Strong: %v rows, %v skipped", len(strong.Rows), strong.Skipped)
+	}
+	if r := strong.Rows[0]; r.Reps != 0 || r.Distance != 5.2 || r.Duration != 1800 {
+		t.Errorf("Strong run = %+v", r)
+	}
+	if r := strong.Rows[1]; r.Duration != 60 || r.Distance != 0 {
+		t.Errorf("Strong plank = %+v", r)
+	}
+
+	fitNotes := read(t, `Date,Exercise,Category,Weight (kgs),Reps,Distance,Distance Unit,Time,Comment
+2025-01-07,Running,Cardio,,,3.1,mi,0:25:30,
+2025-01-07,Rowing,Cardio,,,2000,m,,
+2025-01-07,Plank,Core,,,,,1:00,
+2025-01-07,Plank,Core,,,,,,
+`, FITNOTES)
+	if len(fitNotes.Rows) != 3 || fitNotes.Skipped != 1 {
+		t.Fatalf("FitNotes: %v rows, %v skipped", len(fitNotes.Rows), fitNotes.Skipped)
+	}
+	if r := fitNotes.Rows[0]; r.Duration != 1530 || r.Distance < 4.98 || r.Distance > 4.99 {
+		t.Errorf("FitNotes run = %+v", r)
+	}
+	if r := fitNotes.Rows[1]; r.Distance != 2 {
+		t.Errorf("FitNotes row = %+v", r)
+	}
+	if r := fitNotes.Rows[2]; r.Duration != 60 {
+		t.Errorf("FitNotes plank = %+v", r)
+	}
+
+	if _, err := Read(strings.NewReader("Date,Exercise,Category,Weight (kgs),Reps,Distance,Distance Unit,Time,Comment\n2025-01-07,Running,Cardio,,,3,furlong,,\n"), FITNOTES); err == nil {
+		t.Error("read a distance in an unknown unit")
+	}
+}
+
+func TestKey(t *testing.T) {
+	var utc, ny []string
+	inLocation(t, "UTC", func() {
+		for _, r := range read(t, strongCSV, STRONG).Rows {
+			utc = append(utc, r.Key(STRONG))
+		}
+	})
+	inLocation(t, "America/New_York", func() {
+		for _, r := range read(t, strongCSV, STRONG).Rows {
+			ny = append(ny, r.Key(STRONG))
+		}
+	})
+	for i := range utc {
+		if utc[i] != ny[i] {
+			t.Errorf("key of row %v changed with the time zone: %v, %v", i, utc[i], ny[i])
+		}
+	}
+
+	rows := read(t, strongCSV, STRONG).Rows
+	if rows[0].Key(STRONG) == rows[1].Key(STRONG) {
+		t.Error("two sets share a key")
+	}
+	if !strings.HasPrefix(rows[0].Key(STRONG), STRONG+":") {
+		t.Errorf("Key = %v", rows[0].Key(STRONG))
+	}
+
+	run := Row{Stamp: "2025-01-07 07:00", Exercise: "Running", Duration: 1800, Distance: 5}
+	longer, further := run, run
+	longer.Duration, further.Distance = 2400, 6
+	if run.Key(HEVY) == longer.Key(HEVY) || run.Key(HEVY) == further.Key(HEVY) {
+		t.Error("key ignores duration or distance")
+	}
+}
+
+func TestApply(t *testing.T) {
+	store := wodb.NewInMemoryTrainingDB()
+	src := read(t, strongCSV, STRONG)
+
+	matcher, err := NewMatcher(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapping, unknown := matcher.Mapping(src)
+	if len(unknown) != 0 {
+		t.Fatalf("unknown names %v", unknown)
+	}
+
+	result, err := Apply(store, src, mapping, "lb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (Result{Imported: 3}) {
+		t.Errorf("Apply = %+v", result)
+	}
+
+	sets, err := store.GetAllPerformedSets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := make(map[uint]int)
+	for _, s := range sets {
+		if s.SessionID == 0 {
+			t.Errorf("set %v has no session", s.ID)
+		}
+		sessions[s.SessionID]++
+		if s.Unit != "lb" || s.ImportKey == "" {
+			t.Errorf("set %+v", s)
+		}
+	}
+	if len(sessions) != 2 {
+		t.Errorf("%v sessions, want 2", len(sessions))
+	}
+
+	again, err := Apply(store, src, mapping, "lb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again != (Result{Duplicates: 3}) {
+		t.Errorf("Apply again = %+v", again)
+	}
+
+	delete(mapping, "bench press (barbell)")
+	unmapped, err := Apply(wodb.NewInMemoryTrainingDB(), src, mapping, "kg")
+	if err != nil || unmapped != (Result{Imported: 2, Unmapped: 1}) {
+		t.Errorf("Apply without mapping = %+v, %v", unmapped, err)
+	}
+}
+
+func TestApplyFitNotesByDay(t *testing.T) {
+	store := wodb.NewInMemoryTrainingDB()
+	src := read(t, fitNotesCSV, FITNOTES)
+	mapping := map[string]string{"squat": "Barbell_Squat", "deadlift": "Barbell_Deadlift"}
+
+	if _, err := Apply(store, src, mapping, "kg"); err != nil {
+		t.Fatal(err)
+	}
+	sets, _ := store.GetAllPerformedSets()
+	sessions := make(map[uint]bool)
+	for _, s := range sets {
+		sessions[s.SessionID] = true
+	}
+	if len(sets) != 3 || len(sessions) != 2 {
+		t.Errorf("%v sets in %v sessions, want 3 in 2", len(sets), len(sessions))
+	}
+}
+
+func TestApplyOwnExport(t *testing.T) {
+	store := wodb.NewInMemoryTrainingDB()
+	date := time.Date(2025, 1, 6, 18, 0, 0, 0, time.Local)
+	logged := []wodb.PerformedSet{
+		{ExerciseID: "Barbell_Squat", PerformedDate: date, SetNo: 0, Reps: 5, Weight: 100},
+		{ExerciseID: "Barbell_Squat", PerformedDate: date, SetNo: 1, Reps: 5, Weight: 100},
+	}
+	if err := store.LogSession(&wodb.Session{StartedAt: date, EndedAt: date.Add(time.Hour), Notes: "heavy", Bodyweight: 82.5}, logged); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := export.Build(store, wodb.SetFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := export.WriteJSON(&buf, doc); err != nil {
+		t.Fatal(err)
+	}
+	src, err := Read(&buf, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matcher, err := NewMatcher(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapping, unknown := matcher.Mapping(src)
+	if len(unknown) != 0 {
+		t.Fatalf("own exercise IDs unknown: %v", unknown)
+	}
+
+	result, err := Apply(store, src, mapping, "kg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (Result{Duplicates: 2}) {
+		t.Errorf("importing the export into its own database = %+v", result)
+	}
+
+	fresh := wodb.NewInMemoryTrainingDB()
+	if result, err := Apply(fresh, src, mapping, "kg"); err != nil || result != (Result{Imported: 2}) {
+		t.Errorf("importing the export into a new database = %+v, %v", result, err)
+	}
+	sets, _ := fresh.GetAllPerformedSets()
+	sessions, err := fresh.GetSessions([]uint{sets[0].SessionID})
+	if err != nil || len(sessions) != 1 {
+		t.Fatalf("sessions = %+v, %v", sessions, err)
+	}
+	if s := sessions[0]; s.Notes != "heavy" || s.Bodyweight != 82.5 || !s.EndedAt.Equal(date.Add(time.Hour)) {
+		t.Errorf("imported session = %+v", s)
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	store := wodb.NewInMemoryTrainingDB()
+	if err := store.SaveExerciseAlias("Back Squat (Safety Bar)", "Barbell_Squat"); err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewMatcher(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, want string
+	}{
+		{"Barbell Squat", "Barbell_Squat"},
+		{"Squat (Barbell)", "Barbell_Squat"},
+		{"back squat (safety bar)", "Barbell_Squat"},
+		{"Pull Up", "Pullups"},
+		{"Deadlift", "Barbell_Deadlift"},
+	}
+	for _, tt := range tests {
+		if got, ok := m.Match(tt.name); !ok || got != tt.want {
+			t.Errorf("Match(%q) = %v, %v, want %v", tt.name, got, ok, tt.want)
+		}
+	}
+	if got, ok := m.Match("Underwater Basket Weaving"); ok {
+		t.Errorf("matched nonsense to %v", got)
+	}
+
+	candidates := m.Candidates("Barbel Squats", 3)
+	if len(candidates) != 3 || candidates[0].ID != "Barbell_Squat" {
+		t.Errorf("Candidates = %v", candidates)
+	}
+}
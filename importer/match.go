@@ -0,0 +1,206 @@
+package importer
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+// common names of other apps that don't match any of our exercise names
+var builtinAliases = map[string]string{
+	"Bench Press (Barbell)":          "Barbell_Bench_Press_-_Medium_Grip",
+	"Bench Press":                    "Barbell_Bench_Press_-_Medium_Grip",
+	"Flat Barbell Bench Press":       "Barbell_Bench_Press_-_Medium_Grip",
+	"Incline Bench Press (Barbell)":  "Barbell_Incline_Bench_Press_-_Medium_Grip",
+	"Incline Barbell Bench Press":    "Barbell_Incline_Bench_Press_-_Medium_Grip",
+	"Bench Press (Dumbbell)":         "Dumbbell_Bench_Press",
+	"Incline Bench Press (Dumbbell)": "Incline_Dumbbell_Press",
+	"Squat (Barbell)":                "Barbell_Squat",
+	"Squat":                          "Barbell_Squat",
+	"Front Squat (Barbell)":          "Front_Barbell_Squat",
+	"Deadlift (Barbell)":             "Barbell_Deadlift",
+	"Deadlift":                       "Barbell_Deadlift",
+	"Romanian Deadlift (Barbell)":    "Romanian_Deadlift",
+	"Sumo Deadlift (Barbell)":        "Sumo_Deadlift",
+	"Overhead Press (Barbell)":       "Standing_Military_Press",
+	"Overhead Press":                 "Standing_Military_Press",
+	"Shoulder Press (Dumbbell)":      "Dumbbell_Shoulder_Press",
+	"Bent Over Row (Barbell)":        "Bent_Over_Barbell_Row",
+	"Barbell Row":                    "Bent_Over_Barbell_Row",
+	"Dumbbell Row":                   "One-Arm_Dumbbell_Row",
+	"Hip Thrust (Barbell)":           "Barbell_Hip_Thrust",
+	"Pull Up":                        "Pullups",
+	"Chin Up":                        "Chin-Up",
+	"Push Up":                        "Pushups",
+	"Dip":                            "Dips_-_Triceps_Version",
+	"Triceps Dip":                    "Dips_-_Triceps_Version",
+	"Chest Dip":                      "Dips_-_Chest_Version",
+	"Lat Pulldown (Cable)":           "Wide-Grip_Lat_Pulldown",
+	"Lat Pulldown":                   "Wide-Grip_Lat_Pulldown",
+	"Seated Row (Cable)":             "Seated_Cable_Rows",
+	"Seated Cable Row":               "Seated_Cable_Rows",
+	"Leg Extension (Machine)":        "Leg_Extensions",
+	"Lying Leg Curl (Machine)":       "Lying_Leg_Curls",
+	"Bicep Curl (Barbell)":           "Barbell_Curl",
+	"Bicep Curl (Dumbbell)":          "Dumbbell_Bicep_Curl",
+	"Hammer Curl (Dumbbell)":         "Hammer_Curls",
+	"Triceps Pushdown (Cable)":       "Triceps_Pushdown",
+	"Lateral Raise (Dumbbell)":       "Side_Lateral_Raise",
+	"Face Pull (Cable)":              "Face_Pull",
+	"Shrug (Barbell)":                "Barbell_Shrug",
+	"Shrug (Dumbbell)":               "Dumbbell_Shrug",
+	"Standing Calf Raise":            "Standing_Calf_Raises",
+	"Seated Calf Raise (Machine)":    "Seated_Calf_Raise",
+	"Lunge (Dumbbell)":               "Dumbbell_Lunges",
+	"Bulgarian Split Squat":          "Split_Squat_with_Dumbbells",
+	"Good Morning (Barbell)":         "Good_Morning",
+	"Skullcrusher (Barbell)":         "EZ-Bar_Skullcrusher",
+}
+
+type Candidate struct {
+	ID    string
+	Name  string
+	Score float64 // 0..1, higher is better
+}
+
+// Matcher resolves exercise names of other apps to our exercise IDs.
+type Matcher struct {
+	exercises []wodb.Exercise
+	aliases   map[string]string // lower case name -> id, remembered choices
+	byKey     map[string]string // compact key -> id
+}
+
+func NewMatcher(store wodb.Store) (*Matcher, error) {
+	exercises, err := store.GetAllExercises()
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := store.GetExerciseAliases()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Matcher{
+		exercises: exercises,
+		aliases:   aliases,
+		byKey:     make(map[string]string, len(exercises)+len(builtinAliases)),
+	}
+
+	known := make(map[string]bool, len(exercises))
+	for _, e := range exercises {
+		known[e.ID] = true
+		m.byKey[compactKey(e.GetName())] = e.ID
+	}
+	for name, id := range builtinAliases {
+		k := compactKey(name)
+		if _, taken := m.byKey[k]; !taken && known[id] {
+			m.byKey[k] = id
+		}
+	}
+
+	return m, nil
+}
+
+// Match resolves name without asking anybody.
+func (m *Matcher) Match(name string) (string, bool) {
+	if id, ok := m.aliases[strings.ToLower(name)]; ok {
+		return id, true
+	}
+	if e, ok := wodb.FindExercise(m.exercises, name); ok {
+		return e.ID, true
+	}
+	id, ok := m.byKey[compactKey(name)]
+	return id, ok
+}
+
+// Candidates returns the n exercises most similar to name.
+func (m *Matcher) Candidates(name string, n int) []Candidate {
+	target := normalize(name)
+
+	candidates := make([]Candidate, 0, len(m.exercises))
+	for _, e := range m.exercises {
+		candidates = append(candidates, Candidate{
+			ID:    e.ID,
+			Name:  e.GetName(),
+			Score: similarity(target, normalize(e.GetName())),
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	return candidates
+}
+
+// Mapping resolves all names of src it can. The second return value holds
+// the names that need a decision.
+func (m *Matcher) Mapping(src Source) (map[string]string, []string) {
+	mapping := make(map[string]string)
+	unknown := make([]string, 0)
+	for _, name := range src.Names() {
+		if id, ok := m.Match(name); ok {
+			mapping[strings.ToLower(name)] = id
+		} else {
+			unknown = append(unknown, name)
+		}
+	}
+	return mapping, unknown
+}
+
+// normalize lower cases name, moves a trailing "(equipment)" to the front as
+// in "Squat (Barbell)" -> "barbell squat" and reduces punctuation to spaces.
+func normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if i := strings.LastIndex(name, "("); i > 0 && strings.HasSuffix(name, ")") {
+		name = name[i+1:len(name)-1] + " " + name[:i]
+	}
+
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
+
+// compactKey drops spaces and plural s, so "Pull Up" and "Pullups" meet.
+func compactKey(name string) string {
+	words := strings.Fields(normalize(name))
+	for i, w := range words {
+		if len(w) > 3 {
+			words[i] = strings.TrimSuffix(w, "s")
+		}
+	}
+	return strings.TrimSuffix(strings.Join(words, ""), "s")
+}
+
+// similarity is the Dice coefficient over letter bigrams.
+func similarity(a, b string) float64 {
+	ba, bb := bigrams(a), bigrams(b)
+	if len(ba) == 0 || len(bb) == 0 {
+		return 0
+	}
+
+	count := make(map[string]int, len(ba))
+	for _, g := range ba {
+		count[g]++
+	}
+	shared := 0
+	for _, g := range bb {
+		if count[g] > 0 {
+			count[g]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ba)+len(bb))
+}
+
+func bigrams(s string) []string {
+	r := []rune(s)
+	grams := make([]string, 0, len(r))
+	for i := 0; i+1 < len(r); i++ {
+		grams = append(grams, string(r[i:i+2]))
+	}
+	return grams
+}
@@ -0,0 +1,201 @@
+// Package interval plans timed conditioning work: EMOM, AMRAP, Tabata and
+// custom sequences of work and rest.
+//
+// Sequences are written as work/rest pairs in seconds or m:ss, a trailing xN
+// repeats a pair:
+//
+//	1:00         one minute of work
+//	20/10x8      eight times 20 seconds of work and 10 of rest
+//	40/20x3 90   three 40/20 rounds, then 90 seconds of work
+package interval
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kinds
+const (
+	EMOM   = "emom"
+	AMRAP  = "amrap"
+	TABATA = "tabata"
+	CUSTOM = "custom"
+)
+
+var Kinds = []string{EMOM, AMRAP, TABATA, CUSTOM}
+
+// Phase is a stretch of work or rest.
+type Phase struct {
+	Work     bool
+	Duration time.Duration
+}
+
+// Defaults are the sequence and rounds a kind starts with.
+func Defaults(kind string) (string, int) {
+	switch kind {
+	case EMOM:
+		return "1:00", 10
+	case AMRAP:
+		return "10:00", 1
+	case TABATA:
+		return "20/10", 8
+	}
+	return "", 1
+}
+
+// Plan lays out the phases of a timer: the sequence spec repeated rounds
+// times. An empty spec or rounds below 1 take the defaults of kind.
+func Plan(kind, spec string, rounds int) ([]Phase, error) {
+	if !slices.Contains(Kinds, kind) {
+		return nil, fmt.Errorf("timer must be one of %v, got %q", strings.Join(Kinds, ", "), kind)
+	}
+
+	defSpec, defRounds := Defaults(kind)
+	if strings.TrimSpace(spec) == "" {
+		spec = defSpec
+	}
+	if rounds < 1 {
+		rounds = defRounds
+	}
+	if spec == "" {
+		return nil, fmt.Errorf("a custom timer needs a sequence like 40/20x8")
+	}
+
+	sequence, err := Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	if kind == AMRAP && len(sequence) != 1 {
+		return nil, fmt.Errorf("an AMRAP is a single time span like 12:00")
+	}
+
+	phases := make([]Phase, 0, len(sequence)*rounds)
+	for range rounds {
+		phases = append(phases, sequence...)
+	}
+	return phases, nil
+}
+
+// Parse reads a sequence of work/rest pairs.
+func Parse(spec string) ([]Phase, error) {
+	fields := strings.FieldsFunc(spec, func(r rune) bool { return r == ' ' || r == ',' || r == ';' })
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty sequence")
+	}
+
+	phases := make([]Phase, 0, len(fields)*2)
+	for _, f := range fields {
+		pair, times, repeated := strings.Cut(strings.ToLower(f), "x")
+		n := 1
+		if repeated {
+			var err error
+			n, err = strconv.Atoi(times)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("bad repetition in %q", f)
+			}
+		}
+
+		workPart, restPart, hasRest := strings.Cut(pair, "/")
+		work, err := parseDuration(workPart)
+		if err != nil || work <= 0 {
+			return nil, fmt.Errorf("bad work time in %q", f)
+		}
+		var rest time.Duration
+		if hasRest {
+			rest, err = parseDuration(restPart)
+			if err != nil {
+				return nil, fmt.Errorf("bad rest time in %q", f)
+			}
+		}
+
+		for range n {
+			phases = append(phases, Phase{Work: true, Duration: work})
+			if rest > 0 {
+				phases = append(phases, Phase{Duration: rest})
+			}
+		}
+	}
+	return phases, nil
+}
+
+// Total is the length of all phases.
+func Total(phases []Phase) time.Duration {
+	var d time.Duration
+	for _, p := range phases {
+		d += p.Duration
+	}
+	return d
+}
+
+// At finds the phase running after elapsed and the time left of it. It also
+// counts the work phases completed by then. i is len(phases) once all are
+// done.
+func At(phases []Phase, elapsed time.Duration) (i int, left time.Duration, worked int) {
+	for i, p := range phases {
+		if elapsed < p.Duration {
+			return i, p.Duration - elapsed, worked
+		}
+		elapsed -= p.Duration
+		if p.Work {
+			worked++
+		}
+	}
+	return len(phases), 0, worked
+}
+
+// Rounds counts the work phases.
+func Rounds(phases []Phase) int {
+	n := 0
+	for _, p := range phases {
+		if p.Work {
+			n++
+		}
+	}
+	return n
+}
+
+// Describe names a timer in a few words, e.g. "EMOM 10 × 1:00".
+func Describe(kind, spec string, rounds int) string {
+	defSpec, defRounds := Defaults(kind)
+	if strings.TrimSpace(spec) == "" {
+		spec = defSpec
+	}
+	if rounds < 1 {
+		rounds = defRounds
+	}
+
+	name := strings.ToUpper(kind)
+	if kind == TABATA || kind == CUSTOM {
+		name = strings.ToUpper(kind[:1]) + kind[1:]
+	}
+	if rounds == 1 {
+		return name + " " + spec
+	}
+	return fmt.Sprintf("%v %v × %v", name, rounds, spec)
+}
+
+// Format shows a duration as m:ss, started seconds count as full ones.
+func Format(d time.Duration) string {
+	s := int((d + time.Second - 1).Truncate(time.Second).Seconds())
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// parseDuration reads seconds ("90") or minutes and seconds ("1:30").
+func parseDuration(s string) (time.Duration, error) {
+	minutes, seconds, ok := strings.Cut(s, ":")
+	if !ok {
+		minutes, seconds = "0", s
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 {
+		return 0, fmt.Errorf("bad minutes %q", s)
+	}
+	n, err := strconv.Atoi(seconds)
+	if err != nil || n < 0 || (ok && n >= 60) {
+		return 0, fmt.Errorf("bad seconds %q", s)
+	}
+	return time.Duration(m*60+n) * time.Second, nil
+}
@@ -0,0 +1,138 @@
+package interval
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func work(s int) Phase { return Phase{Work: true, Duration: time.Duration(s) * time.Second} }
+func rest(s int) Phase { return Phase{Duration: time.Duration(s) * time.Second} }
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []Phase
+	}{
+		{"1:00", []Phase{work(60)}},
+		{"90", []Phase{work(90)}},
+		{"20/10x2", []Phase{work(20), rest(10), work(20), rest(10)}},
+		{"40/20X2 1:30", []Phase{work(40), rest(20), work(40), rest(20), work(90)}},
+		{"30/0, 45;15", []Phase{work(30), work(45), work(15)}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.spec)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %v, %v, want %v", tt.spec, got, err, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"", " , ", "0", "abc", "20/xx", "20/10x0", "20/10xa", "1:60", "-5", "30/-10"} {
+		if got, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", spec, got)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		kind, spec string
+		rounds     int
+		phases     int
+		total      time.Duration
+	}{
+		{EMOM, "", 0, 10, 10 * time.Minute},
+		{EMOM, "", 12, 12, 12 * time.Minute},
+		{AMRAP, "", 0, 1, 10 * time.Minute},
+		{AMRAP, "12:00", 1, 1, 12 * time.Minute},
+		{TABATA, "", 0, 16, 4 * time.Minute},
+		{CUSTOM, "40/20x2 90", 2, 10, 2 * (2*time.Minute + 90*time.Second)},
+	}
+	for _, tt := range tests {
+		phases, err := Plan(tt.kind, tt.spec, tt.rounds)
+		if err != nil {
+			t.Errorf("Plan(%v, %q, %v): %v", tt.kind, tt.spec, tt.rounds, err)
+			continue
+		}
+		if len(phases) != tt.phases || Total(phases) != tt.total {
+			t.Errorf("Plan(%v, %q, %v) = %v phases of %v, want %v of %v", tt.kind, tt.spec, tt.rounds, len(phases), Total(phases), tt.phases, tt.total)
+		}
+	}
+
+	invalid := []struct{ kind, spec string }{
+		{"fartlek", "1:00"},
+		{CUSTOM, ""},
+		{AMRAP, "20/10"},
+		{EMOM, "x"},
+	}
+	for _, tt := range invalid {
+		if _, err := Plan(tt.kind, tt.spec, 1); err == nil {
+			t.Errorf("Plan(%v, %q) made a plan", tt.kind, tt.spec)
+		}
+	}
+}
+
+func TestAt(t *testing.T) {
+	phases := []Phase{work(20), rest(10), work(20), rest(10)}
+	tests := []struct {
+		elapsed time.Duration
+		i       int
+		left    time.Duration
+		worked  int
+	}{
+		{0, 0, 20 * time.Second, 0},
+		{5 * time.Second, 0, 15 * time.Second, 0},
+		{20 * time.Second, 1, 10 * time.Second, 1},
+		{29500 * time.Millisecond, 1, 500 * time.Millisecond, 1},
+		{45 * time.Second, 2, 5 * time.Second, 1},
+		{55 * time.Second, 3, 5 * time.Second, 2},
+		{60 * time.Second, 4, 0, 2},
+		{time.Hour, 4, 0, 2},
+	}
+	for _, tt := range tests {
+		i, left, worked := At(phases, tt.elapsed)
+		if i != tt.i || left != tt.left || worked != tt.worked {
+			t.Errorf("At(%v) = %v, %v, %v, want %v, %v, %v", tt.elapsed, i, left, worked, tt.i, tt.left, tt.worked)
+		}
+	}
+	if n := Rounds(phases); n != 2 {
+		t.Errorf("Rounds = %v, want 2", n)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		kind, spec string
+		rounds     int
+		want       string
+	}{
+		{EMOM, "", 0, "EMOM 10 × 1:00"},
+		{AMRAP, "", 0, "AMRAP 10:00"},
+		{TABATA, "", 0, "Tabata 8 × 20/10"},
+		{CUSTOM, "40/20x3 90", 1, "Custom 40/20x3 90"},
+		{CUSTOM, "30/30", 5, "Custom 5 × 30/30"},
+	}
+	for _, tt := range tests {
+		if got := Describe(tt.kind, tt.spec, tt.rounds); got != tt.want {
+			t.Errorf("Describe(%v, %q, %v) = %q, want %q", tt.kind, tt.spec, tt.rounds, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0:00"},
+		{59 * time.Second, "0:59"},
+		{59100 * time.Millisecond, "1:00"},
+		{10 * time.Minute, "10:00"},
+		{61*time.Second + time.Nanosecond, "1:02"},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.d); got != tt.want {
+			t.Errorf("Format(%v) = %v, want %v", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+// Package library ships well-known training programs ready to be installed.
+//
+// Every program is a TOML file in programs/ describing its workouts and the
+// weeks that schedule them:
+//
+//	name = "StrongLifts 5x5"
+//	description = "Three full body sessions a week ..."
+//	maxes = "the weights you start the 5x5 with"
+//
+//	[[workouts]]
+//	key = "a"                          # what days refer to
+//	name = "StrongLifts A"
+//
+//	[[workouts.exercises]]
+//	exercise = "Barbell_Squat"         # ID of an exercise shipped with clift
+//	scheme = "100x5 100x5 100x5"       # percent x reps of the lift's max
+//	progression = "linear"             # optional rule, see package progression
+//	increment = 2.5                    # kilograms, like all weights here
+//	deload_after = 3
+//	deload_percent = 10
+//
+//	[[workouts.exercises]]
+//	exercise = "Pullups"
+//	sets = "3x5-8@bw"                  # fixed targets in set notation
+//
+//	[[weeks]]
+//	name = "A/B"
+//	scheme = ""                        # optional week scheme
+//	days = ["a", "b"]
+//
+// Installing asks for a max of every lift with a scheme. Lifts following the
+// percent rule keep it as their training max, so week schemes apply to them.
+package library
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/notation"
+	"github.com/zmnpl/clift/progression"
+)
+
+//go:embed programs/*.toml
+var programFiles embed.FS
+
+type Program struct {
+	Name        string    `toml:"name"`
+	Description string    `toml:"description"`
+	Maxes       string    `toml:"maxes"` // what the asked maxes mean
+	Workouts    []Workout `toml:"workouts"`
+	Weeks       []Week    `toml:"weeks"`
+}
+
+type Workout struct {
+	Key       string     `toml:"key"`
+	Name      string     `toml:"name"`
+	Exercises []Exercise `toml:"exercises"`
+}
+
+type Exercise struct {
+	Exercise      string  `toml:"exercise"`
+	Note          string  `toml:"note"`
+	Scheme        string  `toml:"scheme"`
+	Sets          string  `toml:"sets"`
+	Progression   string  `toml:"progression"`
+	Increment     float64 `toml:"increment"`
+	Reps          [2]int  `toml:"reps"` // rep range of double progression
+	DeloadAfter   int     `toml:"deload_after"`
+	DeloadPercent float64 `toml:"deload_percent"`
+}
+
+type Week struct {
+	Name   string   `toml:"name"`
+	Scheme string   `toml:"scheme"`
+	Days   []string `toml:"days"`
+}
+
+// Programs reads all programs of the library, sorted by name.
+func Programs() ([]Program, error) {
+	files, err := fs.Glob(programFiles, "programs/*.toml")
+	if err != nil {
+		return nil, err
+	}
+
+	programs := make([]Program, 0, len(files))
+	for _, f := range files {
+		var p Program
+		if _, err := toml.DecodeFS(programFiles, f, &p); err != nil {
+			return nil, fmt.Errorf("library: %v: %v", f, err)
+		}
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("library: %v: %v", f, err)
+		}
+		programs = append(programs, p)
+	}
+
+	slices.SortFunc(programs, func(a, b Program) int { return strings.Compare(a.Name, b.Name) })
+	return programs, nil
+}
+
+// Validate checks that the program only uses shipped exercises, valid sets
+// and rules and that its days refer to its workouts.
+func (p Program) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("program without name")
+	}
+
+	keys := make(map[string]bool, len(p.Workouts))
+	for _, w := range p.Workouts {
+		if keys[w.Key] {
+			return fmt.Errorf("workout key %q used twice", w.Key)
+		}
+		keys[w.Key] = true
+
+		for _, e := range w.Exercises {
+			if !(wodb.Exercise{ID: e.Exercise}).IsSeeded() {
+				return fmt.Errorf("%v: unknown exercise %q", w.Name, e.Exercise)
+			}
+			if (e.Scheme == "") == (e.Sets == "") {
+				return fmt.Errorf("%v: %v needs either a scheme or sets", w.Name, e.Exercise)
+			}
+			if _, err := e.targets(100, notation.KG); err != nil {
+				return fmt.Errorf("%v: %v: %v", w.Name, e.Exercise, err)
+			}
+			we := e.rule(100)
+			if err := progression.Validate(we); err != nil {
+				return fmt.Errorf("%v: %v: %v", w.Name, e.Exercise, err)
+			}
+		}
+	}
+
+	for i, week := range p.Weeks {
+		if _, err := progression.ParseScheme(week.Scheme); err != nil {
+			return fmt.Errorf("week %v: %v", i+1, err)
+		}
+		for _, d := range week.Days {
+			if !keys[d] {
+				return fmt.Errorf("week %v: no workout %q", i+1, d)
+			}
+		}
+	}
+	return nil
+}
+
+// Lifts are the IDs of the exercises a max is needed for, in order of their
+// first appearance.
+func (p Program) Lifts() []string {
+	lifts := make([]string, 0, 10)
+	for _, w := range p.Workouts {
+		for _, e := range w.Exercises {
+			if e.Scheme != "" && !slices.Contains(lifts, e.Exercise) {
+				lifts = append(lifts, e.Exercise)
+			}
+		}
+	}
+	return lifts
+}
+
+// Install creates the workouts and the program in store. maxes holds the max
+// of every lift by exercise ID, weights are rounded in unit. Nothing is left
+// of an install that fails.
+func Install(store wodb.Store, p Program, maxes map[string]float64, unit string) (*wodb.Program, error) {
+	for _, lift := range p.Lifts() {
+		if maxes[lift] <= 0 {
+			return nil, fmt.Errorf("no max given for %v", lift)
+		}
+	}
+
+	var program *wodb.Program
+	err := store.Transaction(func(tx wodb.Store) error {
+		var err error
+		program, err = install(tx, p, maxes, unit)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return program, nil
+}
+
+func install(store wodb.Store, p Program, maxes map[string]float64, unit string) (*wodb.Program, error) {
+	workoutIDs := make(map[string]uint, len(p.Workouts))
+	for _, w := range p.Workouts {
+		workout, err := store.CreateWorkout(w.Name)
+		if err != nil {
+			return nil, err
+		}
+		workoutIDs[w.Key] = workout.ID
+
+		for _, e := range w.Exercises {
+			we, err := store.AddExerciseToWorkout(workout.ID, e.Exercise, e.Note)
+			if err != nil {
+				return nil, fmt.Errorf("adding %v to %v: %v", e.Exercise, w.Name, err)
+			}
+
+			sets, err := e.targets(maxes[e.Exercise], unit)
+			if err != nil {
+				return nil, fmt.Errorf("targets of %v in %v: %v", e.Exercise, w.Name, err)
+			}
+			for i := range sets {
+				sets[i].WorkoutExerciseID = we.ID
+			}
+			if err := store.UpdateWorkoutExerciseSets(we.ID, sets); err != nil {
+				return nil, err
+			}
+
+			rule := e.rule(maxes[e.Exercise])
+			rule.ID = we.ID
+			if err := store.UpdateProgression(rule); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	program, err := store.CreateProgram(p.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, week := range p.Weeks {
+		days := make([]uint, len(week.Days))
+		for i, d := range week.Days {
+			days[i] = workoutIDs[d]
+		}
+		if _, err := store.AddProgramWeek(program.ID, week.Name, week.Scheme, days); err != nil {
+			return nil, err
+		}
+	}
+	return program, nil
+}
+
+// targets are the set targets of the exercise for a max, rounded in unit.
+func (e Exercise) targets(max float64, unit string) ([]wodb.Set, error) {
+	if e.Scheme != "" {
+		scheme, err := progression.ParseScheme(e.Scheme)
+		if err != nil {
+			return nil, err
+		}
+		return progression.ApplyScheme(scheme, max, unit), nil
+	}
+	return notation.ParseTemplates(e.Sets, notation.KG, 0)
+}
+
+// rule is the progression rule of the exercise for a max.
+func (e Exercise) rule(max float64) wodb.WorkoutExercise {
+	we := wodb.WorkoutExercise{
+		Progression:   e.Progression,
+		Increment:     e.Increment,
+		RepsMin:       e.Reps[0],
+		RepsMax:       e.Reps[1],
+		DeloadAfter:   e.DeloadAfter,
+		DeloadPercent: e.DeloadPercent,
+	}
+	if e.Progression == progression.PERCENT {
+		we.TrainingMax = max
+	}
+	return we
+}
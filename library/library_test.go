@@ -0,0 +1,76 @@
+package library
+
+import (
+	"testing"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+func TestInstall(t *testing.T) {
+	programs, err := Programs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(programs) == 0 {
+		t.Fatal("empty library")
+	}
+
+	for _, p := range programs {
+		store := wodb.NewInMemoryTrainingDB()
+		maxes := make(map[string]float64)
+		for _, lift := range p.Lifts() {
+			maxes[lift] = 100
+		}
+
+		installed, err := Install(store, p, maxes, "kg")
+		if err != nil {
+			t.Errorf("installing %v: %v", p.Name, err)
+			continue
+		}
+		program, err := store.GetProgram(installed.ID)
+		if err != nil || len(program.Weeks) != len(p.Weeks) {
+			t.Errorf("%v installed with %v weeks, want %v (%v)", p.Name, len(program.Weeks), len(p.Weeks), err)
+		}
+		workouts, _ := store.GetAllWorkouts()
+		if len(workouts) != len(p.Workouts) {
+			t.Errorf("%v installed %v workouts, want %v", p.Name, len(workouts), len(p.Workouts))
+		}
+	}
+}
+
+func TestInstallAllOrNothing(t *testing.T) {
+	store := wodb.NewInMemoryTrainingDB()
+	p := Program{
+		Name: "Broken",
+		Workouts: []Workout{
+			{Key: "a", Name: "A", Exercises: []Exercise{{Exercise: "Barbell_Squat", Sets: "3x5@100"}}},
+			{Key: "b", Name: "B", Exercises: []Exercise{{Exercise: "Barbell_Deadlift", Sets: "not sets"}}},
+		},
+		Weeks: []Week{{Name: "A/B", Days: []string{"a", "b"}}},
+	}
+
+	if _, err := Install(store, p, nil, "kg"); err == nil {
+		t.Fatal("broken program installed")
+	}
+
+	workouts, _ := store.GetAllWorkouts()
+	programs, _ := store.GetPrograms()
+	if len(workouts) != 0 || len(programs) != 0 {
+		t.Errorf("failed install left %v workouts and %v programs", len(workouts), len(programs))
+	}
+}
+
+func TestInstallNeedsMaxes(t *testing.T) {
+	programs, err := Programs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range programs {
+		if len(p.Lifts()) == 0 {
+			continue
+		}
+		if _, err := Install(wodb.NewInMemoryTrainingDB(), p, nil, "kg"); err == nil {
+			t.Errorf("%v installed without maxes", p.Name)
+		}
+	}
+}
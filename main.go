@@ -1,26 +1,63 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zmnpl/clift/cli"
+	"github.com/zmnpl/clift/config"
 	wodb "github.com/zmnpl/clift/db"
 	ui "github.com/zmnpl/clift/ui"
+	coms "github.com/zmnpl/clift/ui/common"
 )
 
 func main() {
-	home, err := os.UserHomeDir()
+	dbFlag := flag.String("db", "", "path of the training database (overrides config and "+config.ENV_DB+")")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: clift [--db PATH] [command]")
+		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output(), "\nrun 'clift help' for the list of commands")
+	}
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Cannot load config: %v", err)
+	}
+	if *dbFlag != "" {
+		cfg.DB = *dbFlag
+	}
+
+	dbPath, err := cfg.DBPath()
 	if err != nil {
-		log.Fatalf("Cannot find user home: %v", err)
+		log.Fatalf("Cannot resolve db path: %v", err)
+	}
+
+	settings, err := coms.NewSettings(cfg)
+	if err != nil {
+		log.Fatalf("Cannot apply config: %v", err)
+	}
+	if err := coms.SetTheme(cfg.Theme); err != nil {
+		log.Fatalf("Cannot apply config: %v", err)
+	}
+
+	// any argument means headless mode
+	if flag.NArg() > 0 {
+		open := func() wodb.Store { return wodb.Init(dbPath) }
+		if err := cli.Run(open, cfg, flag.Args()); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
 	}
 
 	// Init DB
-	wodb.Init(filepath.Join(home, "Documents", "training.db"))
+	store := wodb.Init(dbPath)
 
-	p := tea.NewProgram(ui.NewModel(), tea.WithAltScreen())
+	p := tea.NewProgram(ui.NewModel(store, settings), tea.WithAltScreen())
 	_, err = p.Run()
 	if err != nil {
 		fmt.Println("Error running TUI:", err)
@@ -0,0 +1,91 @@
+package notation
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseDuration reads the duration of a cardio or timed set in seconds:
+// "1:05:00", "30:00", "45s", "30m", "1h" or "1h30m". A bare number counts in
+// unit, e.g. time.Minute for a run and time.Second for a stretch.
+func ParseDuration(s string, unit time.Duration) (int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+
+	if strings.Contains(s, ":") {
+		parts := strings.Split(s, ":")
+		if len(parts) > 3 {
+			return 0, fmt.Errorf("bad duration %q, want h:mm:ss or m:ss", s)
+		}
+		total := 0
+		for i, p := range parts {
+			n, err := strconv.Atoi(p)
+			if err != nil || n < 0 || (i > 0 && n >= 60) {
+				return 0, fmt.Errorf("bad duration %q, want h:mm:ss or m:ss", s)
+			}
+			total = total*60 + n
+		}
+		return total, nil
+	}
+
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("bad duration %q", s)
+		}
+		return int(math.Round(n * unit.Seconds())), nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("bad duration %q, want e.g. 30:00 or 45s", s)
+	}
+	return int(d.Round(time.Second).Seconds()), nil
+}
+
+// FormatDuration shows seconds as m:ss, or h:mm:ss from an hour on.
+func FormatDuration(seconds int) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// ParseDistance reads a distance in kilometres, "5", "5km", "5.2" or "400m".
+func ParseDistance(s string) (float64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+
+	factor := 1.0
+	if rest, ok := strings.CutSuffix(s, "km"); ok {
+		s = rest
+	} else if rest, ok := strings.CutSuffix(s, "m"); ok {
+		s, factor = rest, 0.001
+	}
+	d, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", "."), 64)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("bad distance %q", s)
+	}
+	return d * factor, nil
+}
+
+// FormatDistance shows kilometres with up to two decimals, e.g. "5.25 km".
+func FormatDistance(km float64) string {
+	return strconv.FormatFloat(math.Round(km*100)/100, 'f', -1, 64) + " km"
+}
+
+// FormatPace shows a pace as m:ss per kilometre, e.g. "5:30 /km".
+func FormatPace(pace time.Duration) string {
+	return FormatDuration(int(pace.Round(time.Second).Seconds())) + " /km"
+}
+
+// FormatSpeed shows a speed in km/h with one decimal.
+func FormatSpeed(kmh float64) string {
+	return strconv.FormatFloat(kmh, 'f', 1, 64) + " km/h"
+}
@@ -0,0 +1,59 @@
+package notation
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseEffort reads how hard a set was: an RPE from 6 to 10 in half steps
+// ("8", "8.5", "@8", "rpe 8") or reps in reserve ("2rir", "rir 2", "r2").
+// rir is nil unless given, both are zero for an empty string.
+func ParseEffort(s string) (rpe float64, rir *int, err error) {
+	s = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+	if s == "" {
+		return 0, nil, nil
+	}
+
+	if n, ok := cutRIR(s); ok {
+		r, err := strconv.Atoi(n)
+		if err != nil || r < 0 || r > 10 {
+			return 0, nil, fmt.Errorf("bad reps in reserve %q", s)
+		}
+		return 0, &r, nil
+	}
+
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "@"), "rpe")
+	rpe, err = strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+	if err != nil || rpe < 6 || rpe > 10 || math.Mod(rpe*2, 1) != 0 {
+		return 0, nil, fmt.Errorf("RPE must be 6 to 10 in half steps, or reps in reserve like 2rir, got %q", s)
+	}
+	return rpe, nil, nil
+}
+
+// cutRIR strips the marks of reps in reserve off s.
+func cutRIR(s string) (string, bool) {
+	if n, ok := strings.CutSuffix(s, "rir"); ok {
+		return n, true
+	}
+	if n, ok := strings.CutPrefix(s, "rir"); ok {
+		return n, true
+	}
+	if strings.HasPrefix(s, "rpe") {
+		return s, false
+	}
+	return strings.CutPrefix(s, "r")
+}
+
+// FormatEffort shows an RPE as "@8.5" or reps in reserve as "RIR 2", empty
+// if neither is known.
+func FormatEffort(rpe float64, rir *int) string {
+	switch {
+	case rpe > 0:
+		return "@" + strconv.FormatFloat(rpe, 'f', -1, 64)
+	case rir != nil:
+		return fmt.Sprintf("RIR %v", *rir)
+	}
+	return ""
+}
@@ -0,0 +1,216 @@
+// Package notation parses the compact set notation used for quick logging.
+//
+// A line consists of one or more groups separated by spaces or semicolons:
+//
+//	5x5@100      5 sets of 5 reps at 100
+//	3x8-10@60kg  3 sets of 8 to 10 reps at 60
+//	5x5@135lb    5 sets of 5 reps at 135 pounds
+//	3x5@62,5     3 sets of 5 reps at 62.5
+//	12,10,8@40   3 sets of 12, 10 and 8 reps at 40
+//	100x5,5,4    3 sets of 5, 5 and 4 reps at 100
+//	BW+20x8      1 set of 8 reps with 20 added to bodyweight
+//	8,8,6        3 sets of bodyweight reps
+//
+// With an "@" the part in front describes the reps and the part behind the
+// weight. Without it, the part in front of the "x" is the weight. Bodyweight
+// counts as 0, so BW+20 is stored as 20 and BW-10 (assisted) as -10. A kg or
+// lb behind the weight overrides the unit of bare numbers. Spaces around the
+// operators and in front of the unit don't matter, "5 x 5 @ 135 lb" is fine.
+package notation
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+// Set is one parsed set. For rep ranges like 8-10 Reps holds the lower and
+// RepsMax the upper bound, otherwise both are the same. Weight is as written,
+// in Unit if the notation names one.
+type Set struct {
+	Reps    int
+	RepsMax int
+	Weight  float64
+	Unit    string
+}
+
+// MAX_SETS is the most sets a single group like 5x5 may stand for.
+const MAX_SETS = 100
+
+var (
+	operatorSpaces = regexp.MustCompile(`\s*([,@x+\-])\s*`)
+	unitSpaces     = regexp.MustCompile(`(\d)\s+(kg|lb)`)
+)
+
+// Parse reads a whole line of notation.
+func Parse(line string) ([]Set, error) {
+	line = strings.ToLower(strings.TrimSpace(line))
+	line = strings.NewReplacer("×", "x", "*", "x").Replace(line)
+	line = operatorSpaces.ReplaceAllString(line, "$1")
+	line = unitSpaces.ReplaceAllString(line, "$1$2")
+
+	groups := strings.FieldsFunc(line, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == ';'
+	})
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("no sets given")
+	}
+
+	sets := make([]Set, 0, 10)
+	for _, g := range groups {
+		gs, err := parseGroup(g)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sets %q: %v", g, err)
+		}
+		sets = append(sets, gs...)
+	}
+
+	return sets, nil
+}
+
+// ParsePerformed parses line into sets ready for logging, bare weights count
+// in unit. Rep ranges are logged with their lower bound.
+func ParsePerformed(line string, unit string, workoutID uint, exerciseID string, performedDate time.Time) ([]wodb.PerformedSet, error) {
+	sets, err := Parse(line)
+	if err != nil {
+		return nil, err
+	}
+
+	performed := make([]wodb.PerformedSet, len(sets))
+	for i, s := range sets {
+		performed[i] = wodb.PerformedSet{
+			WorkoutID:     workoutID,
+			ExerciseID:    exerciseID,
+			PerformedDate: performedDate,
+			SetNo:         i,
+			Reps:          s.Reps,
+			Weight:        s.Kg(unit),
+			Unit:          s.UnitOr(unit),
+		}
+	}
+	return performed, nil
+}
+
+// ParseTemplates parses line into set templates of a workout exercise, bare
+// weights count in unit. Rep ranges become their lower bound.
+func ParseTemplates(line string, unit string, weID uint) ([]wodb.Set, error) {
+	sets, err := Parse(line)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make([]wodb.Set, len(sets))
+	for i, s := range sets {
+		templates[i] = wodb.Set{
+			WorkoutExerciseID: weID,
+			Reps:              s.Reps,
+			Weight:            s.Kg(unit),
+		}
+	}
+	return templates, nil
+}
+
+// UnitOr is the unit of s, unit if the notation doesn't name one.
+func (s Set) UnitOr(unit string) string {
+	if s.Unit != "" {
+		return s.Unit
+	}
+	return unit
+}
+
+// Kg is the weight of s in kilograms, bare weights counting in unit.
+func (s Set) Kg(unit string) float64 {
+	return ToKg(s.Weight, s.UnitOr(unit))
+}
+
+func parseGroup(g string) ([]Set, error) {
+	var sets []Set
+	var unit string
+	var err error
+
+	if repsPart, weightPart, ok := strings.Cut(g, "@"); ok {
+		// reps@weight
+		var weight float64
+		if weight, unit, err = parseWeight(weightPart); err != nil {
+			return nil, err
+		}
+		sets, err = parseReps(repsPart, weight)
+	} else if i := strings.LastIndex(g, "x"); i >= 0 {
+		// weight x reps
+		var weight float64
+		if weight, unit, err = parseWeight(g[:i]); err != nil {
+			return nil, err
+		}
+		sets, err = parseRepList(g[i+1:], weight)
+	} else {
+		// reps only
+		return parseRepList(g, 0)
+	}
+
+	for i := range sets {
+		sets[i].Unit = unit
+	}
+	return sets, err
+}
+
+// parseReps reads "5x5", "3x8-10" or a rep list like "12,10,8".
+func parseReps(s string, weight float64) ([]Set, error) {
+	count, reps, ok := strings.Cut(s, "x")
+	if !ok {
+		return parseRepList(s, weight)
+	}
+
+	n, err := strconv.Atoi(count)
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("bad set count %q", count)
+	}
+	if n > MAX_SETS {
+		return nil, fmt.Errorf("set count %v is more than %v", n, MAX_SETS)
+	}
+	low, high, err := parseRepRange(reps)
+	if err != nil {
+		return nil, err
+	}
+
+	sets := make([]Set, n)
+	for i := range sets {
+		sets[i] = Set{Reps: low, RepsMax: high, Weight: weight}
+	}
+	return sets, nil
+}
+
+// parseRepList reads comma separated reps, each of them possibly a range.
+func parseRepList(s string, weight float64) ([]Set, error) {
+	items := strings.Split(s, ",")
+	sets := make([]Set, 0, len(items))
+	for _, item := range items {
+		low, high, err := parseRepRange(item)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, Set{Reps: low, RepsMax: high, Weight: weight})
+	}
+	return sets, nil
+}
+
+func parseRepRange(s string) (int, int, error) {
+	lowPart, highPart, isRange := strings.Cut(s, "-")
+
+	low, err := strconv.Atoi(lowPart)
+	if err != nil || low <= 0 {
+		return 0, 0, fmt.Errorf("bad reps %q", s)
+	}
+	if !isRange {
+		return low, low, nil
+	}
+
+	high, err := strconv.Atoi(highPart)
+	if err != nil || high < low {
+		return 0, 0, fmt.Errorf("bad rep range %q", s)
+	}
+	return low, high, nil
+}
@@ -0,0 +1,125 @@
+package notation
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		line string
+		want []Set
+	}{
+		// the examples of the README
+		{"5x5@100", repeat(5, Set{Reps: 5, RepsMax: 5, Weight: 100})},
+		{"3x8-10@60kg", repeat(3, Set{Reps: 8, RepsMax: 10, Weight: 60, Unit: KG})},
+		{"5x5@135lb", repeat(5, Set{Reps: 5, RepsMax: 5, Weight: 135, Unit: LB})},
+		{"12,10,8@40", []Set{{12, 12, 40, ""}, {10, 10, 40, ""}, {8, 8, 40, ""}}},
+		{"100x5,5,4", []Set{{5, 5, 100, ""}, {5, 5, 100, ""}, {4, 4, 100, ""}}},
+		{"BW+20x8", []Set{{8, 8, 20, ""}}},
+		{"8,8,6", []Set{{8, 8, 0, ""}, {8, 8, 0, ""}, {6, 6, 0, ""}}},
+		{"5x5@100 3x5@90", append(repeat(5, Set{5, 5, 100, ""}), repeat(3, Set{5, 5, 90, ""})...)},
+		{"5x5@100;1x3@110", append(repeat(5, Set{5, 5, 100, ""}), Set{3, 3, 110, ""})},
+
+		// spelled differently
+		{"5 x 5 @ 135 lb", repeat(5, Set{5, 5, 135, LB})},
+		{"5×5@100kgs", repeat(5, Set{5, 5, 100, KG})},
+		{"3*5@62,5", repeat(3, Set{5, 5, 62.5, ""})},
+		{"3x5@62.5", repeat(3, Set{5, 5, 62.5, ""})},
+		{"62,5x5,5", []Set{{5, 5, 62.5, ""}, {5, 5, 62.5, ""}}},
+		{"bw - 10 x 8", []Set{{8, 8, -10, ""}}},
+		{"3x10@bw", repeat(3, Set{10, 10, 0, ""})},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.line)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.line, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Parse(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"5x5@nan",
+		"5x5@inf",
+		"5x5@-100",
+		"5x5@bw+nan",
+		"0x5@100",
+		"101x5@100",
+		"100000000x5@100",
+		"5x0@100",
+		"3x10-8@60",
+		"5x5@abc",
+		"5x5@bw20",
+	} {
+		if sets, err := Parse(line); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", line, sets)
+		}
+	}
+}
+
+func TestParseWeight(t *testing.T) {
+	tests := []struct {
+		s, unit string
+		kg      float64
+		entered string
+	}{
+		{"", KG, 0, KG},
+		{"100", KG, 100, KG},
+		{"62,5", KG, 62.5, KG},
+		{"62.5 kg", LB, 62.5, KG},
+		{"135 lb", KG, 135 * LB_IN_KG, LB},
+		{"225", LB, 225 * LB_IN_KG, LB},
+		{"bw+20", KG, 20, KG},
+		{"bw - 10", KG, -10, KG},
+	}
+	for _, tt := range tests {
+		kg, entered, err := ParseWeight(tt.s, tt.unit)
+		if err != nil || math.Abs(kg-tt.kg) > 1e-9 || entered != tt.entered {
+			t.Errorf("ParseWeight(%q, %v) = %v, %v, %v, want %v, %v", tt.s, tt.unit, kg, entered, err, tt.kg, tt.entered)
+		}
+	}
+
+	for _, s := range []string{"nan", "inf", "-100", "abc", "bw20"} {
+		if _, _, err := ParseWeight(s, KG); err == nil {
+			t.Errorf("ParseWeight(%q) accepted", s)
+		}
+	}
+}
+
+func TestWeightValue(t *testing.T) {
+	for _, w := range []float64{0, 100, 62.5, -10} {
+		kg, _, err := ParseWeight(WeightValue(w), KG)
+		if err != nil || kg != w {
+			t.Errorf("ParseWeight(WeightValue(%v)) = %v, %v", w, kg, err)
+		}
+	}
+}
+
+func TestFromKg(t *testing.T) {
+	if got := FromKg(100, LB); got != 220.46 {
+		t.Errorf("FromKg(100, lb) = %v, want 220.46", got)
+	}
+	if got := FromKg(ToKg(135, LB), LB); got != 135 {
+		t.Errorf("FromKg(ToKg(135, lb), lb) = %v, want 135", got)
+	}
+	if got := FormatWeight(100, KG); !strings.HasPrefix(got, "100 ") {
+		t.Errorf("FormatWeight(100, kg) = %q", got)
+	}
+}
+
+func repeat(n int, s Set) []Set {
+	sets := make([]Set, n)
+	for i := range sets {
+		sets[i] = s
+	}
+	return sets
+}
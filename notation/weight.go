@@ -0,0 +1,116 @@
+package notation
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// units of weight; weights are stored in KG and shown in the unit of the user
+const (
+	KG = "kg"
+	LB = "lb"
+)
+
+var Units = []string{KG, LB}
+
+const LB_IN_KG = 0.45359237
+
+// ToKg converts weight given in unit to kilograms.
+func ToKg(weight float64, unit string) float64 {
+	if unit == LB {
+		return weight * LB_IN_KG
+	}
+	return weight
+}
+
+// FromKg converts kilograms to unit, rounded to two decimals.
+func FromKg(kg float64, unit string) float64 {
+	if unit == LB {
+		kg = kg / LB_IN_KG
+	}
+	return math.Round(kg*100) / 100
+}
+
+// ParseWeight reads a weight in kilograms, written as in the notation: "100",
+// "62.5kg", "62,5", "135 lb" or a bodyweight load like "bw+20". A bare number
+// counts in unit. entered is the unit the weight was given in.
+func ParseWeight(s, unit string) (kg float64, entered string, err error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0, unit, nil
+	}
+
+	w, entered, err := parseWeight(operatorSpaces.ReplaceAllString(s, "$1"))
+	if err != nil {
+		return 0, unit, err
+	}
+	if entered == "" {
+		entered = unit
+	}
+	return ToKg(w, entered), entered, nil
+}
+
+// WeightValue writes a weight the way ParseWeight reads it back, loads below
+// bodyweight as "bw-10".
+func WeightValue(weight float64) string {
+	s := strconv.FormatFloat(weight, 'f', -1, 64)
+	if weight < 0 {
+		return "bw" + s
+	}
+	return s
+}
+
+// parseWeight reads "100", "62.5kg", "62,5", "135lb", "bw", "bw+20" or
+// "bw-10" and the unit named, if any. Only bodyweight loads can be negative.
+func parseWeight(s string) (float64, string, error) {
+	s, unit := cutUnit(s)
+
+	if rest, ok := strings.CutPrefix(s, "bw"); ok {
+		if rest == "" {
+			return 0, unit, nil
+		}
+		w, err := parseNumber(rest)
+		if err != nil || (rest[0] != '+' && rest[0] != '-') {
+			return 0, "", fmt.Errorf("bad bodyweight load %q, want e.g. bw+20 or bw-10", s)
+		}
+		return w, unit, nil
+	}
+
+	w, err := parseNumber(s)
+	if err != nil {
+		return 0, "", fmt.Errorf("bad weight %q, want a number like 100, 62.5kg or 135lb", s)
+	}
+	if w < 0 {
+		return 0, "", fmt.Errorf("negative weight %q, write assisted loads as bw-10", s)
+	}
+	return w, unit, nil
+}
+
+// parseNumber reads a finite number, with a decimal point or comma.
+func parseNumber(s string) (float64, error) {
+	w, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(w) || math.IsInf(w, 0) {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	return w, nil
+}
+
+// cutUnit strips a unit off a weight and returns it, empty if s has none.
+func cutUnit(s string) (string, string) {
+	for _, u := range []struct{ suffix, unit string }{{"kgs", KG}, {"kg", KG}, {"lbs", LB}, {"lb", LB}} {
+		if rest, ok := strings.CutSuffix(s, u.suffix); ok {
+			return strings.TrimSpace(rest), u.unit
+		}
+	}
+	return s, ""
+}
+
+// FormatWeight shows kilograms in unit, e.g. "100 kg" or "225 lb".
+func FormatWeight(kg float64, unit string) string {
+	return strconv.FormatFloat(FromKg(kg, unit), 'f', -1, 64) + " " + unit
+}
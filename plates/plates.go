@@ -0,0 +1,153 @@
+// Package plates works out how to load a barbell from the plates at hand.
+//
+// An inventory is a bar weight and the plates available, written as sizes with
+// the number of plates of that size, e.g. for a home gym:
+//
+//	20x4 10x2 5x2 2.5x2 1.25x2   four 20s, two 10s, ... a size alone is a pair
+//
+// Plates go on in pairs, so a count of 4 means two per side. Weights have no
+// unit here, bar and plates are in the unit of the user.
+package plates
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Plate is a plate size and how many of it go on each side at most.
+type Plate struct {
+	Weight  float64
+	PerSide int
+}
+
+// Inventory is a bar and the plates to load it with, heaviest first.
+type Inventory struct {
+	Bar    float64
+	Plates []Plate
+
+	// every load of one side, built by Parse; see loads
+	table map[int][]float64
+}
+
+// Defaults are the bar and plates of a commercial gym in unit.
+func Defaults(unit string) (float64, string) {
+	if unit == "lb" {
+		return 45, "45x8 35x2 25x2 10x4 5x2 2.5x2"
+	}
+	return 20, "25x8 20x4 15x2 10x2 5x2 2.5x2 1.25x2"
+}
+
+// Parse reads an inventory of plates for a bar.
+func Parse(bar float64, spec string) (Inventory, error) {
+	if bar < 0 {
+		return Inventory{}, fmt.Errorf("bar weight must not be negative")
+	}
+
+	inv := Inventory{Bar: bar}
+	for _, f := range strings.FieldsFunc(spec, func(r rune) bool { return r == ' ' || r == ',' || r == ';' }) {
+		size, count, counted := strings.Cut(strings.ToLower(f), "x")
+		w, err := strconv.ParseFloat(size, 64)
+		if err != nil || w <= 0 {
+			return Inventory{}, fmt.Errorf("plates: bad size in %q", f)
+		}
+		n := 2
+		if counted {
+			n, err = strconv.Atoi(count)
+			if err != nil || n < 2 {
+				return Inventory{}, fmt.Errorf("plates: %q needs a count of at least 2, plates go on in pairs", f)
+			}
+		}
+		inv.Plates = append(inv.Plates, Plate{Weight: w, PerSide: n / 2})
+	}
+	if len(inv.Plates) == 0 {
+		return Inventory{}, fmt.Errorf("plates: no plates given")
+	}
+
+	slices.SortFunc(inv.Plates, func(a, b Plate) int { return cents(b.Weight) - cents(a.Weight) })
+	inv.table = inv.buildLoads()
+	return inv, nil
+}
+
+// Load finds the plates for one side that bring the bar closest to weight
+// without going over, heaviest first. short is what is missing to weight, 0
+// if it can be loaded exactly. ok is false for a weight below the bar.
+func (inv Inventory) Load(weight float64) (perSide []float64, short float64, ok bool) {
+	target := cents((weight - inv.Bar) / 2)
+	if target < 0 {
+		return nil, 0, false
+	}
+	loads := inv.loads()
+
+	best := 0
+	for side := range loads {
+		if side <= target && side > best {
+			best = side
+		}
+	}
+	return loads[best], float64(target-best) * 2 / 100, true
+}
+
+// Round is the loadable weight nearest to weight, the lighter one of two
+// equally near. Weights below the bar round to the bar.
+func (inv Inventory) Round(weight float64) float64 {
+	nearest := inv.Bar
+	for side := range inv.loads() {
+		total := inv.Bar + 2*float64(side)/100
+		if d, best := math.Abs(total-weight), math.Abs(nearest-weight); d < best || (d == best && total < nearest) {
+			nearest = total
+		}
+	}
+	return nearest
+}
+
+// Total is the weight of the bar loaded with perSide on each side.
+func (inv Inventory) Total(perSide []float64) float64 {
+	total := inv.Bar
+	for _, p := range perSide {
+		total += 2 * p
+	}
+	return total
+}
+
+// loads maps every weight one side can carry, in hundredths, to the plates
+// making it up. Of several ways the one with the heaviest plates is taken, the
+// way plates are picked off the rack. Inventories not made by Parse have it
+// worked out on every call.
+func (inv Inventory) loads() map[int][]float64 {
+	if inv.table != nil {
+		return inv.table
+	}
+	return inv.buildLoads()
+}
+
+func (inv Inventory) buildLoads() map[int][]float64 {
+	loads := map[int][]float64{0: nil}
+	for _, p := range inv.Plates {
+		for side, plates := range maps.Clone(loads) {
+			for n := 1; n <= p.PerSide; n++ {
+				plates = append(slices.Clip(plates), p.Weight)
+				if old, ok := loads[side+n*cents(p.Weight)]; !ok || slices.Compare(plates, old) > 0 {
+					loads[side+n*cents(p.Weight)] = plates
+				}
+			}
+		}
+	}
+	return loads
+}
+
+// Format shows the plates of one side, e.g. "20 + 10 + 2.5".
+func Format(perSide []float64) string {
+	parts := make([]string, len(perSide))
+	for i, p := range perSide {
+		parts[i] = strconv.FormatFloat(p, 'f', -1, 64)
+	}
+	return strings.Join(parts, " + ")
+}
+
+func cents(w float64) int {
+	return int(math.Round(w * 100))
+}
@@ -0,0 +1,114 @@
+package plates
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	inv, err := Parse(20, "10x2, 20x4;2.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Plate{{20, 2}, {10, 1}, {2.5, 1}}
+	if inv.Bar != 20 || !slices.Equal(inv.Plates, want) {
+		t.Errorf("Parse = %v %v, want 20 %v", inv.Bar, inv.Plates, want)
+	}
+
+	for _, spec := range []string{"", "20x1", "0x2", "-5x2", "abcx2", "20xabc"} {
+		if _, err := Parse(20, spec); err == nil {
+			t.Errorf("Parse(%q) accepted", spec)
+		}
+	}
+	if _, err := Parse(-1, "20x2"); err == nil {
+		t.Error("negative bar accepted")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	kg, err := Parse(Defaults("kg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	home, err := Parse(20, "20x4 10x2 5x2 2.5x2 1.25x2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		inv     Inventory
+		weight  float64
+		perSide []float64
+		short   float64
+	}{
+		{kg, 20, nil, 0},
+		{kg, 100, []float64{25, 15}, 0},
+		{kg, 140, []float64{25, 25, 10}, 0},
+		{kg, 102.5, []float64{25, 15, 1.25}, 0},
+		{kg, 101, []float64{25, 15}, 1},
+		{home, 200, []float64{20, 20, 10, 5, 2.5, 1.25}, 62.5},
+		{home, 62.5, []float64{20, 1.25}, 0},
+	}
+	for _, tt := range tests {
+		perSide, short, ok := tt.inv.Load(tt.weight)
+		if !ok || !slices.Equal(perSide, tt.perSide) || short != tt.short {
+			t.Errorf("Load(%v) = %v, %v, %v, want %v, %v", tt.weight, perSide, short, ok, tt.perSide, tt.short)
+		}
+		if ok && tt.inv.Total(perSide)+short != tt.weight {
+			t.Errorf("Total(%v) + %v != %v", perSide, short, tt.weight)
+		}
+	}
+
+	if perSide, short, ok := kg.Load(15); ok {
+		t.Errorf("Load below the bar = %v, %v, want not ok", perSide, short)
+	}
+}
+
+func TestRound(t *testing.T) {
+	kg, err := Parse(Defaults("kg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lb, err := Parse(Defaults("lb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		inv          Inventory
+		weight, want float64
+	}{
+		{kg, 100, 100},
+		{kg, 101, 100},
+		{kg, 101.5, 102.5},
+		{kg, 101.25, 100}, // equally near, the lighter one
+		{kg, 10, 20},
+		{lb, 226, 225},
+		{lb, 228, 230},
+	}
+	for _, tt := range tests {
+		if got := tt.inv.Round(tt.weight); got != tt.want {
+			t.Errorf("Round(%v) = %v, want %v", tt.weight, got, tt.want)
+		}
+	}
+}
+
+func TestLoadsWithoutParse(t *testing.T) {
+	parsed, err := Parse(20, "20x2 10x2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	literal := Inventory{Bar: 20, Plates: parsed.Plates}
+
+	for _, w := range []float64{20, 40, 60, 80} {
+		if a, b := parsed.Round(w), literal.Round(w); a != b {
+			t.Errorf("Round(%v) = %v parsed, %v literal", w, a, b)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	if got := Format([]float64{20, 10, 2.5}); got != "20 + 10 + 2.5" {
+		t.Errorf("Format = %q", got)
+	}
+}
@@ -0,0 +1,259 @@
+// Package progression proposes new set targets for workout exercises from the
+// sessions performed since their targets last changed.
+//
+// Rules:
+//
+//	linear   all sets done: weight + increment
+//	double   all sets at the top of the rep range: weight + increment and reps
+//	         back to the bottom, otherwise one rep more than last time
+//	percent  set weights are percentages of a training max; all sets done:
+//	         training max + increment
+//
+// Each rule can deload: after deload_after failed sessions in a row weights
+// (and the training max) drop by deload_percent.
+//
+// Warm-ups, performed or planned, are not evaluated and only follow the
+// working sets when weights are scaled.
+package progression
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zmnpl/clift/analytics"
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/notation"
+)
+
+// rules
+const (
+	NONE    = ""
+	LINEAR  = "linear"
+	DOUBLE  = "double"
+	PERCENT = "percent"
+)
+
+var Rules = []string{LINEAR, DOUBLE, PERCENT}
+
+// WEIGHT_STEP is what scaled weights are rounded to, in the unit of the user.
+const WEIGHT_STEP = 0.5
+
+// session outcomes
+const (
+	FAILED = iota
+	PARTIAL
+	DONE
+)
+
+// Proposal are the next targets of a workout exercise.
+type Proposal struct {
+	Sets        []wodb.Set
+	TrainingMax float64
+	Based       time.Time // newest session the proposal comes from
+	Reason      string
+}
+
+// Validate checks the rule settings of we.
+func Validate(we wodb.WorkoutExercise) error {
+	if we.Progression == NONE {
+		return nil
+	}
+	if !slices.Contains(Rules, we.Progression) {
+		return fmt.Errorf("progression must be one of %v, got %q", strings.Join(Rules, ", "), we.Progression)
+	}
+	if we.Increment <= 0 {
+		return fmt.Errorf("increment must be positive")
+	}
+	if we.Progression == DOUBLE && (we.RepsMin < 1 || we.RepsMax < we.RepsMin) {
+		return fmt.Errorf("double progression needs a rep range, e.g. 8-12")
+	}
+	if we.Progression == PERCENT && we.TrainingMax <= 0 {
+		return fmt.Errorf("percent progression needs a training max")
+	}
+	if we.DeloadAfter < 0 {
+		return fmt.Errorf("deload after must not be negative")
+	}
+	if we.DeloadAfter > 0 && (we.DeloadPercent <= 0 || we.DeloadPercent >= 100) {
+		return fmt.Errorf("deload percent must be between 0 and 100")
+	}
+	return nil
+}
+
+// Describe sums the rule of we up in a few words with weights in unit, empty
+// without one.
+func Describe(we wodb.WorkoutExercise, unit string) string {
+	var s string
+	switch we.Progression {
+	case LINEAR:
+		s = fmt.Sprintf("linear +%v", formatWeight(we.Increment, unit))
+	case DOUBLE:
+		s = fmt.Sprintf("double %v-%v +%v", we.RepsMin, we.RepsMax, formatWeight(we.Increment, unit))
+	case PERCENT:
+		s = fmt.Sprintf("percent of %v +%v", formatWeight(we.TrainingMax, unit), formatWeight(we.Increment, unit))
+	default:
+		return ""
+	}
+	if we.DeloadAfter > 0 {
+		s += fmt.Sprintf(", -%v%% after %v fails", formatFloat(we.DeloadPercent), we.DeloadAfter)
+	}
+	return s
+}
+
+// Propose evaluates the sessions of we performed after its targets last
+// changed. sets are the performed sets of the exercise in the workout, newest
+// first. There is no proposal without a rule, without new sessions or when the
+// targets stay the same. Scaled weights are rounded in unit.
+func Propose(we wodb.WorkoutExercise, sets []wodb.PerformedSet, unit string) (Proposal, bool) {
+	if we.Progression == NONE || len(we.Sets) == 0 || Validate(we) != nil {
+		return Proposal{}, false
+	}
+
+	recent := make([]wodb.PerformedSet, 0, len(sets))
+	for _, s := range sets {
+		if s.PerformedDate.After(we.ProgressedAt) {
+			recent = append(recent, s)
+		}
+	}
+	sessions := analytics.GroupSessions(recent)
+	if len(sessions) == 0 {
+		return Proposal{}, false
+	}
+
+	p := Proposal{
+		Sets:        slices.Clone(we.Sets),
+		TrainingMax: we.TrainingMax,
+	}
+	for _, s := range sessions[0] {
+		if s.PerformedDate.After(p.Based) {
+			p.Based = s.PerformedDate
+		}
+	}
+
+	failures := 0
+	for _, session := range sessions {
+		if outcome(we, session) != FAILED {
+			break
+		}
+		failures++
+	}
+
+	last := working(sessions[0])
+	switch {
+	case we.DeloadAfter > 0 && failures >= we.DeloadAfter:
+		p.deload(we, unit)
+		p.Reason = fmt.Sprintf("-%v%% after %v failed sessions", formatFloat(we.DeloadPercent), failures)
+
+	case outcome(we, last) == DONE && we.Progression == PERCENT:
+		p.TrainingMax = we.TrainingMax + we.Increment
+		p.scale(p.TrainingMax/we.TrainingMax, unit)
+		p.Reason = fmt.Sprintf("all sets done, training max %v", formatWeight(p.TrainingMax, unit))
+
+	case outcome(we, last) == DONE:
+		for i := range p.Sets {
+			if p.Sets[i].Type == wodb.SET_WARMUP {
+				continue
+			}
+			p.Sets[i].Weight += we.Increment
+			if we.Progression == DOUBLE {
+				p.Sets[i].Reps = we.RepsMin
+			}
+		}
+		p.Reason = fmt.Sprintf("all sets done, +%v", formatWeight(we.Increment, unit))
+
+	case outcome(we, last) == PARTIAL && we.Progression == DOUBLE:
+		j := 0
+		for i := range p.Sets {
+			if p.Sets[i].Type == wodb.SET_WARMUP {
+				continue
+			}
+			reps := p.Sets[i].Reps
+			if j < len(last) {
+				reps = max(reps, last[j].Reps+1)
+			}
+			j++
+			p.Sets[i].Reps = min(max(reps, we.RepsMin), we.RepsMax)
+		}
+		p.Reason = "one more rep"
+	}
+
+	if p.Reason == "" || (slices.Equal(p.Sets, we.Sets) && p.TrainingMax == we.TrainingMax) {
+		return Proposal{}, false
+	}
+	return p, true
+}
+
+// outcome compares a session, ordered by set number, with the targets of we.
+// For double progression a session is done when all sets reach the top of the
+// rep range and only failed when a set misses the bottom.
+func outcome(we wodb.WorkoutExercise, session []wodb.PerformedSet) int {
+	session = working(session)
+	result := DONE
+	i := -1
+	for _, target := range we.Sets {
+		if target.Type == wodb.SET_WARMUP {
+			continue
+		}
+		i++
+		if i >= len(session) || session[i].Weight < target.Weight {
+			return FAILED
+		}
+
+		reps := session[i].Reps
+		if we.Progression == DOUBLE {
+			if reps < we.RepsMin {
+				return FAILED
+			}
+			if reps < we.RepsMax {
+				result = PARTIAL
+			}
+			continue
+		}
+		if reps < target.Reps {
+			return FAILED
+		}
+	}
+	return result
+}
+
+func (p *Proposal) deload(we wodb.WorkoutExercise, unit string) {
+	factor := 1 - we.DeloadPercent/100
+	if we.Progression == PERCENT {
+		p.TrainingMax = Round(we.TrainingMax*factor, unit)
+	}
+	p.scale(factor, unit)
+	if we.Progression == DOUBLE {
+		for i := range p.Sets {
+			if p.Sets[i].Type != wodb.SET_WARMUP {
+				p.Sets[i].Reps = we.RepsMin
+			}
+		}
+	}
+}
+
+// working leaves out the warm-ups of a session.
+func working(session []wodb.PerformedSet) []wodb.PerformedSet {
+	return slices.DeleteFunc(slices.Clone(session), wodb.PerformedSet.IsWarmup)
+}
+
+func (p *Proposal) scale(factor float64, unit string) {
+	for i := range p.Sets {
+		p.Sets[i].Weight = Round(p.Sets[i].Weight*factor, unit)
+	}
+}
+
+// Round rounds kilograms to WEIGHT_STEP in unit.
+func Round(kg float64, unit string) float64 {
+	return notation.ToKg(math.Round(notation.FromKg(kg, unit)/WEIGHT_STEP)*WEIGHT_STEP, unit)
+}
+
+func formatWeight(kg float64, unit string) string {
+	return formatFloat(notation.FromKg(kg, unit))
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
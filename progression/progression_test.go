@@ -0,0 +1,223 @@
+package progression
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+var start = time.Date(2026, 3, 2, 18, 0, 0, 0, time.Local)
+
+// performed makes the sets of session n (days after start) with the given reps
+// at weight, newest sessions are passed first.
+func performed(n int, weight float64, reps ...int) []wodb.PerformedSet {
+	sets := make([]wodb.PerformedSet, len(reps))
+	for i, r := range reps {
+		sets[i] = wodb.PerformedSet{SessionID: uint(n), PerformedDate: start.AddDate(0, 0, n), SetNo: i, Reps: r, Weight: weight}
+	}
+	return sets
+}
+
+func targets(n, reps int, weight float64) []wodb.Set {
+	sets := make([]wodb.Set, n)
+	for i := range sets {
+		sets[i] = wodb.Set{Reps: reps, Weight: weight}
+	}
+	return sets
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		we wodb.WorkoutExercise
+		ok bool
+	}{
+		{wodb.WorkoutExercise{}, true},
+		{wodb.WorkoutExercise{Progression: LINEAR, Increment: 2.5}, true},
+		{wodb.WorkoutExercise{Progression: LINEAR}, false},
+		{wodb.WorkoutExercise{Progression: "wave", Increment: 2.5}, false},
+		{wodb.WorkoutExercise{Progression: DOUBLE, Increment: 2.5, RepsMin: 8, RepsMax: 12}, true},
+		{wodb.WorkoutExercise{Progression: DOUBLE, Increment: 2.5, RepsMin: 12, RepsMax: 8}, false},
+		{wodb.WorkoutExercise{Progression: PERCENT, Increment: 2.5}, false},
+		{wodb.WorkoutExercise{Progression: PERCENT, Increment: 2.5, TrainingMax: 100}, true},
+		{wodb.WorkoutExercise{Progression: LINEAR, Increment: 2.5, DeloadAfter: 3, DeloadPercent: 10}, true},
+		{wodb.WorkoutExercise{Progression: LINEAR, Increment: 2.5, DeloadAfter: 3}, false},
+		{wodb.WorkoutExercise{Progression: LINEAR, Increment: 2.5, DeloadAfter: -1}, false},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.we); (err == nil) != tt.ok {
+			t.Errorf("Validate(%+v) = %v, want ok %v", tt.we, err, tt.ok)
+		}
+	}
+}
+
+func TestPropose(t *testing.T) {
+	linear := wodb.WorkoutExercise{Progression: LINEAR, Increment: 2.5, DeloadAfter: 2, DeloadPercent: 10, Sets: targets(3, 5, 100)}
+	double := wodb.WorkoutExercise{Progression: DOUBLE, Increment: 2.5, RepsMin: 8, RepsMax: 10, Sets: targets(2, 8, 50)}
+	percent := wodb.WorkoutExercise{Progression: PERCENT, Increment: 5, TrainingMax: 100, Sets: targets(1, 5, 80)}
+
+	tests := []struct {
+		name string
+		we   wodb.WorkoutExercise
+		sets []wodb.PerformedSet
+		want []wodb.Set // nil for no proposal
+		tm   float64
+	}{
+		{"linear done", linear, performed(1, 100, 5, 5, 5), targets(3, 5, 102.5), 0},
+		{"linear failed once", linear, performed(1, 100, 5, 5, 4), nil, 0},
+		{"linear deload", linear, slices.Concat(performed(2, 100, 5, 4, 4), performed(1, 100, 5, 5, 3)), targets(3, 5, 90), 0},
+		{"linear missing set", linear, performed(1, 100, 5, 5), nil, 0},
+		{"linear lighter", linear, performed(1, 95, 5, 5, 5), nil, 0},
+		{"double partial", double, performed(1, 50, 9, 8), []wodb.Set{{Reps: 10, Weight: 50}, {Reps: 9, Weight: 50}}, 0},
+		{"double done", double, performed(1, 50, 10, 10), targets(2, 8, 52.5), 0},
+		{"double failed", double, performed(1, 50, 9, 7), nil, 0},
+		{"percent done", percent, performed(1, 80, 5), targets(1, 5, 84), 105},
+		{"no sessions", linear, nil, nil, 0},
+	}
+	for _, tt := range tests {
+		p, ok := Propose(tt.we, tt.sets, "kg")
+		if tt.want == nil {
+			if ok {
+				t.Errorf("%v: proposed %v (%v)", tt.name, p.Sets, p.Reason)
+			}
+			continue
+		}
+		if !ok || !slices.Equal(p.Sets, tt.want) {
+			t.Errorf("%v: Propose = %v, %v, want %v", tt.name, p.Sets, ok, tt.want)
+		}
+		if tt.tm != 0 && p.TrainingMax != tt.tm {
+			t.Errorf("%v: training max %v, want %v", tt.name, p.TrainingMax, tt.tm)
+		}
+	}
+}
+
+func TestProposeSinceProgressed(t *testing.T) {
+	we := wodb.WorkoutExercise{Progression: LINEAR, Increment: 2.5, Sets: targets(1, 5, 100), ProgressedAt: start.AddDate(0, 0, 1)}
+
+	if p, ok := Propose(we, performed(1, 100, 5), "kg"); ok {
+		t.Errorf("session before the last progression proposed %v", p.Sets)
+	}
+	p, ok := Propose(we, performed(2, 100, 5), "kg")
+	if !ok || !p.Based.Equal(start.AddDate(0, 0, 2)) {
+		t.Errorf("Propose based on %v, %v", p.Based, ok)
+	}
+}
+
+func TestProposeWarmups(t *testing.T) {
+	sets := append([]wodb.Set{{Reps: 10, Weight: 20, Type: wodb.SET_WARMUP}}, targets(2, 5, 100)...)
+	we := wodb.WorkoutExercise{Progression: LINEAR, Increment: 5, Sets: sets}
+
+	warmup := wodb.PerformedSet{SessionID: 1, PerformedDate: start.AddDate(0, 0, 1), SetNo: -1, Reps: 3, Weight: 20, Type: wodb.SET_WARMUP}
+	p, ok := Propose(we, append(performed(1, 100, 5, 5), warmup), "kg")
+	want := append([]wodb.Set{{Reps: 10, Weight: 20, Type: wodb.SET_WARMUP}}, targets(2, 5, 105)...)
+	if !ok || !slices.Equal(p.Sets, want) {
+		t.Errorf("Propose = %v, %v, want %v", p.Sets, ok, want)
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		kg   float64
+		unit string
+		want float64
+	}{
+		{100.2, "kg", 100},
+		{100.3, "kg", 100.5},
+		{102.06, "lb", 102.058},
+	}
+	for _, tt := range tests {
+		if got := Round(tt.kg, tt.unit); got-tt.want > 0.001 || tt.want-got > 0.001 {
+			t.Errorf("Round(%v, %v) = %v, want %v", tt.kg, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestScheme(t *testing.T) {
+	scheme, err := ParseScheme("65x5, 75%x5 85x5+")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []SchemeSet{{65, 5, false}, {75, 5, false}, {85, 5, true}}
+	if !slices.Equal(scheme, want) {
+		t.Errorf("ParseScheme = %v, want %v", scheme, want)
+	}
+
+	sets := ApplyScheme(scheme, 101, "kg")
+	wantSets := []wodb.Set{{Reps: 5, Weight: 65.5}, {Reps: 5, Weight: 76}, {Reps: 5, Weight: 86, Type: wodb.SET_AMRAP}}
+	if !slices.Equal(sets, wantSets) {
+		t.Errorf("ApplyScheme = %v, want %v", sets, wantSets)
+	}
+
+	for _, s := range []string{"65", "0x5", "65x0", "ax5", "65x5++"} {
+		if _, err := ParseScheme(s); err == nil {
+			t.Errorf("ParseScheme(%q) accepted", s)
+		}
+	}
+}
+
+func TestWarmUps(t *testing.T) {
+	ramp, err := ParseRamp(DEFAULT_RAMP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []SchemeSet{{0, 10, false}, {40, 5, false}, {60, 3, false}, {80, 1, false}}; !slices.Equal(ramp, want) {
+		t.Errorf("ParseRamp = %v, want %v", ramp, want)
+	}
+	if spaced, err := ParseRamp("bar × 10, 40 x 5"); err != nil || len(spaced) != 2 {
+		t.Errorf("ParseRamp with spaces = %v, %v", spaced, err)
+	}
+	for _, s := range []string{"100x1", "barx0", "x5"} {
+		if _, err := ParseRamp(s); err == nil {
+			t.Errorf("ParseRamp(%q) accepted", s)
+		}
+	}
+
+	// like plates.Inventory.Round, never lighter than the bar
+	fives := func(w float64) float64 { return max(20, float64(int(w/5)*5)) }
+	tests := []struct {
+		working wodb.Set
+		bar     float64
+		weights []float64
+	}{
+		{wodb.Set{Reps: 5, Weight: 100}, 20, []float64{20, 40, 60, 80}},
+		{wodb.Set{Reps: 5, Weight: 40}, 20, []float64{20, 30}}, // 40% is the bar again
+		{wodb.Set{Reps: 5, Weight: 20}, 20, nil},
+		{wodb.Set{Reps: 5, Weight: 50}, 0, []float64{20, 30, 40}},
+	}
+	for _, tt := range tests {
+		sets := WarmUps(ramp, tt.working, tt.bar, fives)
+		var weights []float64
+		for _, s := range sets {
+			if s.Type != wodb.SET_WARMUP {
+				t.Errorf("warm-up of type %q", s.Type)
+			}
+			weights = append(weights, s.Weight)
+		}
+		if !slices.Equal(weights, tt.weights) {
+			t.Errorf("WarmUps(%v, bar %v) = %v, want %v", tt.working.Weight, tt.bar, weights, tt.weights)
+		}
+	}
+}
+
+func TestSuggestLoads(t *testing.T) {
+	rated := performed(1, 100, 5)
+	rated[0].RPE = 8
+	goal := []wodb.Set{{Reps: 5, Weight: 90, TargetRPE: 8}, {Reps: 3, Weight: 90, TargetRPE: 9}, {Reps: 8, Weight: 60}}
+
+	p, ok := SuggestLoads(goal, rated, "kg")
+	if !ok {
+		t.Fatal("no suggestion")
+	}
+	want := []wodb.Set{{Reps: 5, Weight: 100, TargetRPE: 8}, {Reps: 3, Weight: 110, TargetRPE: 9}, {Reps: 8, Weight: 60}}
+	if !slices.Equal(p.Sets, want) {
+		t.Errorf("SuggestLoads = %v, want %v", p.Sets, want)
+	}
+
+	if _, ok := SuggestLoads(p.Sets, rated, "kg"); ok {
+		t.Error("suggested the same loads again")
+	}
+	if _, ok := SuggestLoads(goal, performed(1, 100, 5), "kg"); ok {
+		t.Error("suggested loads without a rated session")
+	}
+}
@@ -0,0 +1,40 @@
+package progression
+
+import (
+	"fmt"
+
+	"github.com/zmnpl/clift/analytics"
+	wodb "github.com/zmnpl/clift/db"
+)
+
+// SuggestLoads sets the weights of the targets with a target RPE to what the
+// one rep max estimated from the last rated session allows for their reps.
+// history are the performed sets of the exercise, newest first. Loads are
+// rounded in unit. There is no suggestion without such targets, without a
+// rated session or when the weights stay the same.
+func SuggestLoads(targets []wodb.Set, history []wodb.PerformedSet, unit string) (Proposal, bool) {
+	e1rm := analytics.RecentE1RM(history)
+	if e1rm <= 0 {
+		return Proposal{}, false
+	}
+
+	p := Proposal{Sets: make([]wodb.Set, len(targets))}
+	changed := false
+	for i, s := range targets {
+		p.Sets[i] = s
+		if s.TargetRPE <= 0 {
+			continue
+		}
+		load := Round(analytics.LoadForRPE(e1rm, s.Reps, s.TargetRPE), unit)
+		if load > 0 && load != s.Weight {
+			p.Sets[i].Weight = load
+			changed = true
+		}
+	}
+	if !changed {
+		return Proposal{}, false
+	}
+
+	p.Reason = fmt.Sprintf("loads for the target RPE, e1RM %v", formatWeight(Round(e1rm, unit), unit))
+	return p, true
+}
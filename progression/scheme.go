@@ -0,0 +1,57 @@
+package progression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+// SchemeSet is one set of a week's scheme: reps at a percentage of the
+// training max.
+type SchemeSet struct {
+	Percent float64
+	Reps    int
+	AMRAP   bool // as many reps as possible, at least Reps
+}
+
+// ParseScheme reads a scheme like "65x5 75x5 85x5+", sets separated by spaces
+// or commas. A % behind the percentage and a trailing + (as many reps as
+// possible) are accepted.
+func ParseScheme(scheme string) ([]SchemeSet, error) {
+	fields := strings.FieldsFunc(scheme, func(r rune) bool { return r == ' ' || r == ',' || r == ';' })
+
+	sets := make([]SchemeSet, 0, len(fields))
+	for _, f := range fields {
+		pct, reps, ok := strings.Cut(strings.ToLower(f), "x")
+		if !ok {
+			return nil, fmt.Errorf("scheme: %q is not percent x reps, e.g. 75x5", f)
+		}
+
+		p, err := strconv.ParseFloat(strings.TrimSuffix(pct, "%"), 64)
+		if err != nil || p <= 0 {
+			return nil, fmt.Errorf("scheme: invalid percentage in %q", f)
+		}
+		r, err := strconv.Atoi(strings.TrimSuffix(reps, "+"))
+		if err != nil || r <= 0 {
+			return nil, fmt.Errorf("scheme: invalid reps in %q", f)
+		}
+
+		sets = append(sets, SchemeSet{Percent: p, Reps: r, AMRAP: strings.HasSuffix(reps, "+")})
+	}
+	return sets, nil
+}
+
+// ApplyScheme turns a scheme into set targets for a training max, weights
+// rounded in unit.
+func ApplyScheme(scheme []SchemeSet, trainingMax float64, unit string) []wodb.Set {
+	sets := make([]wodb.Set, len(scheme))
+	for i, s := range scheme {
+		sets[i] = wodb.Set{Reps: s.Reps, Weight: Round(trainingMax*s.Percent/100, unit)}
+		if s.AMRAP {
+			sets[i].Type = wodb.SET_AMRAP
+		}
+	}
+	return sets
+}
@@ -0,0 +1,68 @@
+package progression
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+// DEFAULT_RAMP warms up to a working set with the empty bar for 10 reps,
+// then 40, 60 and 80 percent of the working weight.
+const DEFAULT_RAMP = "barx10 40x5 60x3 80x1"
+
+var timesSpaces = regexp.MustCompile(`\s*x\s*`)
+
+// ParseRamp reads a warm-up ramp, written like a scheme of percentages of the
+// working weight ("40x5 60x3 80x1"). "bar" in place of a percentage is the
+// empty bar, it counts as 0 percent.
+func ParseRamp(ramp string) ([]SchemeSet, error) {
+	ramp = strings.ReplaceAll(strings.ToLower(ramp), "×", "x")
+	ramp = timesSpaces.ReplaceAllString(ramp, "x")
+	fields := strings.FieldsFunc(ramp, func(r rune) bool { return r == ' ' || r == ',' || r == ';' })
+
+	sets := make([]SchemeSet, 0, len(fields))
+	for _, f := range fields {
+		if reps, ok := strings.CutPrefix(f, "barx"); ok {
+			r, err := strconv.Atoi(reps)
+			if err != nil || r <= 0 {
+				return nil, fmt.Errorf("warm-up: invalid reps in %q", f)
+			}
+			sets = append(sets, SchemeSet{Reps: r})
+			continue
+		}
+
+		s, err := ParseScheme(f)
+		if err != nil {
+			return nil, fmt.Errorf("warm-up: %v", strings.TrimPrefix(err.Error(), "scheme: "))
+		}
+		if s[0].Percent >= 100 {
+			return nil, fmt.Errorf("warm-up: %q is not lighter than the working set", f)
+		}
+		sets = append(sets, s[0])
+	}
+	return sets, nil
+}
+
+// WarmUps plans the warm-up sets ramping up to the working set. bar is the
+// weight of the empty bar, 0 if the exercise has none; loadable rounds a
+// weight to one that can be put together. Steps that come out no heavier than
+// the one before or not lighter than the working set are left out.
+func WarmUps(ramp []SchemeSet, working wodb.Set, bar float64, loadable func(float64) float64) []wodb.Set {
+	sets := make([]wodb.Set, 0, len(ramp))
+	last := 0.0
+	for _, step := range ramp {
+		weight := bar
+		if step.Percent > 0 {
+			weight = loadable(working.Weight * step.Percent / 100)
+		}
+		if weight <= 0 || weight <= last || weight >= working.Weight {
+			continue
+		}
+		sets = append(sets, wodb.Set{Reps: step.Reps, Weight: weight, Type: wodb.SET_WARMUP})
+		last = weight
+	}
+	return sets
+}
@@ -0,0 +1,125 @@
+package common
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// braille dot bits by column and row within a cell of 2x4 dots
+var brailleDots = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+const chartLabelWidth = 8
+
+// LineChart plots ys over xs as a line of braille dots, width x height cells
+// including the y axis labels on the left. xs must be ascending.
+func LineChart(xs, ys []float64, width, height int) string {
+	plotWidth := max(width-chartLabelWidth-1, 1)
+	height = max(height, 1)
+	if len(xs) == 0 || len(xs) != len(ys) {
+		return strings.Repeat("\n", height-1)
+	}
+
+	dotsX, dotsY := plotWidth*2, height*4
+	grid := make([][]rune, height)
+	for i := range grid {
+		grid[i] = make([]rune, plotWidth)
+	}
+	set := func(x, y int) {
+		if x >= 0 && x < dotsX && y >= 0 && y < dotsY {
+			grid[y/4][x/2] |= brailleDots[x%2][y%4]
+		}
+	}
+
+	minX, maxX := xs[0], xs[len(xs)-1]
+	minY, maxY := slices.Min(ys), slices.Max(ys)
+	if maxY == minY {
+		minY, maxY = minY-1, maxY+1
+	}
+
+	toDot := func(i int) (int, int) {
+		x := dotsX / 2
+		if maxX > minX {
+			x = int(math.Round((xs[i] - minX) / (maxX - minX) * float64(dotsX-1)))
+		}
+		y := dotsY - 1 - int(math.Round((ys[i]-minY)/(maxY-minY)*float64(dotsY-1)))
+		return x, y
+	}
+
+	px, py := toDot(0)
+	set(px, py)
+	for i := 1; i < len(xs); i++ {
+		x, y := toDot(i)
+		line(px, py, x, y, set)
+		px, py = x, y
+	}
+
+	sb := &strings.Builder{}
+	for row := range grid {
+		label, tick := "", "│"
+		switch row {
+		case 0:
+			label, tick = formatAxis(maxY), "┤"
+		case height - 1:
+			label, tick = formatAxis(minY), "┤"
+		}
+		sb.WriteString(strings.Repeat(" ", max(chartLabelWidth-len(label), 0)) + label + tick)
+		for _, r := range grid[row] {
+			if r == 0 {
+				sb.WriteRune(' ')
+			} else {
+				sb.WriteRune(0x2800 + r)
+			}
+		}
+		if row < height-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// line draws from (x0,y0) to (x1,y1) with Bresenham's algorithm.
+func line(x0, y0, x1, y1 int, set func(x, y int)) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		set(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func formatAxis(v float64) string {
+	if math.Abs(v) >= 1000 {
+		return strconv.FormatFloat(v/1000, 'f', 1, 64) + "k"
+	}
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
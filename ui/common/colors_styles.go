@@ -1,6 +1,8 @@
 package common
 
 import (
+	"fmt"
+
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -15,8 +17,8 @@ func GetContentHeight(screenHeight int) int {
 var WINDOW_HEIGHT = 10
 var WINDOW_WIDTH = 10
 
-var FilterCursorStyle = lipgloss.NewStyle().Background(Theme.Yellow)
-var FilterPromptStyle = lipgloss.NewStyle().Foreground(Theme.Yellow)
+var FilterCursorStyle lipgloss.Style
+var FilterPromptStyle lipgloss.Style
 
 func ListItemStyle() list.DefaultDelegate {
 	d := list.NewDefaultDelegate()
@@ -207,17 +209,52 @@ var Terafox = Colorscheme{
 	Bright_white:   lipgloss.Color("#eeeeee"),
 }
 
+var Themes = map[string]Colorscheme{
+	"hachikoo":   Hachikoo,
+	"blackmetal": Blackmetal,
+	"terafox":    Terafox,
+}
+
 var Theme = Hachikoo
 
 var (
-	StatusGood   = lipgloss.NewStyle().Foreground(Theme.Cyan).Width(7).Align(lipgloss.Left)
-	StatusBad    = lipgloss.NewStyle().Foreground(Theme.Magenta).Width(7).Align(lipgloss.Left)
-	StatusCenter = lipgloss.NewStyle().Foreground(Theme.Cyan).Width(70).Align(lipgloss.Left)
+	StatusGood   lipgloss.Style
+	StatusBad    lipgloss.Style
+	StatusCenter lipgloss.Style
 
-	PrimaryExStlye = lipgloss.NewStyle().Foreground(Theme.Cyan)
-	FocusedStyle   = lipgloss.NewStyle().Foreground(Theme.Red)
-	BlurredStyle   = lipgloss.NewStyle().Foreground(Theme.Bright_yellow)
-	HeaderStyle    = lipgloss.NewStyle().Foreground(Theme.Red).Width(80)
+	PrimaryExStlye lipgloss.Style
+	FocusedStyle   lipgloss.Style
+	BlurredStyle   lipgloss.Style
+	HeaderStyle    lipgloss.Style
 	NoStyle        = lipgloss.NewStyle()
 	Margin         = lipgloss.NewStyle().Margin(1, 1, 1, 1)
 )
+
+func init() {
+	applyTheme()
+}
+
+// SetTheme switches to one of the Themes and rebuilds all styles from it.
+func SetTheme(name string) error {
+	scheme, ok := Themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme: %v", name)
+	}
+	Theme = scheme
+	applyTheme()
+	return nil
+}
+
+func applyTheme() {
+	StatusGood = lipgloss.NewStyle().Foreground(Theme.Cyan).Width(7).Align(lipgloss.Left)
+	StatusBad = lipgloss.NewStyle().Foreground(Theme.Magenta).Width(7).Align(lipgloss.Left)
+	StatusCenter = lipgloss.NewStyle().Foreground(Theme.Cyan).Width(70).Align(lipgloss.Left)
+
+	PrimaryExStlye = lipgloss.NewStyle().Foreground(Theme.Cyan)
+	FocusedStyle = lipgloss.NewStyle().Foreground(Theme.Red)
+	BlurredStyle = lipgloss.NewStyle().Foreground(Theme.Bright_yellow)
+	HeaderStyle = lipgloss.NewStyle().Foreground(Theme.Red).Width(80)
+
+	FilterCursorStyle = lipgloss.NewStyle().Background(Theme.Yellow)
+	FilterPromptStyle = lipgloss.NewStyle().Foreground(Theme.Yellow)
+}
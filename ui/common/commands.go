@@ -3,15 +3,21 @@ package common
 import (
 	"fmt"
 	"log"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
-	"github.com/charmbracelet/lipgloss"
+	"github.com/zmnpl/clift/analytics"
+	"github.com/zmnpl/clift/config"
 	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/export"
+	"github.com/zmnpl/clift/library"
+	"github.com/zmnpl/clift/notation"
+	"github.com/zmnpl/clift/progression"
 )
 
 type StatusMsg struct {
@@ -36,9 +42,49 @@ type MsgWorkoutSingleReload struct {
 	Err     error
 }
 
-type MsgPerformedSetsLoaded struct {
-	PerformedSets []wodb.PerformedSet
-	Err           error
+type MsgExerciseHistory struct {
+	Sets []wodb.PerformedSet
+	Err  error
+}
+
+// MsgLastPerformance carries the sets of the last session of an exercise.
+type MsgLastPerformance struct {
+	ExerciseID string
+	Sets       []wodb.PerformedSet
+	Err        error
+}
+
+type MsgMuscleVolume struct {
+	Volumes []analytics.MuscleVolume
+	Err     error
+}
+
+type MsgJournalPage struct {
+	Sets     []wodb.PerformedSet
+	Sessions []wodb.Session
+	Offset   int
+	Limit    int
+	Err      error
+}
+
+// MsgJournalChanged reports a change to logged sets. Undo holds how they
+// were before, to be restored by RestorePerformedSets; it is empty for the
+// restore itself.
+type MsgJournalChanged struct {
+	Status string
+	Undo   JournalChange
+	Err    error
+}
+
+// JournalChange holds logged sets as they were before a change, and the
+// sessions deleted along with them.
+type JournalChange struct {
+	Sets     []wodb.PerformedSet
+	Sessions []wodb.Session
+}
+
+func (c JournalChange) IsEmpty() bool {
+	return len(c.Sets) == 0 && len(c.Sessions) == 0
 }
 
 type MsgExerciseAddedToWorkout struct {
@@ -50,12 +96,51 @@ type MsgUpdatedWorkoutExercise struct {
 	Err error
 }
 
+type MsgPrograms struct {
+	Programs []wodb.Program
+	Err      error
+}
+
+type MsgProgram struct {
+	Program wodb.Program
+	Err     error
+}
+
+// MsgProgramChanged tells that a program was edited.
+type MsgProgramChanged struct {
+	Status string
+	Err    error
+}
+
+// MsgLibrary carries the programs of the built-in library.
+type MsgLibrary struct {
+	Programs []library.Program
+	Err      error
+}
+
+// MsgToday carries the active program, nil if there is none.
+type MsgToday struct {
+	Program *wodb.Program
+	Err     error
+}
+
+// MsgProgressions carries the proposed targets by workout exercise ID. Loads
+// suggested for target RPEs only hold for the session and are not saved.
+type MsgProgressions struct {
+	Proposals map[uint]progression.Proposal
+	Loads     map[uint]progression.Proposal
+	Err       error
+}
+
 type MsgExerciseLogged struct {
-	Err error
+	Status string
+	Err    error
 }
 
 type MsgExerciseID string
 
+type MsgWorkoutID uint
+
 type MsgDate time.Time
 
 type MsgPerformedSets struct {
@@ -121,6 +206,12 @@ func SendExerciseID(id string) func() tea.Msg {
 	}
 }
 
+func SendWorkoutID(id uint) func() tea.Msg {
+	return func() tea.Msg {
+		return MsgWorkoutID(id)
+	}
+}
+
 func SendPerformedSets(sets []SetInput, weid uint) func() tea.Msg {
 	return func() tea.Msg {
 		return MsgPerformedSets{
@@ -130,33 +221,37 @@ func SendPerformedSets(sets []SetInput, weid uint) func() tea.Msg {
 	}
 }
 
-func ReloadExercises() tea.Msg {
-	exercises, err := wodb.Instance().GetAllExercises()
-	if err != nil {
-		log.Fatalf("Failed to get all exercises: %v", err) // TODO: maybe pass error to ui
-	}
+func ReloadExercises(store wodb.Store) func() tea.Msg {
+	return func() tea.Msg {
+		exercises, err := store.GetAllExercises()
+		if err != nil {
+			log.Fatalf("Failed to get all exercises: %v", err) // TODO: maybe pass error to ui
+		}
 
-	return MsgExercisesReload{
-		Exercises: exercises,
-		Err:       err,
+		return MsgExercisesReload{
+			Exercises: exercises,
+			Err:       err,
+		}
 	}
 }
 
-func ReloadWorkouts() tea.Msg {
-	workouts, err := wodb.Instance().GetAllWorkouts()
-	if err != nil {
-		log.Fatalf("Failed to get all workout: %v", err)
-	}
+func ReloadWorkouts(store wodb.Store) func() tea.Msg {
+	return func() tea.Msg {
+		workouts, err := store.GetAllWorkouts()
+		if err != nil {
+			log.Fatalf("Failed to get all workout: %v", err)
+		}
 
-	return MsgWorkoutsReload{
-		Workouts: workouts,
-		Err:      err,
+		return MsgWorkoutsReload{
+			Workouts: workouts,
+			Err:      err,
+		}
 	}
 }
 
-func ReloadWorkoutSingle(wid uint) func() tea.Msg {
+func ReloadWorkoutSingle(store wodb.Store, wid uint) func() tea.Msg {
 	return func() tea.Msg {
-		workout, err := wodb.Instance().GetWorkoutWithExercises(wid)
+		workout, err := store.GetWorkoutWithExercises(wid)
 		if err != nil {
 			log.Fatalf("Could not reload workout ID: %v (%v)", wid, err)
 		}
@@ -168,9 +263,9 @@ func ReloadWorkoutSingle(wid uint) func() tea.Msg {
 	}
 }
 
-func AddWorkoutExercise(workoutID uint, eid string) func() tea.Msg {
+func AddWorkoutExercise(store wodb.Store, workoutID uint, eid string) func() tea.Msg {
 	return func() tea.Msg {
-		ex, err := wodb.Instance().AddExerciseToWorkout(workoutID, eid, "")
+		ex, err := store.AddExerciseToWorkout(workoutID, eid, "")
 		if err != nil {
 			log.Fatalf("Could not add exercise (%v) to workout (%v): %v", eid, workoutID, err)
 		}
@@ -181,17 +276,67 @@ func AddWorkoutExercise(workoutID uint, eid string) func() tea.Msg {
 	}
 }
 
-func RemoveWorkoutExercise(weID uint) func() tea.Msg {
+// ProposeProgressions evaluates the progression rules of the exercises of
+// workout and suggests loads for targets with an RPE. Weights of barbell
+// exercises are rounded to what the plates allow.
+func ProposeProgressions(store wodb.Store, settings Settings, workout wodb.Workout) func() tea.Msg {
+	return func() tea.Msg {
+		proposals := make(map[uint]progression.Proposal)
+		loads := make(map[uint]progression.Proposal)
+		for _, we := range workout.WorkoutExercises {
+			if we.Progression != progression.NONE {
+				filter := wodb.SetFilter{From: we.ProgressedAt, ExerciseIDs: []string{we.ExerciseID}, WorkoutID: we.WorkoutID}
+				sets, err := store.GetPerformedSets(filter, 0, 0)
+				if err != nil {
+					return MsgProgressions{Err: fmt.Errorf("Error loading sets of %v: %v", we.Exercise.GetName(), err)}
+				}
+				if p, ok := progression.Propose(we, sets, settings.Units); ok {
+					settings.RoundTargetsToPlates(we.Exercise, p.Sets, we.Sets)
+					proposals[we.ID] = p
+					we.Sets = p.Sets
+				}
+			}
+
+			if !slices.ContainsFunc(we.Sets, func(s wodb.Set) bool { return s.TargetRPE > 0 }) {
+				continue
+			}
+			history, err := store.GetPerformedSets(wodb.SetFilter{ExerciseIDs: []string{we.ExerciseID}}, RPE_HISTORY, 0)
+			if err != nil {
+				return MsgProgressions{Err: fmt.Errorf("Error loading sets of %v: %v", we.Exercise.GetName(), err)}
+			}
+			if p, ok := progression.SuggestLoads(we.Sets, history, settings.Units); ok {
+				settings.RoundTargetsToPlates(we.Exercise, p.Sets, nil)
+				loads[we.ID] = p
+			}
+		}
+		return MsgProgressions{Proposals: proposals, Loads: loads}
+	}
+}
+
+// RPE_HISTORY is how many of the latest sets of an exercise are searched for
+// a session rated by RPE.
+const RPE_HISTORY = 100
+
+// SaveProgression stores the progression rule of a workout exercise.
+func SaveProgression(store wodb.Store, we wodb.WorkoutExercise) func() tea.Msg {
 	return func() tea.Msg {
 		return MsgUpdatedWorkoutExercise{
-			Err: wodb.Instance().RemoveExerciseFromWorkout(weID),
+			Err: store.UpdateProgression(we),
 		}
 	}
 }
 
-func NewWorkout(name string) func() tea.Msg {
+func RemoveWorkoutExercise(store wodb.Store, weID uint) func() tea.Msg {
 	return func() tea.Msg {
-		_, err := wodb.Instance().CreateWorkout(name)
+		return MsgUpdatedWorkoutExercise{
+			Err: store.RemoveExerciseFromWorkout(weID),
+		}
+	}
+}
+
+func NewWorkout(store wodb.Store, name string) func() tea.Msg {
+	return func() tea.Msg {
+		_, err := store.CreateWorkout(name)
 		if err != nil {
 			return StatusMsg{Status: "", Err: fmt.Errorf("Error creating workout: %v", err)}
 		}
@@ -200,9 +345,9 @@ func NewWorkout(name string) func() tea.Msg {
 	}
 }
 
-func RemoveWorkout(id uint) func() tea.Msg {
+func RemoveWorkout(store wodb.Store, id uint) func() tea.Msg {
 	return func() tea.Msg {
-		err := wodb.Instance().RemoveWorkout(id)
+		err := store.RemoveWorkout(id)
 		if err != nil {
 			return StatusMsg{Status: "", Err: fmt.Errorf("Error removing workout: %v", err)}
 		}
@@ -211,63 +356,140 @@ func RemoveWorkout(id uint) func() tea.Msg {
 	}
 }
 
-func LoadPerformedSets() tea.Msg {
-	performedSets, err := wodb.Instance().GetAllPerformedSets()
-	if err != nil {
-		log.Printf("Could not get performed sets: %v", err)
+func LoadExerciseHistory(store wodb.Store, exerciseID string) func() tea.Msg {
+	return func() tea.Msg {
+		sets, err := store.GetPerformedSets(wodb.SetFilter{ExerciseIDs: []string{exerciseID}}, 0, 0)
+		return MsgExerciseHistory{
+			Sets: sets,
+			Err:  err,
+		}
 	}
+}
 
-	return MsgPerformedSetsLoaded{
-		PerformedSets: performedSets,
-		Err:           err,
+// LoadLastPerformance loads the sets of the last session of an exercise up to
+// and including the day of datum.
+func LoadLastPerformance(store wodb.Store, exerciseID string, datum time.Time) func() tea.Msg {
+	return func() tea.Msg {
+		day := time.Date(datum.Year(), datum.Month(), datum.Day(), 0, 0, 0, 0, datum.Location())
+		filter := wodb.SetFilter{To: day, ExerciseIDs: []string{exerciseID}}
+		sets, err := store.GetPerformedSets(filter, 50, 0)
+		return MsgLastPerformance{
+			ExerciseID: exerciseID,
+			Sets:       analytics.LastSession(sets),
+			Err:        err,
+		}
+	}
+}
+
+// LoadMuscleVolume loads the volume per muscle of the last weeks, all of them
+// for weeks <= 0.
+func LoadMuscleVolume(store wodb.Store, weeks int) func() tea.Msg {
+	return func() tea.Msg {
+		fromWeek := ""
+		if weeks > 0 {
+			fromWeek = analytics.WeeksBack(time.Now(), weeks)
+		}
+		weekly, err := store.GetWeeklyVolume(fromWeek)
+		if err != nil {
+			return MsgMuscleVolume{Err: err}
+		}
+		exercises, err := store.GetAllExercises()
+		return MsgMuscleVolume{
+			Volumes: analytics.MuscleVolumes(weekly, exercises),
+			Err:     err,
+		}
 	}
 }
 
-func MakeJournal(performedSets []wodb.PerformedSet) table.Model {
+// LoadJournalPage loads up to limit sets matching filter, starting at
+// offset, together with their sessions.
+func LoadJournalPage(store wodb.Store, filter wodb.SetFilter, offset, limit int) func() tea.Msg {
+	return func() tea.Msg {
+		sets, err := store.GetPerformedSets(filter, limit, offset)
+		if err != nil {
+			return MsgJournalPage{Err: err}
+		}
+
+		ids := make([]uint, 0, 10)
+		for _, s := range sets {
+			if s.SessionID != 0 && !slices.Contains(ids, s.SessionID) {
+				ids = append(ids, s.SessionID)
+			}
+		}
+		sessions, err := store.GetSessions(ids)
 
-	columns := []table.Column{
-		{Title: "Exercise", Width: 30},
-		{Title: "Date", Width: 12},
-		{Title: "Set", Width: 5},
-		{Title: "Reps", Width: 8},
-		{Title: "Weight", Width: 8},
+		return MsgJournalPage{
+			Sets:     sets,
+			Sessions: sessions,
+			Offset:   offset,
+			Limit:    limit,
+			Err:      err,
+		}
+	}
+}
+
+func UpdatePerformedSet(store wodb.Store, old, set wodb.PerformedSet) func() tea.Msg {
+	return func() tea.Msg {
+		err := store.UpdatePerformedSet(set)
+		return MsgJournalChanged{
+			Status: "Set updated",
+			Undo:   JournalChange{Sets: []wodb.PerformedSet{old}},
+			Err:    err,
+		}
 	}
+}
 
-	rows := make([]table.Row, 0, len(performedSets))
-	for _, s := range performedSets {
-		r := table.Row{
-			s.ExerciseID,
-			fmt.Sprintf("%v", s.PerformedDate),
-			fmt.Sprintf("%v", s.SetNo),
-			fmt.Sprintf("%v", s.Reps),
-			fmt.Sprintf("%v", s.Weight),
+// UpdatePerformedSetType stores the changed type of a logged set.
+func UpdatePerformedSetType(store wodb.Store, old, set wodb.PerformedSet) func() tea.Msg {
+	return func() tea.Msg {
+		err := store.UpdatePerformedSet(set)
+		return MsgJournalChanged{
+			Status: "Set type: " + SetTypeName(set.Type),
+			Undo:   JournalChange{Sets: []wodb.PerformedSet{old}},
+			Err:    err,
 		}
-		rows = append(rows, r)
 	}
+}
 
-	t := table.New(
-		table.WithColumns(columns),
-		table.WithRows(rows),
-		table.WithFocused(true),
-		table.WithHeight(20),
-	)
+func DeletePerformedSets(store wodb.Store, sets []wodb.PerformedSet) func() tea.Msg {
+	return func() tea.Msg {
+		ids := make([]uint, len(sets))
+		for i, s := range sets {
+			ids[i] = s.ID
+		}
+		sessions, err := store.DeletePerformedSets(ids)
+		return MsgJournalChanged{
+			Status: fmt.Sprintf("Deleted %v sets", len(sets)),
+			Undo:   JournalChange{Sets: sets, Sessions: sessions},
+			Err:    err,
+		}
+	}
+}
 
-	s := table.DefaultStyles()
-	s.Header = s.Header.
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(Theme.Foreground).
-		BorderBottom(true).
-		Bold(false)
-	s.Selected = s.Selected.
-		Foreground(lipgloss.Color(Theme.Red)).
-		//Background(lipgloss.Color("57")).
-		Bold(false)
-	t.SetStyles(s)
+// DeleteDay deletes everything logged on day, whatever the journal shows,
+// together with its sessions.
+func DeleteDay(store wodb.Store, day time.Time) func() tea.Msg {
+	return func() tea.Msg {
+		sets, sessions, err := store.DeleteDay(day)
+		return MsgJournalChanged{
+			Status: fmt.Sprintf("Deleted %v sets of %v", len(sets), day.Format("Mon 2006-01-02")),
+			Undo:   JournalChange{Sets: sets, Sessions: sessions},
+			Err:    err,
+		}
+	}
+}
 
-	return t
+func RestorePerformedSets(store wodb.Store, change JournalChange) func() tea.Msg {
+	return func() tea.Msg {
+		err := store.RestorePerformedSets(change.Sets, change.Sessions)
+		return MsgJournalChanged{
+			Status: "Undone",
+			Err:    err,
+		}
+	}
 }
 
-func WorkoutToMarkdown(wo wodb.Workout, sessionSets map[uint][]SetInput) func() tea.Msg {
+func WorkoutToMarkdown(settings Settings, wo wodb.Workout, sessionSets map[uint][]SetInput) func() tea.Msg {
 	return func() tea.Msg {
 		sb := &strings.Builder{}
 
@@ -279,7 +501,7 @@ func WorkoutToMarkdown(wo wodb.Workout, sessionSets map[uint][]SetInput) func()
 				if i > 0 {
 					sb.WriteString(" // ")
 				}
-				sb.WriteString(fmt.Sprintf("%v @ %v", set.Reps, set.Weight))
+				sb.WriteString(settings.FormatTarget(set))
 			}
 			sb.WriteString("\n")
 		}
@@ -302,7 +524,7 @@ func MakeDBPerformedSet(set SetInput, setno int, datum time.Time) wodb.Performed
 		reps = 0
 		// TODO - pass error to user maybe
 	}
-	weight, err := strconv.ParseFloat(set.Weight.Value(), 64)
+	weight, unit, err := notation.ParseWeight(set.Weight.Value(), set.units)
 	if err != nil {
 		weight = 0
 	}
@@ -313,13 +535,79 @@ func MakeDBPerformedSet(set SetInput, setno int, datum time.Time) wodb.Performed
 		SetNo:         setno,
 		Reps:          reps,
 		Weight:        weight,
+		Unit:          unit,
 		PerformedDate: datum,
+		Type:          set.Type,
+	}
+	foo.RPE, foo.RIR, _ = notation.ParseEffort(set.RPE.Value())
+
+	if set.Measure != MEASURE_REPS {
+		foo.Reps, foo.Weight, foo.Unit = 0, 0, ""
+		foo.Duration, _ = notation.ParseDuration(set.Duration.Value(), set.durationUnit())
+	}
+	if set.Measure == MEASURE_CARDIO {
+		foo.Distance, _ = notation.ParseDistance(set.Distance.Value())
+		foo.HeartRate, _ = strconv.Atoi(strings.TrimSpace(set.HeartRate.Value()))
+		foo.Calories, _ = strconv.Atoi(strings.TrimSpace(set.Calories.Value()))
 	}
 
 	return foo
 }
 
-func LogSingleExercise(datum time.Time, setInputs []SetInput) func() tea.Msg {
+// exerciseHistory loads all earlier sets of the exercises in sets.
+func exerciseHistory(store wodb.Store, sets []wodb.PerformedSet) ([]wodb.PerformedSet, error) {
+	ids := make([]string, 0, 10)
+	for _, s := range sets {
+		if !slices.Contains(ids, s.ExerciseID) {
+			ids = append(ids, s.ExerciseID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return store.GetPerformedSets(wodb.SetFilter{ExerciseIDs: ids}, 0, 0)
+}
+
+// prStatus names the records the sets just logged in session broke, if any.
+func prStatus(store wodb.Store, settings Settings, session wodb.Session, history, logged []wodb.PerformedSet) string {
+	for i := range logged {
+		logged[i].SessionID = session.ID
+	}
+
+	prs := analytics.NewPRs(settings.E1RMFormula, history, logged)
+	if len(prs) == 0 {
+		return ""
+	}
+
+	exercises, err := store.GetAllExercises()
+	if err != nil {
+		log.Printf("Could not get exercises: %v", err)
+	}
+	name := func(id string) string {
+		if e, ok := wodb.FindExercise(exercises, id); ok {
+			return e.GetName()
+		}
+		return id
+	}
+	return "🏆 New PR! " + analytics.Summary(prs, settings.Units, name)
+}
+
+// OnDay puts the clock time of t on the date of day.
+func OnDay(day, t time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), t.Hour(), t.Minute(), t.Second(), 0, day.Location())
+}
+
+// finishSession moves session to the day of datum and stamps its end.
+func finishSession(session wodb.Session, datum time.Time) wodb.Session {
+	if session.StartedAt.IsZero() {
+		session.StartedAt = time.Now()
+	}
+	session.StartedAt = OnDay(datum, session.StartedAt)
+	session.EndedAt = OnDay(datum, time.Now())
+	return session
+}
+
+func LogSingleExercise(store wodb.Store, settings Settings, session wodb.Session, datum time.Time, setInputs []SetInput) func() tea.Msg {
 	return func() tea.Msg {
 		sets := make([]wodb.PerformedSet, 0, 30)
 		for i, s := range setInputs {
@@ -327,47 +615,35 @@ func LogSingleExercise(datum time.Time, setInputs []SetInput) func() tea.Msg {
 			sets = append(sets, foo)
 		}
 
-		err := wodb.Instance().LogSetsTransaction(sets)
+		history, err := exerciseHistory(store, sets)
+		if err != nil {
+			return MsgExerciseLogged{Err: fmt.Errorf("error loading your history: %v", err.Error())}
+		}
+
+		session = finishSession(session, datum)
+		err = store.LogSession(&session, sets)
 		if err != nil {
 			return MsgExerciseLogged{Err: fmt.Errorf("error logging your sets: %v", err.Error())}
 		}
-		return MsgExerciseLogged{}
+		return MsgExerciseLogged{Status: prStatus(store, settings, session, history, sets)}
 	}
 }
 
-func UpdateWorkoutExerciseSets(weid uint, setInputs []SetInput) func() tea.Msg {
+func UpdateWorkoutExerciseSets(store wodb.Store, weid uint, setInputs []SetInput) func() tea.Msg {
 	return func() tea.Msg {
 		sets := make([]wodb.Set, 0, len(setInputs))
 		for _, v := range setInputs {
-
-			// parse reps and weight
-			// try value first, then placeholder
-			// if 0 reps -> skip
-			// 0 weight is ok
-			reps, err := strconv.Atoi(v.Reps.Value())
-			if err != nil {
-				reps, err = strconv.Atoi(v.Reps.Placeholder)
-				if err != nil || reps == 0 {
-					continue
-				}
+			// values first, then placeholders; sets without reps, time or
+			// distance are skipped, 0 weight is ok
+			set := v.Target()
+			if set.Reps == 0 && set.Duration == 0 && set.Distance == 0 {
+				continue
 			}
-
-			weight, err := strconv.ParseFloat(v.Weight.Value(), 64)
-			if err != nil {
-				weight, err = strconv.ParseFloat(v.Weight.Placeholder, 64)
-				if err != nil {
-					weight = 0
-				}
-			}
-
-			sets = append(sets, wodb.Set{
-				WorkoutExerciseID: weid,
-				Reps:              reps,
-				Weight:            weight,
-			})
+			set.WorkoutExerciseID = weid
+			sets = append(sets, set)
 		}
 
-		err := wodb.Instance().UpdateWorkoutExerciseSets(weid, sets)
+		err := store.UpdateWorkoutExerciseSets(weid, sets)
 		if err != nil {
 			log.Fatalf("Could not update sets for  weid: %v: %v", weid, err)
 		}
@@ -378,7 +654,7 @@ func UpdateWorkoutExerciseSets(weid uint, setInputs []SetInput) func() tea.Msg {
 	}
 }
 
-func LogWorkout(weItems []WeItem, datum time.Time) func() tea.Msg {
+func LogWorkout(store wodb.Store, settings Settings, session wodb.Session, weItems []WeItem, datum time.Time) func() tea.Msg {
 	return func() tea.Msg {
 		sets := make([]wodb.PerformedSet, 0, 30)
 		for _, we := range weItems {
@@ -388,10 +664,208 @@ func LogWorkout(weItems []WeItem, datum time.Time) func() tea.Msg {
 			}
 		}
 
-		err := wodb.Instance().LogSetsTransaction(sets)
+		history, err := exerciseHistory(store, sets)
+		if err != nil {
+			return StatusMsg{Status: "", Err: fmt.Errorf("Error loading your history: %v", err.Error())}
+		}
+
+		// sets and new targets are saved together, so a failure leaves
+		// nothing half logged to log a second time
+		session = finishSession(session, datum)
+		err = store.Transaction(func(tx wodb.Store) error {
+			if err := tx.LogSession(&session, sets); err != nil {
+				return fmt.Errorf("Error logging your sets: %v", err)
+			}
+			for _, we := range weItems {
+				if we.Proposal == nil {
+					continue
+				}
+				p := we.Proposal
+				err := tx.ApplyProgression(we.WorkoutExercise.ID, p.Sets, p.TrainingMax, p.Based)
+				if err != nil {
+					return fmt.Errorf("Error updating the targets of %v: %v", we.Exercise.GetName(), err)
+				}
+			}
+			return nil
+		})
 		if err != nil {
-			return StatusMsg{Status: "", Err: fmt.Errorf("Error logging your sets: %v", err.Error())}
+			return StatusMsg{Status: "", Err: err}
+		}
+
+		if prs := prStatus(store, settings, session, history, sets); prs != "" {
+			return StatusMsg{Status: "Good job, logged workout 💪 " + prs}
 		}
 		return StatusMsg{Status: "Good job, logged workout 💪"}
 	}
 }
+
+// Export writes the history in format to path. Dates are YYYY-MM-DD and may
+// be empty, exercises is a comma separated list of IDs or names. Without a
+// path the export goes to ~/Documents.
+func Export(store wodb.Store, format, path, from, to, exercises string) func() tea.Msg {
+	return func() tea.Msg {
+		filter := wodb.SetFilter{}
+		var err error
+
+		if from != "" {
+			filter.From, err = time.ParseInLocation("2006-01-02", from, time.Local)
+			if err != nil {
+				return StatusMsg{Err: fmt.Errorf("Invalid from date: %v", from)}
+			}
+		}
+		if to != "" {
+			filter.To, err = time.ParseInLocation("2006-01-02", to, time.Local)
+			if err != nil {
+				return StatusMsg{Err: fmt.Errorf("Invalid to date: %v", to)}
+			}
+		}
+
+		if exercises != "" {
+			all, err := store.GetAllExercises()
+			if err != nil {
+				return StatusMsg{Err: fmt.Errorf("Error loading exercises: %v", err)}
+			}
+			for _, q := range strings.Split(exercises, ",") {
+				e, ok := wodb.FindExercise(all, strings.TrimSpace(q))
+				if !ok {
+					return StatusMsg{Err: fmt.Errorf("Unknown exercise: %v", q)}
+				}
+				filter.ExerciseIDs = append(filter.ExerciseIDs, e.ID)
+			}
+		}
+
+		if path == "" {
+			path = DefaultExportPath(format)
+		}
+		path, err = config.ExpandHome(path)
+		if err != nil {
+			return StatusMsg{Err: fmt.Errorf("Error resolving export path: %v", err)}
+		}
+
+		doc, err := export.Build(store, filter)
+		if err != nil {
+			return StatusMsg{Err: fmt.Errorf("Error collecting export: %v", err)}
+		}
+		if err := export.Save(doc, format, path); err != nil {
+			return StatusMsg{Err: fmt.Errorf("Error writing export: %v", err)}
+		}
+
+		return StatusMsg{Status: fmt.Sprintf("Exported %v sets to %v", len(doc.PerformedSets), path)}
+	}
+}
+
+func DefaultExportPath(format string) string {
+	name := "clift-export-" + time.Now().Format("20060102")
+	if format == export.JSON {
+		name = name + ".json"
+	}
+	return filepath.Join("~", "Documents", name)
+}
+
+func LoadPrograms(store wodb.Store) func() tea.Msg {
+	return func() tea.Msg {
+		programs, err := store.GetPrograms()
+		return MsgPrograms{Programs: programs, Err: err}
+	}
+}
+
+func LoadProgram(store wodb.Store, id uint) func() tea.Msg {
+	return func() tea.Msg {
+		program, err := store.GetProgram(id)
+		return MsgProgram{Program: program, Err: err}
+	}
+}
+
+// LoadToday loads the active program to find the workout scheduled next.
+func LoadToday(store wodb.Store) func() tea.Msg {
+	return func() tea.Msg {
+		program, err := store.GetActiveProgram()
+		return MsgToday{Program: program, Err: err}
+	}
+}
+
+func NewProgram(store wodb.Store, name string) func() tea.Msg {
+	return func() tea.Msg {
+		_, err := store.CreateProgram(name)
+		return programChanged("Created program "+name, "Error creating program", err)
+	}
+}
+
+func RemoveProgram(store wodb.Store, id uint) func() tea.Msg {
+	return func() tea.Msg {
+		return programChanged("Removed program", "Error removing program", store.RemoveProgram(id))
+	}
+}
+
+func ActivateProgram(store wodb.Store, program wodb.Program) func() tea.Msg {
+	return func() tea.Msg {
+		return programChanged(program.Name+" is what you train now", "Error activating program", store.ActivateProgram(program.ID))
+	}
+}
+
+func SetProgramPosition(store wodb.Store, id uint, week, day int) func() tea.Msg {
+	return func() tea.Msg {
+		status := fmt.Sprintf("Next up: week %v day %v", week+1, day+1)
+		return programChanged(status, "Error moving program", store.SetProgramPosition(id, week, day))
+	}
+}
+
+// AddProgramWeek appends a week to the program doing the same workouts as its
+// last week.
+func AddProgramWeek(store wodb.Store, program wodb.Program) func() tea.Msg {
+	return func() tea.Msg {
+		var workoutIDs []uint
+		if len(program.Weeks) > 0 {
+			for _, d := range program.Weeks[len(program.Weeks)-1].Days {
+				workoutIDs = append(workoutIDs, d.WorkoutID)
+			}
+		}
+		_, err := store.AddProgramWeek(program.ID, "", "", workoutIDs)
+		return programChanged("Added week", "Error adding week", err)
+	}
+}
+
+func UpdateProgramWeek(store wodb.Store, week wodb.ProgramWeek) func() tea.Msg {
+	return func() tea.Msg {
+		return programChanged("Saved week", "Error saving week", store.UpdateProgramWeek(week))
+	}
+}
+
+func RemoveProgramWeek(store wodb.Store, weekID uint) func() tea.Msg {
+	return func() tea.Msg {
+		return programChanged("Removed week", "Error removing week", store.RemoveProgramWeek(weekID))
+	}
+}
+
+func AddProgramDay(store wodb.Store, weekID uint, workoutID uint) func() tea.Msg {
+	return func() tea.Msg {
+		_, err := store.AddProgramDay(weekID, workoutID)
+		return programChanged("Added day", "Error adding day", err)
+	}
+}
+
+func RemoveProgramDay(store wodb.Store, dayID uint) func() tea.Msg {
+	return func() tea.Msg {
+		return programChanged("Removed day", "Error removing day", store.RemoveProgramDay(dayID))
+	}
+}
+
+func LoadLibrary() tea.Msg {
+	programs, err := library.Programs()
+	return MsgLibrary{Programs: programs, Err: err}
+}
+
+// InstallProgram creates a program of the library with its workouts.
+func InstallProgram(store wodb.Store, settings Settings, program library.Program, maxes map[string]float64) func() tea.Msg {
+	return func() tea.Msg {
+		_, err := library.Install(store, program, maxes, settings.Units)
+		return programChanged("Installed "+program.Name, "Error installing "+program.Name, err)
+	}
+}
+
+func programChanged(status, failure string, err error) MsgProgramChanged {
+	if err != nil {
+		return MsgProgramChanged{Err: fmt.Errorf("%v: %v", failure, err)}
+	}
+	return MsgProgramChanged{Status: status}
+}
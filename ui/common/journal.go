@@ -0,0 +1,263 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/notation"
+)
+
+const JOURNAL_PAGE_SIZE = 200
+
+const (
+	JOURNAL_ROW_DAY = iota
+	JOURNAL_ROW_EXERCISE
+	JOURNAL_ROW_SET
+)
+
+// JournalRow is what a row of the journal table stands for.
+type JournalRow struct {
+	Kind  int
+	Group string              // session or day the row belongs to
+	Block string              // exercise block within the group, empty for day rows
+	Day   time.Time           // day of the group
+	Sets  []wodb.PerformedSet // the whole group, the block or the single set
+}
+
+// JournalNames resolves IDs to display names.
+type JournalNames struct {
+	Exercises map[string]string
+	Workouts  map[uint]string
+}
+
+type journalGroup struct {
+	key     string
+	day     time.Time
+	workout uint
+	session wodb.Session
+	sets    []wodb.PerformedSet
+	blocks  []*journalBlock
+}
+
+type journalBlock struct {
+	key        string
+	exerciseID string
+	sets       []wodb.PerformedSet
+}
+
+// groupKey puts sets of the same session together. Sets logged without one
+// are grouped by day and workout.
+func groupKey(s wodb.PerformedSet) string {
+	if s.SessionID != 0 {
+		return fmt.Sprintf("s%v", s.SessionID)
+	}
+	return fmt.Sprintf("d%v|w%v", s.PerformedDate.Local().Format("2006-01-02"), s.WorkoutID)
+}
+
+func groupJournal(sets []wodb.PerformedSet, sessions map[uint]wodb.Session) []*journalGroup {
+	groups := make([]*journalGroup, 0, 50)
+	byKey := make(map[string]*journalGroup)
+	blocks := make(map[string]*journalBlock)
+
+	for _, s := range sets {
+		gk := groupKey(s)
+		g, ok := byKey[gk]
+		if !ok {
+			g = &journalGroup{
+				key:     gk,
+				day:     s.PerformedDate.Local(),
+				workout: s.WorkoutID,
+				session: sessions[s.SessionID],
+			}
+			byKey[gk] = g
+			groups = append(groups, g)
+		}
+		g.sets = append(g.sets, s)
+
+		bk := gk + "|" + s.ExerciseID
+		b, ok := blocks[bk]
+		if !ok {
+			b = &journalBlock{key: bk, exerciseID: s.ExerciseID}
+			blocks[bk] = b
+			g.blocks = append(g.blocks, b)
+		}
+		b.sets = append(b.sets, s)
+	}
+
+	for _, b := range blocks {
+		sort.SliceStable(b.sets, func(i, j int) bool { return b.sets[i].SetNo < b.sets[j].SetNo })
+	}
+	return groups
+}
+
+// BuildJournal groups sets by session, or by day where there is none, and
+// within those by exercise. Blocks whose key is in collapsed only show their
+// exercise row.
+func (st Settings) BuildJournal(sets []wodb.PerformedSet, sessions map[uint]wodb.Session, names JournalNames, collapsed map[string]bool) ([]JournalRow, []table.Row) {
+	rows := make([]JournalRow, 0, len(sets)+50)
+	tableRows := make([]table.Row, 0, len(sets)+50)
+
+	for _, g := range groupJournal(sets, sessions) {
+		rows = append(rows, JournalRow{Kind: JOURNAL_ROW_DAY, Group: g.key, Day: g.day, Sets: g.sets})
+		tableRows = append(tableRows, table.Row{g.day.Format("Mon 2006-01-02"), st.groupTitle(g, names), "", "", "", ""})
+
+		for _, b := range g.blocks {
+			marker := "▾"
+			if collapsed[b.key] {
+				marker = "▸"
+			}
+
+			name, ok := names.Exercises[b.exerciseID]
+			if !ok {
+				name = b.exerciseID
+			}
+
+			rows = append(rows, JournalRow{Kind: JOURNAL_ROW_EXERCISE, Group: g.key, Block: b.key, Day: g.day, Sets: b.sets})
+			tableRows = append(tableRows, table.Row{"", fmt.Sprintf("%v %v (%v)", marker, name, len(b.sets)), "", "", "", ""})
+
+			if collapsed[b.key] {
+				continue
+			}
+			for _, s := range b.sets {
+				rows = append(rows, JournalRow{Kind: JOURNAL_ROW_SET, Group: g.key, Block: b.key, Day: g.day, Sets: []wodb.PerformedSet{s}})
+				// cardio and timed sets show time and distance instead
+				reps, weight := strconv.Itoa(s.Reps), st.FormatWeight(s.Weight)
+				if s.Reps <= 0 && s.Duration > 0 {
+					reps = notation.FormatDuration(s.Duration)
+				}
+				if effort := notation.FormatEffort(s.RPE, s.RIR); effort != "" {
+					reps += " " + effort
+				}
+				if s.Weight == 0 && s.Distance > 0 {
+					weight = notation.FormatDistance(s.Distance)
+				}
+				tableRows = append(tableRows, table.Row{
+					"",
+					"",
+					strconv.Itoa(s.SetNo+1) + SetTypeTag(s.Type),
+					reps,
+					weight,
+					FormatCardio(s),
+				})
+			}
+		}
+	}
+
+	return rows, tableRows
+}
+
+// groupTitle is the workout name plus whatever the session knows.
+func (s Settings) groupTitle(g *journalGroup, names JournalNames) string {
+	parts := make([]string, 0, 4)
+	if name, ok := names.Workouts[g.workout]; ok {
+		parts = append(parts, name)
+	} else {
+		parts = append(parts, "no workout")
+	}
+	if d := g.session.Duration(); d >= time.Minute {
+		parts = append(parts, fmt.Sprintf("%v min", int(d.Minutes())))
+	}
+	if g.session.Bodyweight > 0 {
+		parts = append(parts, "bw "+s.FormatWeight(g.session.Bodyweight))
+	}
+	if g.session.Notes != "" {
+		parts = append(parts, g.session.Notes)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// FormatCardio shows pace, speed, heart rate and calories of a set, as far as
+// they are known.
+func FormatCardio(s wodb.PerformedSet) string {
+	parts := make([]string, 0, 4)
+	if pace := s.Pace(); pace > 0 {
+		parts = append(parts, notation.FormatPace(pace), notation.FormatSpeed(s.Speed()))
+	}
+	if s.HeartRate > 0 {
+		parts = append(parts, fmt.Sprintf("%v bpm", s.HeartRate))
+	}
+	if s.Calories > 0 {
+		parts = append(parts, fmt.Sprintf("%v kcal", s.Calories))
+	}
+	return strings.Join(parts, "  ")
+}
+
+// FormatPerformed shows a set in a few characters, "5 × 100 kg @8" or
+// "30:00 · 5 km".
+func (st Settings) FormatPerformed(s wodb.PerformedSet) string {
+	if s.Duration > 0 || s.Distance > 0 {
+		return formatTimed(s.Duration, s.Distance)
+	}
+	return strings.TrimSpace(fmt.Sprintf("%v × %v %v", s.Reps, st.FormatWeight(s.Weight), notation.FormatEffort(s.RPE, s.RIR)))
+}
+
+// FormatTarget shows a set target, "5 @ 100" or "30:00 · 5 km", followed
+// by the type of other than working sets.
+func (st Settings) FormatTarget(s wodb.Set) string {
+	target := fmt.Sprintf("%v @ %v", s.Reps, st.DisplayWeight(s.Weight))
+	if s.TargetRPE > 0 {
+		target += fmt.Sprintf(" RPE %v", s.TargetRPE)
+	}
+	if s.Duration > 0 || s.Distance > 0 {
+		target = formatTimed(s.Duration, s.Distance)
+	}
+	if s.Type != wodb.SET_WORKING {
+		target += " (" + SetTypeName(s.Type) + ")"
+	}
+	return target
+}
+
+func formatTimed(duration int, distance float64) string {
+	parts := make([]string, 0, 2)
+	if duration > 0 {
+		parts = append(parts, notation.FormatDuration(duration))
+	}
+	if distance > 0 {
+		parts = append(parts, notation.FormatDistance(distance))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func MakeJournal(rows []table.Row) table.Model {
+	columns := []table.Column{
+		{Title: "Date", Width: 15},
+		{Title: "Exercise", Width: 33},
+		{Title: "Set", Width: 5},
+		{Title: "Reps/Time", Width: 10},
+		{Title: "Weight/Km", Width: 10},
+		{Title: "Pace", Width: 37},
+	}
+
+	t := MakeTable(columns)
+	t.SetRows(rows)
+	return t
+}
+
+// MakeTable returns an empty, focused table in the colors of the theme.
+func MakeTable(columns []table.Column) table.Model {
+	t := table.New(
+		table.WithColumns(columns),
+		table.WithFocused(true),
+		table.WithHeight(20),
+	)
+
+	s := table.DefaultStyles()
+	s.Header = s.Header.
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(Theme.Foreground).
+		BorderBottom(true).
+		Bold(false)
+	s.Selected = s.Selected.
+		Foreground(lipgloss.Color(Theme.Red)).
+		//Background(lipgloss.Color("57")).
+		Bold(false)
+	t.SetStyles(s)
+
+	return t
+}
@@ -2,10 +2,11 @@ package common
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 
 	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/library"
+	"github.com/zmnpl/clift/progression"
 )
 
 // ------------------------------------------
@@ -25,20 +26,19 @@ func (wi WorkoutItem) FilterValue() string { return wi.Name }
 type WeItem struct {
 	*wodb.WorkoutExercise
 	SetInputs []SetInput
+	Proposal  *progression.Proposal // targets proposed by the progression rule
+	Note      string                // shown above the sets
 }
 
 func (we WeItem) Title() string { return we.Exercise.ID }
 func (we WeItem) Description() string {
 	sb := &strings.Builder{}
+	if we.Note != "" {
+		sb.WriteString(we.Note + "\n")
+	}
 
 	for _, setInput := range we.SetInputs {
-		reps := setInput.Reps.Placeholder
-		weight := setInput.Weight.Placeholder
-
-		doneReps, _ := strconv.Atoi(setInput.Reps.Value())
-		doneWeight, _ := strconv.Atoi(setInput.Weight.Value())
-
-		sb.WriteString(fmt.Sprintf("%v (%v) reps @ %v (%v) kg\n", doneReps, reps, doneWeight, weight))
+		sb.WriteString(setInput.Describe() + "\n")
 	}
 
 	return sb.String()
@@ -47,6 +47,38 @@ func (we WeItem) FilterValue() string { return we.Exercise.ID }
 
 // ------------------------------------------
 
+type ProgramItem struct {
+	*wodb.Program
+}
+
+func (pi ProgramItem) Title() string {
+	if pi.Active {
+		return pi.Name + " ★"
+	}
+	return pi.Name
+}
+func (pi ProgramItem) Description() string {
+	week, day := pi.Position()
+	if week < 0 {
+		return fmt.Sprintf("%v weeks", len(pi.Weeks))
+	}
+	workout := pi.Weeks[week].Days[day].Workout.Name
+	return fmt.Sprintf("%v weeks · next week %v day %v: %v", len(pi.Weeks), week+1, day+1, workout)
+}
+func (pi ProgramItem) FilterValue() string { return pi.Name }
+
+// ------------------------------------------
+
+type LibraryItem struct {
+	library.Program
+}
+
+func (li LibraryItem) Title() string       { return li.Name }
+func (li LibraryItem) Description() string { return li.Program.Description }
+func (li LibraryItem) FilterValue() string { return li.Name }
+
+// ------------------------------------------
+
 type ExerciseItem struct {
 	*wodb.Exercise
 }
@@ -0,0 +1,63 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/notation"
+	"github.com/zmnpl/clift/plates"
+)
+
+// IsBarbell reports whether e is loaded with plates on a bar.
+func IsBarbell(e wodb.Exercise) bool {
+	return e.GetEquipment() == "barbell"
+}
+
+// RoundToPlates rounds kilograms to the nearest weight the bar can be loaded
+// to with the plates of the user. Bodyweight loads (0 and below) stay as they
+// are.
+func (s Settings) RoundToPlates(kg float64) float64 {
+	if kg <= 0 {
+		return kg
+	}
+	return notation.ToKg(s.Plates.Round(s.DisplayWeight(kg)), s.Units)
+}
+
+// RoundTargetsToPlates rounds the weights of the targets of a barbell
+// exercise to loadable ones. A change against before, the targets up to now,
+// isn't undone by rounding though: an increment below the smallest plates
+// stays as it is.
+func (s Settings) RoundTargetsToPlates(e wodb.Exercise, targets, before []wodb.Set) {
+	if !IsBarbell(e) {
+		return
+	}
+	for i := range targets {
+		rounded := s.RoundToPlates(targets[i].Weight)
+		if i < len(before) && targets[i].Weight != before[i].Weight && rounded == before[i].Weight {
+			continue
+		}
+		targets[i].Weight = rounded
+	}
+}
+
+// DescribePlates tells what goes on each side of the bar for kilograms, e.g.
+// "100 kg: 25 + 15 each side".
+func (s Settings) DescribePlates(kg float64) string {
+	weight := s.DisplayWeight(kg)
+	perSide, short, ok := s.Plates.Load(weight)
+
+	d := s.FormatWeight(kg) + ": "
+	switch {
+	case !ok:
+		return d + "lighter than the bar"
+	case len(perSide) == 0:
+		d += "just the bar"
+	default:
+		d += plates.Format(perSide) + " each side"
+	}
+	if short > 0 {
+		d += fmt.Sprintf(", %v %v short", strconv.FormatFloat(short, 'f', -1, 64), s.Units)
+	}
+	return d
+}
@@ -0,0 +1,86 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	wodb "github.com/zmnpl/clift/db"
+)
+
+// REST_STEP is what the rest timer keys add or take.
+const REST_STEP = 15 * time.Second
+
+// MsgRestStart starts the rest timer, replacing a running one.
+type MsgRestStart struct {
+	Rest     time.Duration
+	Exercise string
+}
+
+// MsgRestTick is the second beat of the rest timer with the given ID. Ticks of
+// an earlier timer are ignored.
+type MsgRestTick int
+
+func StartRest(rest time.Duration, exercise string) func() tea.Msg {
+	return func() tea.Msg {
+		return MsgRestStart{Rest: rest, Exercise: exercise}
+	}
+}
+
+func RestTick(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return MsgRestTick(id)
+	})
+}
+
+// Bell rings the terminal bell.
+func Bell() tea.Msg {
+	os.Stdout.WriteString("\a")
+	return nil
+}
+
+// RestFor is the rest between the sets of we, the default rest of the user
+// if it has none of its own.
+func (s Settings) RestFor(we wodb.WorkoutExercise) time.Duration {
+	if we.Rest > 0 {
+		return time.Duration(we.Rest) * time.Second
+	}
+	return time.Duration(s.DefaultRest) * time.Second
+}
+
+// FormatRest shows a duration as m:ss.
+func FormatRest(d time.Duration) string {
+	s := int(d.Round(time.Second).Seconds())
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// ParseRest reads a rest as seconds ("90") or minutes and seconds ("1:30").
+func ParseRest(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+
+	minutes, seconds, ok := strings.Cut(s, ":")
+	if !ok {
+		minutes, seconds = "0", s
+	}
+	m, err1 := strconv.Atoi(minutes)
+	n, err2 := strconv.Atoi(seconds)
+	if err1 != nil || err2 != nil || m < 0 || n < 0 || (ok && n >= 60) {
+		return 0, fmt.Errorf("rest must be seconds or m:ss, got %q", s)
+	}
+	return m*60 + n, nil
+}
+
+// SaveRest stores the rest of a workout exercise in seconds.
+func SaveRest(store wodb.Store, weID uint, seconds int) func() tea.Msg {
+	return func() tea.Msg {
+		return MsgUpdatedWorkoutExercise{
+			Err: store.UpdateRest(weID, seconds),
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+func TestParseRest(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"", 0, true},
+		{"90", 90, true},
+		{" 1:30 ", 90, true},
+		{"3:00", 180, true},
+		{"0:45", 45, true},
+		{"150", 150, true},
+		{"1:60", 0, false},
+		{"-30", 0, false},
+		{"1:-5", 0, false},
+		{"1m30", 0, false},
+		{"abc", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseRest(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseRest(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestFormatRest(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0:00"},
+		{90 * time.Second, "1:30"},
+		{3*time.Minute + 5*time.Second, "3:05"},
+		{1500 * time.Millisecond, "0:02"},
+	}
+	for _, tt := range tests {
+		if got := FormatRest(tt.in); got != tt.want {
+			t.Errorf("FormatRest(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRestFor(t *testing.T) {
+	settings := DefaultSettings()
+	settings.DefaultRest = 120
+
+	if got := settings.RestFor(wodb.WorkoutExercise{}); got != 2*time.Minute {
+		t.Errorf("RestFor without a rest of its own = %v, want 2m", got)
+	}
+	if got := settings.RestFor(wodb.WorkoutExercise{Rest: 180}); got != 3*time.Minute {
+		t.Errorf("RestFor = %v, want 3m", got)
+	}
+}
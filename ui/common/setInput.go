@@ -2,105 +2,394 @@ package common
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/notation"
+	"github.com/zmnpl/clift/progression"
 )
 
+// measures, what is entered for a set
+const (
+	MEASURE_REPS   = iota // reps and weight
+	MEASURE_CARDIO        // duration, distance, heart rate and calories
+	MEASURE_TIME          // duration only, e.g. holding a stretch
+)
+
+// MeasureOf picks what is entered for the sets of e by its category.
+func MeasureOf(e wodb.Exercise) int {
+	switch e.GetCategory() {
+	case "cardio":
+		return MEASURE_CARDIO
+	case "stretching":
+		return MEASURE_TIME
+	}
+	return MEASURE_REPS
+}
+
 type SetInput struct {
 	SetNo      int
+	Type       string // see wodb.SetTypes
+	Measure    int
 	Reps       textinput.Model
 	Weight     textinput.Model
+	RPE        textinput.Model // or reps in reserve, see notation.ParseEffort
+	Duration   textinput.Model
+	Distance   textinput.Model
+	HeartRate  textinput.Model
+	Calories   textinput.Model
 	WorkoutId  uint
 	ExerciseId string
 	Datum      time.Time
+	Rested     bool   // the rest timer was started after the set
+	units      string // of weights entered without one
 }
 
-func (i *SetInput) PlaceholderToValue() {
-	if i.Reps.Value() == "" {
-		i.Reps.SetValue(i.Reps.Placeholder)
+// Fields are the inputs of the measure of the set, in order.
+func (i *SetInput) Fields() []*textinput.Model {
+	switch i.Measure {
+	case MEASURE_CARDIO:
+		return []*textinput.Model{&i.Duration, &i.Distance, &i.HeartRate, &i.Calories}
+	case MEASURE_TIME:
+		return []*textinput.Model{&i.Duration}
 	}
-	if i.Weight.Value() == "" {
-		i.Weight.SetValue(i.Weight.Placeholder)
+	return []*textinput.Model{&i.Reps, &i.Weight, &i.RPE}
+}
+
+// fieldLabels name the Fields of each measure.
+var fieldLabels = map[int][]string{
+	MEASURE_REPS:   {"Reps", "Weight", "RPE"},
+	MEASURE_CARDIO: {"Time", "Km", "HR", "kcal"},
+	MEASURE_TIME:   {"Time"},
+}
+
+// ToggleType moves on to the next set type.
+func (i *SetInput) ToggleType() {
+	n := slices.Index(wodb.SetTypes, i.Type)
+	i.Type = wodb.SetTypes[(n+1)%len(wodb.SetTypes)]
+}
+
+// SetTypeTag marks a set type with a letter, working sets with none.
+func SetTypeTag(t string) string {
+	switch t {
+	case wodb.SET_WARMUP:
+		return "W"
+	case wodb.SET_DROP:
+		return "D"
+	case wodb.SET_AMRAP:
+		return "A"
+	case wodb.SET_FAILURE:
+		return "F"
 	}
+	return ""
 }
 
-func (i *SetInput) FocusReps() tea.Cmd {
-	cmd := i.Reps.Focus()
-	i.Reps.PromptStyle = FocusedStyle
-	i.Reps.TextStyle = FocusedStyle
+// SetTypeName names a set type for humans.
+func SetTypeName(t string) string {
+	switch t {
+	case wodb.SET_WARMUP:
+		return "warm-up"
+	case wodb.SET_DROP:
+		return "drop set"
+	case wodb.SET_AMRAP:
+		return "AMRAP"
+	case wodb.SET_FAILURE:
+		return "to failure"
+	}
+	return "working"
+}
 
-	i.Weight.Blur()
-	i.Weight.PromptStyle = NoStyle
-	i.Weight.TextStyle = NoStyle
+// View shows the labelled fields in a row.
+func (i SetInput) View() string {
+	labels := fieldLabels[i.Measure]
+	parts := make([]string, 0, len(labels))
+	for n, f := range i.Fields() {
+		parts = append(parts, labels[n]+" "+f.View())
+	}
+	return strings.Join(parts, " ")
+}
 
-	return cmd
+// Entered reports whether anything was typed into the set.
+func (i SetInput) Entered() bool {
+	for _, f := range i.Fields() {
+		if f.Value() != "" {
+			return true
+		}
+	}
+	return false
 }
 
-func (i *SetInput) FocusWeight() tea.Cmd {
-	cmd := i.Weight.Focus()
-	i.Weight.PromptStyle = FocusedStyle
-	i.Weight.TextStyle = FocusedStyle
+func (i *SetInput) PlaceholderToValue() {
+	for _, f := range i.Fields() {
+		if f.Value() == "" {
+			f.SetValue(f.Placeholder)
+		}
+	}
+}
 
-	i.Reps.Blur()
-	i.Reps.PromptStyle = NoStyle
-	i.Reps.TextStyle = NoStyle
+// Focused is the index of the focused field, -1 if none is.
+func (i *SetInput) Focused() int {
+	for n, f := range i.Fields() {
+		if f.Focused() {
+			return n
+		}
+	}
+	return -1
+}
 
+// FocusField focuses field n of Fields and blurs the others.
+func (i *SetInput) FocusField(n int) tea.Cmd {
+	var cmd tea.Cmd
+	for j, f := range i.Fields() {
+		if j == n {
+			cmd = f.Focus()
+			f.PromptStyle = FocusedStyle
+			f.TextStyle = FocusedStyle
+			continue
+		}
+		f.Blur()
+		f.PromptStyle = NoStyle
+		f.TextStyle = NoStyle
+	}
 	return cmd
 }
 
+func (i *SetInput) FocusFirst() tea.Cmd {
+	return i.FocusField(0)
+}
+
+func (i *SetInput) FocusLast() tea.Cmd {
+	return i.FocusField(len(i.Fields()) - 1)
+}
+
 func (i *SetInput) Unfocus() {
-	i.Reps.Blur()
-	i.Reps.PromptStyle = NoStyle
-	i.Reps.TextStyle = NoStyle
+	i.FocusField(-1)
+}
+
+// Target is the set as planned: what was entered, or the placeholder where
+// nothing or nothing valid was.
+func (i SetInput) Target() wodb.Set {
+	text := func(in textinput.Model) string {
+		if v := strings.TrimSpace(in.Value()); v != "" {
+			return v
+		}
+		return in.Placeholder
+	}
+
+	s := wodb.Set{Type: i.Type}
+	switch i.Measure {
+	case MEASURE_REPS:
+		reps, err := strconv.Atoi(text(i.Reps))
+		if err != nil {
+			reps, _ = strconv.Atoi(i.Reps.Placeholder)
+		}
+		weight, _, err := notation.ParseWeight(text(i.Weight), i.units)
+		if err != nil {
+			weight, _, _ = notation.ParseWeight(i.Weight.Placeholder, i.units)
+		}
+		s.Reps, s.Weight = reps, weight
 
-	i.Weight.Blur()
-	i.Weight.PromptStyle = NoStyle
-	i.Weight.TextStyle = NoStyle
+		rpe, rir, err := notation.ParseEffort(text(i.RPE))
+		if err != nil {
+			rpe, rir, _ = notation.ParseEffort(i.RPE.Placeholder)
+		}
+		if rpe == 0 && rir != nil {
+			rpe = max(0, 10-float64(*rir))
+		}
+		s.TargetRPE = rpe
+	default:
+		duration, err := notation.ParseDuration(text(i.Duration), i.durationUnit())
+		if err != nil {
+			duration, _ = notation.ParseDuration(i.Duration.Placeholder, i.durationUnit())
+		}
+		s.Duration = duration
+		if i.Measure == MEASURE_CARDIO {
+			distance, err := notation.ParseDistance(text(i.Distance))
+			if err != nil {
+				distance, _ = notation.ParseDistance(i.Distance.Placeholder)
+			}
+			s.Distance = distance
+		}
+	}
+	return s
+}
+
+// durationUnit is what a bare number in the duration counts: minutes of
+// cardio, seconds of a hold.
+func (i SetInput) durationUnit() time.Duration {
+	if i.Measure == MEASURE_CARDIO {
+		return time.Minute
+	}
+	return time.Second
+}
+
+// Describe shows what was done against what was planned, e.g.
+// "5 (5) reps @ 100 (100) kg", led by the type of other than working sets.
+func (i SetInput) Describe() string {
+	prefix := ""
+	if i.Type != wodb.SET_WORKING {
+		prefix = SetTypeName(i.Type) + ": "
+	}
+
+	switch i.Measure {
+	case MEASURE_CARDIO:
+		return prefix + fmt.Sprintf("%v (%v) · %v (%v) km", i.Duration.Value(), i.Duration.Placeholder, i.Distance.Value(), i.Distance.Placeholder)
+	case MEASURE_TIME:
+		return prefix + fmt.Sprintf("%v (%v)", i.Duration.Value(), i.Duration.Placeholder)
+	}
+	doneReps, _ := strconv.Atoi(i.Reps.Value())
+	doneWeight, _, _ := notation.ParseWeight(i.Weight.Value(), i.units)
+	s := prefix + fmt.Sprintf("%v (%v) reps @ %v (%v) %v", doneReps, i.Reps.Placeholder, notation.FromKg(doneWeight, i.units), i.Weight.Placeholder, i.units)
+	if i.RPE.Value() != "" || i.RPE.Placeholder != "" {
+		s += fmt.Sprintf(" RPE %v (%v)", i.RPE.Value(), i.RPE.Placeholder)
+	}
+	return s
 }
 
-func CreateSetTemplatesForWE(we wodb.WorkoutExercise) []SetInput {
+func (st Settings) CreateSetTemplatesForWE(we wodb.WorkoutExercise) []SetInput {
+	measure := MeasureOf(we.Exercise)
 	inputs := make([]SetInput, 0, 999)
 	// sets of workout exercise
 	for i, s := range we.Sets {
-		inputs = append(inputs, CreateSetTemplate(i+1, s.Reps, int(s.Weight), we.WorkoutID, we.ExerciseID))
+		input := st.CreateSetTemplate(i+1, s.Reps, s.Weight, we.WorkoutID, we.ExerciseID)
+		if measure != MEASURE_REPS {
+			input = st.CreateTimedSetTemplate(i+1, measure, s.Duration, s.Distance, we.WorkoutID, we.ExerciseID)
+		}
+		input.Type = s.Type
+		if s.TargetRPE > 0 {
+			input.RPE.Placeholder = strconv.FormatFloat(s.TargetRPE, 'f', -1, 64)
+		}
+		inputs = append(inputs, input)
+	}
+
+	return inputs
+}
+
+// CreateWarmUpTemplates makes the warm-up sets before working following the
+// warm-up ramp of the user, rounded to the plates for barbell exercises.
+func (st Settings) CreateWarmUpTemplates(exercise wodb.Exercise, working wodb.Set, workoutId uint) []SetInput {
+	bar, loadable := 0.0, func(kg float64) float64 { return progression.Round(kg, st.Units) }
+	if IsBarbell(exercise) {
+		bar, loadable = notation.ToKg(st.Plates.Bar, st.Units), st.RoundToPlates
 	}
 
+	sets := progression.WarmUps(st.WarmupRamp, working, bar, loadable)
+	inputs := make([]SetInput, len(sets))
+	for i, s := range sets {
+		inputs[i] = st.CreateSetTemplate(i+1, s.Reps, s.Weight, workoutId, exercise.ID)
+		inputs[i].Type = s.Type
+	}
 	return inputs
 }
 
-func CreateEmptySetTemplate(eid string, set_cnt int) []SetInput {
+// CreateEmptySetTemplate makes set_cnt sets of exercise with the default
+// reps, or without targets for cardio and timed exercises.
+func (st Settings) CreateEmptySetTemplate(exercise wodb.Exercise, set_cnt int) []SetInput {
+	measure := MeasureOf(exercise)
 	inputs := make([]SetInput, 0, set_cnt)
 	// sets of workout exercise
 	for i := 0; i < set_cnt; i++ {
-		inputs = append(inputs, CreateSetTemplate(i+1, 10, 0, 0, eid))
+		if measure != MEASURE_REPS {
+			inputs = append(inputs, st.CreateTimedSetTemplate(i+1, measure, 0, 0, 0, exercise.ID))
+			continue
+		}
+		inputs = append(inputs, st.CreateSetTemplate(i+1, st.DefaultReps, 0, 0, exercise.ID))
 	}
 
 	return inputs
 }
 
-func CreateSetTemplate(setno int, reps int, weight int, wrokoutId uint, exerciseId string) SetInput {
+// CreateSetInputsFromNotation turns a line of set notation (see package
+// notation) into filled in set inputs. Weights given in another unit than the
+// user's are kept as written. The notation only knows reps and weights, cardio
+// and timed exercises (see MeasureOf) are refused.
+func (st Settings) CreateSetInputsFromNotation(line string, workoutId uint, exercise wodb.Exercise) ([]SetInput, error) {
+	if MeasureOf(exercise) != MEASURE_REPS {
+		return nil, fmt.Errorf("%v is entered by time, not in set notation", exercise.GetName())
+	}
+	sets, err := notation.Parse(line)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := make([]SetInput, len(sets))
+	for i, s := range sets {
+		inputs[i] = st.CreateSetTemplate(i+1, s.Reps, s.Kg(st.Units), workoutId, exercise.ID)
+		inputs[i].PlaceholderToValue()
+		if s.UnitOr(st.Units) != st.Units {
+			inputs[i].Weight.SetValue(notation.WeightValue(s.Weight) + s.Unit)
+		}
+	}
+
+	return inputs, nil
+}
+
+// CreateSetTemplate makes the inputs of a set planned at reps and weight, in
+// kilograms.
+func (st Settings) CreateSetTemplate(setno int, reps int, weight float64, wrokoutId uint, exerciseId string) SetInput {
 	repTextIn := textinput.New()
 	repTextIn.Placeholder = fmt.Sprintf("%v", reps)
 	repTextIn.CharLimit = 4
-	repTextIn.Width = 20
+	repTextIn.Width = 5
 	//repTextIn.Cursor.SetMode(cursor.CursorBlink)
 
 	weightTextIn := textinput.New()
-	weightTextIn.Placeholder = fmt.Sprintf("%v", weight)
+	weightTextIn.Placeholder = st.WeightValue(weight)
 	weightTextIn.CharLimit = 50
-	weightTextIn.Width = 100
+	weightTextIn.Width = 10
+
+	rpeTextIn := textinput.New()
+	rpeTextIn.CharLimit = 6
+	rpeTextIn.Width = 5
+
+	durationTextIn := textinput.New()
+	durationTextIn.Placeholder = "0:00"
+	durationTextIn.CharLimit = 10
+	durationTextIn.Width = 8
+
+	distanceTextIn := textinput.New()
+	distanceTextIn.Placeholder = "0"
+	distanceTextIn.CharLimit = 10
+	distanceTextIn.Width = 7
+
+	heartRateTextIn := textinput.New()
+	heartRateTextIn.CharLimit = 3
+	heartRateTextIn.Width = 4
+
+	caloriesTextIn := textinput.New()
+	caloriesTextIn.CharLimit = 5
+	caloriesTextIn.Width = 5
 
 	template := SetInput{
 		SetNo:      setno,
+		Measure:    MEASURE_REPS,
 		Reps:       repTextIn,
 		Weight:     weightTextIn,
+		RPE:        rpeTextIn,
+		Duration:   durationTextIn,
+		Distance:   distanceTextIn,
+		HeartRate:  heartRateTextIn,
+		Calories:   caloriesTextIn,
 		WorkoutId:  wrokoutId,
 		ExerciseId: exerciseId,
 		Datum:      time.Now(),
+		units:      st.Units,
 	}
 
 	return template
 }
+
+// CreateTimedSetTemplate makes a set of a cardio or timed exercise, see
+// MeasureOf, with duration in seconds and distance in km as targets.
+func (st Settings) CreateTimedSetTemplate(setno int, measure int, duration int, distance float64, workoutId uint, exerciseId string) SetInput {
+	template := st.CreateSetTemplate(setno, 0, 0, workoutId, exerciseId)
+	template.Measure = measure
+	template.Duration.Placeholder = notation.FormatDuration(duration)
+	template.Distance.Placeholder = strconv.FormatFloat(distance, 'f', -1, 64)
+	return template
+}
@@ -0,0 +1,28 @@
+package common
+
+import (
+	"testing"
+
+	wodb "github.com/zmnpl/clift/db"
+)
+
+func TestCreateSetInputsFromNotation(t *testing.T) {
+	squat := wodb.Exercise{ID: "Barbell_Squat", Data: `{"name": "Barbell Squat", "category": "strength"}`}
+	inputs, err := DefaultSettings().CreateSetInputsFromNotation("3x5@100 1x3@110", 0, squat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) != 4 {
+		t.Fatalf("%v inputs, want 4", len(inputs))
+	}
+	if s := inputs[3].Target(); inputs[3].Measure != MEASURE_REPS || s.Reps != 3 || s.Weight != 110 {
+		t.Errorf("last set = %+v", s)
+	}
+
+	for _, category := range []string{"cardio", "stretching"} {
+		e := wodb.Exercise{ID: "x", Data: `{"name": "X", "category": "` + category + `"}`}
+		if inputs, err := DefaultSettings().CreateSetInputsFromNotation("3x30", 0, e); err == nil {
+			t.Errorf("notation on a %v exercise made %v sets", category, len(inputs))
+		}
+	}
+}
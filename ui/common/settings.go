@@ -0,0 +1,72 @@
+package common
+
+import (
+	"github.com/zmnpl/clift/config"
+	"github.com/zmnpl/clift/notation"
+	"github.com/zmnpl/clift/plates"
+	"github.com/zmnpl/clift/progression"
+)
+
+// Settings are the preferences of the user the models work with. They are
+// taken from the config once at startup and handed down next to the store.
+type Settings struct {
+	Units           string
+	DefaultSetCount int
+	DefaultReps     int
+	E1RMFormula     string
+	PrefillLast     bool
+	DefaultRest     int // seconds
+	Plates          plates.Inventory
+	WarmupRamp      []progression.SchemeSet
+}
+
+// NewSettings reads the settings of c.
+func NewSettings(c config.Config) (Settings, error) {
+	inventory, err := c.PlateInventory()
+	if err != nil {
+		return Settings{}, err
+	}
+	ramp, err := progression.ParseRamp(c.Warmup)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	return Settings{
+		Units:           c.Units,
+		DefaultSetCount: c.DefaultSetCount,
+		DefaultReps:     c.DefaultReps,
+		E1RMFormula:     c.E1RMFormula,
+		PrefillLast:     c.PrefillLast,
+		DefaultRest:     c.DefaultRest,
+		Plates:          inventory,
+		WarmupRamp:      ramp,
+	}, nil
+}
+
+// DefaultSettings are the settings of the default config.
+func DefaultSettings() Settings {
+	s, _ := NewSettings(config.Default())
+	return s
+}
+
+// FormatWeight shows kilograms in the unit of the user, "100 kg".
+func (s Settings) FormatWeight(kg float64) string {
+	return notation.FormatWeight(kg, s.Units)
+}
+
+// DisplayWeight converts kilograms to the unit of the user.
+func (s Settings) DisplayWeight(kg float64) float64 {
+	return notation.FromKg(kg, s.Units)
+}
+
+// WeightValue writes kilograms in the unit of the user for an input, the way
+// ParseWeight reads them back.
+func (s Settings) WeightValue(kg float64) string {
+	return notation.WeightValue(s.DisplayWeight(kg))
+}
+
+// ParseWeight reads a weight in the unit of the user or with a unit of its
+// own like "135lb", see notation.ParseWeight.
+func (s Settings) ParseWeight(weight string) (kg float64, entered string, err error) {
+	return notation.ParseWeight(weight, s.Units)
+}
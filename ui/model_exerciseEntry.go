@@ -2,6 +2,8 @@ package ui
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -9,7 +11,9 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/notation"
 	coms "github.com/zmnpl/clift/ui/common"
 )
 
@@ -20,9 +24,14 @@ const (
 )
 
 type exerciseEntry struct {
+	store    wodb.Store
+	settings coms.Settings
+
 	focusIndex int
 	setInputs  []coms.SetInput
+	quickEntry textinput.Model
 	datum      time.Time
+	startedAt  time.Time
 
 	workout         *wodb.Workout
 	workoutExercise *wodb.WorkoutExercise
@@ -30,56 +39,82 @@ type exerciseEntry struct {
 
 	mode int
 
+	last      []wodb.PerformedSet // sets of the last session of the exercise
+	prefilled bool
+
+	rest time.Duration // started after each set, 0 no rest timer
+
 	help help.Model
 }
 
-func NewExerciseEntry(datum time.Time, exercise *wodb.Exercise) exerciseEntry {
+func NewExerciseEntry(store wodb.Store, settings coms.Settings, datum time.Time, exercise *wodb.Exercise) exerciseEntry {
 	if datum.IsZero() {
 		datum = time.Now()
 	}
 
 	m := exerciseEntry{
-		datum:    datum,
-		exercise: exercise,
-		help:     help.New(),
+		store:      store,
+		settings:   settings,
+		datum:      datum,
+		startedAt:  time.Now(),
+		exercise:   exercise,
+		quickEntry: newQuickEntry(),
+		help:       help.New(),
 	}
 
 	if m.exercise != nil {
-		m.setInputs = coms.CreateEmptySetTemplate(m.exercise.ID, 3)
+		// cardio is usually one long set
+		setCount := settings.DefaultSetCount
+		if coms.MeasureOf(*m.exercise) == coms.MEASURE_CARDIO {
+			setCount = 1
+		}
+		m.setInputs = settings.CreateEmptySetTemplate(*m.exercise, setCount)
 	} else if m.workoutExercise != nil {
-		m.setInputs = coms.CreateSetTemplatesForWE(*m.workoutExercise)
+		m.setInputs = settings.CreateSetTemplatesForWE(*m.workoutExercise)
 		m.exercise = &m.workoutExercise.Exercise
 	}
 
-	m.setInputs[0].FocusReps()
+	m.setInputs[0].FocusFirst()
 
 	return m
 }
 
-func NewWorkoutExerciseEntryModel(datum time.Time, workout *wodb.Workout, workoutExercise *wodb.WorkoutExercise, exercise *wodb.Exercise, setInputs []coms.SetInput, mode int) exerciseEntry {
+func NewWorkoutExerciseEntryModel(store wodb.Store, settings coms.Settings, datum time.Time, workout *wodb.Workout, workoutExercise *wodb.WorkoutExercise, exercise *wodb.Exercise, setInputs []coms.SetInput, mode int) exerciseEntry {
 	if datum.IsZero() {
 		datum = time.Now()
 	}
 
 	m := exerciseEntry{
+		store:           store,
+		settings:        settings,
 		datum:           datum,
+		startedAt:       time.Now(),
 		workout:         workout,
 		workoutExercise: workoutExercise,
 		exercise:        exercise,
 		setInputs:       setInputs,
+		quickEntry:      newQuickEntry(),
 		mode:            mode,
 		help:            help.New(),
 	}
 
 	if len(setInputs) > 0 {
-		m.setInputs[0].FocusReps()
+		m.setInputs[0].FocusFirst()
 	}
 
 	return m
 }
 
+func newQuickEntry() textinput.Model {
+	quickEntry := textinput.New()
+	quickEntry.Placeholder = "5x5@100"
+	quickEntry.CharLimit = 100
+	quickEntry.Width = 40
+	return quickEntry
+}
+
 func (m exerciseEntry) Init() tea.Cmd {
-	return tea.Batch(textinput.Blink)
+	return tea.Batch(textinput.Blink, coms.LoadLastPerformance(m.store, m.exercise.ID, m.datum))
 }
 
 func (m exerciseEntry) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -87,21 +122,65 @@ func (m exerciseEntry) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case coms.MsgExerciseLogged:
-		if msg.Err == nil {
-			return m, coms.Back
-		} else {
-			// TODO: bubble up error to user
+		if msg.Err != nil {
+			return m, coms.SendStatus("", msg.Err)
+		}
+		if msg.Status != "" {
+			return m, tea.Batch(coms.Back, coms.SendStatus(msg.Status, nil))
 		}
+		return m, coms.Back
 
 	case coms.MsgDate:
 		m.datum = time.Time(msg)
+		return m, coms.LoadLastPerformance(m.store, m.exercise.ID, m.datum)
+
+	case coms.MsgLastPerformance:
+		if msg.Err != nil {
+			return m, coms.SendStatus("", msg.Err)
+		}
+		if msg.ExerciseID != m.exercise.ID {
+			return m, cmd
+		}
+		m.last = msg.Sets
+		if m.settings.PrefillLast && !m.prefilled {
+			m.prefillFromLast()
+		}
 		return m, cmd
 
 	case tea.KeyMsg:
+		if m.quickEntry.Focused() {
+			return m.updateQuickEntry(msg)
+		}
+
 		switch msg.String() {
 		case "ctrl+c":
 			return m, tea.Quit
 
+		case "f2":
+			if m.focusIndex < len(m.setInputs) {
+				m.setInputs[m.focusIndex].Unfocus()
+			}
+			m.quickEntry.PromptStyle = coms.FocusedStyle
+			m.quickEntry.TextStyle = coms.FocusedStyle
+			return m, m.quickEntry.Focus()
+
+		case "f3":
+			if m.focusIndex < len(m.setInputs) {
+				m.setInputs[m.focusIndex].ToggleType()
+			}
+			return m, cmd
+
+		case "f12":
+			cmd = m.insertWarmUps()
+			return m, cmd
+
+		case "f4":
+			if len(m.last) == 0 {
+				return m, coms.SendStatus("no earlier sets of this exercise", nil)
+			}
+			m.prefillFromLast()
+			return m, coms.SendStatus("placeholders from "+m.last[0].PerformedDate.Local().Format("Mon 2006-01-02"), nil)
+
 		case "q":
 			// TODO - apply reps / weight from selected inputs plan to actual
 			return m, coms.SendStatus("foo", nil)
@@ -110,7 +189,7 @@ func (m exerciseEntry) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.focusIndex < len(m.setInputs) {
 				m.setInputs[m.focusIndex].PlaceholderToValue()
 			}
-			return m, cmd
+			return m, m.commitSet(m.focusIndex)
 
 		case "f10":
 			for i := 0; i < len(m.setInputs); i++ {
@@ -123,7 +202,7 @@ func (m exerciseEntry) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if m.mode == MODE_RETURN_SETS {
 					return m, coms.Ret(coms.SendPerformedSets(m.setInputs, m.workoutExercise.ID))
 				}
-				return m, coms.LogSingleExercise(m.datum, m.setInputs)
+				return m, coms.LogSingleExercise(m.store, m.settings, wodb.Session{StartedAt: m.startedAt}, m.datum, m.setInputs)
 			}
 
 		case "+":
@@ -136,88 +215,72 @@ func (m exerciseEntry) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.workout != nil {
 				mywid = m.workout.ID
 			}
-			m.setInputs = append(m.setInputs, coms.CreateSetTemplate(
-				len(m.setInputs)+1,
-				10,
-				0,
-				mywid,
-				m.exercise.ID))
+			m.setInputs = append(m.setInputs, m.newSet(len(m.setInputs)+1, m.settings.DefaultReps, 0, mywid))
 
 			if len(m.setInputs) > 0 {
 				m.setInputs[m.focusIndex].Unfocus()
 				m.focusIndex = len(m.setInputs) - 1
 			}
 
-			return m, m.setInputs[m.focusIndex].FocusReps()
+			return m, m.setInputs[m.focusIndex].FocusFirst()
 
 		case "-":
 			if len(m.setInputs) > 0 {
-				m.setInputs[m.focusIndex].Unfocus()
+				if m.focusIndex < len(m.setInputs) {
+					m.setInputs[m.focusIndex].Unfocus()
+				}
 				m.setInputs = m.setInputs[:len(m.setInputs)-1]
 			}
 			if len(m.setInputs) > 0 {
 				m.focusIndex = len(m.setInputs) - 1
-				return m, m.setInputs[m.focusIndex].FocusReps()
+				return m, m.setInputs[m.focusIndex].FocusFirst()
 			}
+			m.focusIndex = 0
 			return m, cmd
 
 		case "tab", "shift+tab", "up", "down":
 			s := msg.String()
+			left := m.focusIndex
+			backward := s == "up" || s == "shift+tab"
 
-			same, up, down := 0, 1, -1
-			direction := same
-			switch s {
-			case "up", "shift+tab":
-				if m.focusIndex == len(m.setInputs) || m.setInputs[m.focusIndex].Reps.Focused() {
-					m.focusIndex--
-					direction = up
+			// within line
+			if m.focusIndex < len(m.setInputs) {
+				set := &m.setInputs[m.focusIndex]
+				field := set.Focused()
+				if backward && field > 0 {
+					return m, set.FocusField(field - 1)
 				}
-			case "down", "tab":
-				if m.focusIndex == len(m.setInputs) || m.setInputs[m.focusIndex].Weight.Focused() {
-					m.focusIndex++
-					direction = down
+				if !backward && field >= 0 && field < len(set.Fields())-1 {
+					return m, set.FocusField(field + 1)
 				}
 			}
 
-			if m.focusIndex > len(m.setInputs) {
-				m.focusIndex = len(m.setInputs)
+			// moved up or down
+			if backward {
+				m.focusIndex--
+			} else {
+				m.focusIndex++
 			}
-			if m.focusIndex < 0 {
-				m.focusIndex = 0
+			m.focusIndex = max(0, min(m.focusIndex, len(m.setInputs)))
+			if m.focusIndex == left {
+				return m, cmd
 			}
 
-			// Set/unset focus
-			cmds := make([]tea.Cmd, len(m.setInputs))
-			for i := 0; i <= len(m.setInputs)-1; i++ {
-				if i == m.focusIndex {
-					// up top
-					if direction == up && i == 0 && m.setInputs[i].Reps.Focused() {
-						continue
-					}
-					// within line
-					if m.setInputs[i].Reps.Focused() {
-						cmds = append(cmds, m.setInputs[i].FocusWeight())
-						continue
-					}
-					if m.setInputs[i].Weight.Focused() {
-						cmds = append(cmds, m.setInputs[i].FocusReps())
-						continue
-					}
-					//moved up or down
-					if direction == up {
-						cmds = append(cmds, m.setInputs[i].FocusWeight())
-						continue
-					}
-					if direction == down {
-						cmds = append(cmds, m.setInputs[i].FocusReps())
-						continue
-					}
+			if left < len(m.setInputs) {
+				m.setInputs[left].Unfocus()
+			}
+			if m.focusIndex < len(m.setInputs) {
+				if backward {
+					cmd = m.setInputs[m.focusIndex].FocusLast()
+				} else {
+					cmd = m.setInputs[m.focusIndex].FocusFirst()
 				}
-				// remove focus from all others
-				m.setInputs[i].Unfocus()
 			}
 
-			return m, tea.Batch(cmds...)
+			if !backward {
+				return m, tea.Batch(cmd, m.commitSet(left))
+			}
+			return m, cmd
 
 		case "esc":
 			return m, coms.Back
@@ -227,23 +290,176 @@ func (m exerciseEntry) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, m.updateInputs(msg)
 }
 
+// commitSet starts the rest timer when set i is left with something entered,
+// once per set.
+func (m *exerciseEntry) commitSet(i int) tea.Cmd {
+	if m.rest <= 0 || i < 0 || i >= len(m.setInputs) {
+		return nil
+	}
+	set := &m.setInputs[i]
+	if set.Rested || !set.Entered() {
+		return nil
+	}
+	set.Rested = true
+	return coms.StartRest(m.rest, m.exercise.GetName())
+}
+
+// insertWarmUps puts warm-up sets in front of the first working set, ramping
+// up to its weight. Warm-ups with nothing entered yet are replaced.
+func (m *exerciseEntry) insertWarmUps() tea.Cmd {
+	sets := slices.DeleteFunc(slices.Clone(m.setInputs), func(s coms.SetInput) bool {
+		return s.Type == wodb.SET_WARMUP && !s.Entered()
+	})
+	first := slices.IndexFunc(sets, func(s coms.SetInput) bool {
+		return s.Type != wodb.SET_WARMUP && s.Measure == coms.MEASURE_REPS && s.Target().Weight > 0
+	})
+	if first < 0 {
+		return coms.SendStatus("no working set with a weight to warm up to", nil)
+	}
+
+	var wid uint
+	if m.workout != nil {
+		wid = m.workout.ID
+	}
+	sets = slices.Insert(sets, first, m.settings.CreateWarmUpTemplates(*m.exercise, sets[first].Target(), wid)...)
+	for i := range sets {
+		sets[i].SetNo = i + 1
+		sets[i].Unfocus()
+	}
+
+	m.setInputs = sets
+	m.focusIndex = first
+	return m.setInputs[first].FocusFirst()
+}
+
+// prefillFromLast sets the placeholders of the set inputs to the reps and
+// weights of the last session, adding inputs if there were more sets then.
+func (m *exerciseEntry) prefillFromLast() {
+	m.prefilled = true
+
+	var wid uint
+	if m.workout != nil {
+		wid = m.workout.ID
+	}
+
+	onSubmit := m.focusIndex == len(m.setInputs)
+	for i, s := range m.last {
+		if i >= len(m.setInputs) {
+			m.setInputs = append(m.setInputs, m.newSet(i+1, s.Reps, s.Weight, wid))
+		}
+		m.setInputs[i].Reps.Placeholder = strconv.Itoa(s.Reps)
+		m.setInputs[i].Weight.Placeholder = m.settings.WeightValue(s.Weight)
+		m.setInputs[i].Duration.Placeholder = notation.FormatDuration(s.Duration)
+		m.setInputs[i].Distance.Placeholder = strconv.FormatFloat(s.Distance, 'f', -1, 64)
+	}
+
+	if onSubmit {
+		m.focusIndex = len(m.setInputs)
+	}
+}
+
+// newSet makes set number no in the measure of the exercise, see
+// coms.MeasureOf.
+func (m exerciseEntry) newSet(no int, reps int, weight float64, wid uint) coms.SetInput {
+	measure := coms.MeasureOf(*m.exercise)
+	if measure != coms.MEASURE_REPS {
+		return m.settings.CreateTimedSetTemplate(no, measure, 0, 0, wid, m.exercise.ID)
+	}
+	return m.settings.CreateSetTemplate(no, reps, weight, wid, m.exercise.ID)
+}
+
+// updateQuickEntry handles keys while the quick entry line has focus. On
+// enter the parsed sets replace the current set inputs.
+func (m exerciseEntry) updateQuickEntry(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "enter":
+		var wid uint
+		if m.workout != nil {
+			wid = m.workout.ID
+		}
+		setInputs, err := m.settings.CreateSetInputsFromNotation(m.quickEntry.Value(), wid, *m.exercise)
+		if err != nil {
+			return m, coms.SendStatus("", err)
+		}
+		m.setInputs = setInputs
+		m.focusIndex = len(m.setInputs) // straight to submit
+		m.blurQuickEntry()
+		return m, coms.SendStatus(fmt.Sprintf("%v sets entered", len(setInputs)), nil)
+
+	case "esc":
+		m.blurQuickEntry()
+		if m.focusIndex < len(m.setInputs) {
+			return m, m.setInputs[m.focusIndex].FocusFirst()
+		}
+		return m, cmd
+	}
+
+	m.quickEntry, cmd = m.quickEntry.Update(msg)
+	return m, cmd
+}
+
+func (m *exerciseEntry) blurQuickEntry() {
+	m.quickEntry.Blur()
+	m.quickEntry.SetValue("")
+	m.quickEntry.PromptStyle = coms.NoStyle
+	m.quickEntry.TextStyle = coms.NoStyle
+}
+
 func (m exerciseEntry) View() string {
 	sb := &strings.Builder{}
 
-	sb.WriteString(coms.FocusedStyle.Render("Date: ") + m.datum.Format("2006-01-02") + "\n\n")
-	for _, v := range m.setInputs {
-		sb.WriteString(fmt.Sprintf("%v | Reps %s Weight %s\n", v.SetNo, v.Reps.View(), v.Weight.View()))
+	sb.WriteString(coms.FocusedStyle.Render("Date: ") + m.datum.Format("2006-01-02") + "\n")
+	if len(m.last) > 0 {
+		sb.WriteString(coms.BlurredStyle.Render(fmt.Sprintf("Last time: %v, %v sets", m.last[0].PerformedDate.Local().Format("Mon 2006-01-02"), len(m.last))) + "\n")
+	}
+	sb.WriteString("\n")
+	for i, v := range m.setInputs {
+		sb.WriteString(fmt.Sprintf("%v%v | %s", v.SetNo, setTypeStyle(v.Type).Render(fmt.Sprintf("%1v", coms.SetTypeTag(v.Type))), v.View()))
+		if v.Type != wodb.SET_WORKING {
+			sb.WriteString(setTypeStyle(v.Type).Render("  " + coms.SetTypeName(v.Type)))
+		}
+		if i < len(m.last) {
+			sb.WriteString(coms.BlurredStyle.Render("  last " + m.settings.FormatPerformed(m.last[i])))
+		}
+		sb.WriteString("\n")
+	}
+	if plates := m.platesView(); plates != "" {
+		sb.WriteString("\n" + coms.BlurredStyle.Render("plates ") + plates + "\n")
 	}
 
-	button := blurredButton
+	button := blurredButton()
 	if m.focusIndex == len(m.setInputs) {
-		button = focusedButton
+		button = focusedButton()
 	}
 	sb.WriteString(fmt.Sprintf("\n%v\n", button))
+	sb.WriteString("\n" + coms.BlurredStyle.Render("quick entry ") + m.quickEntry.View() + "\n")
 
 	return sb.String()
 }
 
+// platesView shows how to load the bar for the weight of the focused set of a
+// barbell exercise.
+func (m exerciseEntry) platesView() string {
+	if !coms.IsBarbell(*m.exercise) || m.focusIndex >= len(m.setInputs) {
+		return ""
+	}
+	set := m.setInputs[m.focusIndex]
+	if set.Measure != coms.MEASURE_REPS || set.Target().Weight <= 0 {
+		return ""
+	}
+	return m.settings.DescribePlates(set.Target().Weight)
+}
+
+// setTypeStyle sets warm-ups apart from the sets that count.
+func setTypeStyle(t string) lipgloss.Style {
+	if t == wodb.SET_WARMUP {
+		return coms.BlurredStyle
+	}
+	return coms.FocusedStyle
+}
+
 func (m exerciseEntry) Help() string {
 	sb := &strings.Builder{}
 
@@ -259,13 +475,11 @@ func (m *exerciseEntry) updateInputs(msg tea.Msg) tea.Cmd {
 	// Only text inputs with Focus() set will respond, so it's safe to simply
 	// update all of them here without any further logic.
 	for i := range m.setInputs {
-		var cmd tea.Cmd
-
-		m.setInputs[i].Reps, cmd = m.setInputs[i].Reps.Update(msg)
-		cmds = append(cmds, cmd)
-
-		m.setInputs[i].Weight, cmd = m.setInputs[i].Weight.Update(msg)
-		cmds = append(cmds, cmd)
+		for _, f := range m.setInputs[i].Fields() {
+			var cmd tea.Cmd
+			*f, cmd = f.Update(msg)
+			cmds = append(cmds, cmd)
+		}
 	}
 	return tea.Batch(cmds...)
 }
@@ -283,20 +497,24 @@ type exerciseEntryKeymap struct {
 	changedate          key.Binding
 	applyPlaceholder    key.Binding
 	applyPlaceholderAll key.Binding
+	quickEntry          key.Binding
+	prefillLast         key.Binding
+	setType             key.Binding
+	warmUps             key.Binding
 }
 
 // ShortHelp returns keybindings to be shown in the mini help view. It's part
 // of the key.Map interface.
 func (k exerciseEntryKeymap) ShortHelp() []key.Binding {
-	return []key.Binding{k.nav, k.changedate, k.applyPlaceholder, k.applyPlaceholderAll, k.confirm, k.back}
+	return []key.Binding{k.nav, k.changedate, k.applyPlaceholder, k.applyPlaceholderAll, k.quickEntry, k.setType, k.warmUps, k.prefillLast, k.confirm, k.back}
 }
 
 // FullHelp returns keybindings for the expanded help view. It's part of the
 // key.Map interface.
 func (k exerciseEntryKeymap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.nav, k.applyPlaceholder, k.changedate}, // first column
-		{k.confirm, k.back},                       // second column
+		{k.nav, k.applyPlaceholder, k.changedate, k.quickEntry, k.setType, k.warmUps, k.prefillLast}, // first column
+		{k.confirm, k.back}, // second column
 	}
 }
 
@@ -317,6 +535,22 @@ var exerciseEntryKeys = exerciseEntryKeymap{
 		key.WithKeys("f10"),
 		key.WithHelp("f10", "apply placeholder all"),
 	),
+	quickEntry: key.NewBinding(
+		key.WithKeys("f2"),
+		key.WithHelp("f2", "quick entry"),
+	),
+	prefillLast: key.NewBinding(
+		key.WithKeys("f4"),
+		key.WithHelp("f4", "last time"),
+	),
+	setType: key.NewBinding(
+		key.WithKeys("f3"),
+		key.WithHelp("f3", "set type"),
+	),
+	warmUps: key.NewBinding(
+		key.WithKeys("f12"),
+		key.WithHelp("f12", "warm-ups"),
+	),
 	confirm: key.NewBinding(
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "confirm"),
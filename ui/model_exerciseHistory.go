@@ -0,0 +1,254 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zmnpl/clift/analytics"
+	wodb "github.com/zmnpl/clift/db"
+	coms "github.com/zmnpl/clift/ui/common"
+)
+
+const (
+	METRIC_TOP_SET = iota
+	METRIC_E1RM
+	METRIC_VOLUME
+)
+
+var historyMetrics = []string{"top set", "e1RM", "volume"}
+
+type historyWindow struct {
+	label  string
+	months int // 0 is everything
+}
+
+var historyWindows = []historyWindow{
+	{"1M", 1},
+	{"3M", 3},
+	{"6M", 6},
+	{"1Y", 12},
+	{"all", 0},
+}
+
+type exerciseHistory struct {
+	store    wodb.Store
+	settings coms.Settings
+	exercise *wodb.Exercise
+
+	stats  []analytics.SessionStat // oldest first
+	metric int
+	window int
+
+	sessions table.Model
+	ws       tea.WindowSizeMsg
+
+	help help.Model
+}
+
+func NewExerciseHistory(store wodb.Store, settings coms.Settings, exercise *wodb.Exercise) exerciseHistory {
+	return exerciseHistory{
+		store:    store,
+		settings: settings,
+		exercise: exercise,
+		metric:   METRIC_E1RM,
+		window:   2,
+		sessions: coms.MakeTable([]table.Column{
+			{Title: "Date", Width: 15},
+			{Title: "Sets", Width: 5},
+			{Title: "Top set", Width: 16},
+			{Title: "e1RM", Width: 10},
+			{Title: "Volume", Width: 10},
+		}),
+		help: help.New(),
+	}
+}
+
+func (m exerciseHistory) Init() tea.Cmd {
+	return coms.LoadExerciseHistory(m.store, m.exercise.ID)
+}
+
+func (m exerciseHistory) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.ws = msg
+		m.sessions.SetHeight(max(m.contentHeight()-m.chartHeight()-3, 3))
+
+	case coms.MsgExerciseHistory:
+		if msg.Err != nil {
+			return m, coms.SendStatus("", msg.Err)
+		}
+		m.stats = analytics.Sessions(m.settings.E1RMFormula, msg.Sets)
+		m.refreshSessions()
+		return m, tea.WindowSize()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, coms.Back
+
+		case "tab":
+			m.metric = (m.metric + 1) % len(historyMetrics)
+			return m, nil
+
+		case "shift+tab":
+			m.metric = (m.metric + len(historyMetrics) - 1) % len(historyMetrics)
+			return m, nil
+
+		case "w":
+			m.window = (m.window + 1) % len(historyWindows)
+			m.refreshSessions()
+			return m, nil
+		}
+	}
+
+	m.sessions, cmd = m.sessions.Update(msg)
+	return m, cmd
+}
+
+// visible are the sessions within the selected time window, oldest first.
+func (m exerciseHistory) visible() []analytics.SessionStat {
+	months := historyWindows[m.window].months
+	if months == 0 {
+		return m.stats
+	}
+
+	from := time.Now().AddDate(0, -months, 0)
+	for i, s := range m.stats {
+		if !s.Date.Before(from) {
+			return m.stats[i:]
+		}
+	}
+	return nil
+}
+
+func (m *exerciseHistory) refreshSessions() {
+	stats := m.visible()
+	rows := make([]table.Row, 0, len(stats))
+	for i := len(stats) - 1; i >= 0; i-- {
+		s := stats[i]
+		rows = append(rows, table.Row{
+			s.Date.Local().Format("Mon 2006-01-02"),
+			strconv.Itoa(s.Sets),
+			fmt.Sprintf("%v × %v", s.TopReps, m.settings.FormatWeight(s.TopWeight)),
+			formatValue(m.settings.DisplayWeight(s.E1RM)),
+			formatValue(m.settings.DisplayWeight(s.Volume)),
+		})
+	}
+	m.sessions.SetRows(rows)
+	m.sessions.GotoTop()
+}
+
+// value is the charted metric of s in the unit of the user.
+func (m exerciseHistory) value(s analytics.SessionStat) float64 {
+	switch m.metric {
+	case METRIC_TOP_SET:
+		return m.settings.DisplayWeight(s.TopWeight)
+	case METRIC_VOLUME:
+		return m.settings.DisplayWeight(s.Volume)
+	}
+	return m.settings.DisplayWeight(s.E1RM)
+}
+
+func (m exerciseHistory) contentHeight() int {
+	return coms.GetContentHeight(m.ws.Height)
+}
+
+func (m exerciseHistory) chartHeight() int {
+	return max(m.contentHeight()*2/5, 4)
+}
+
+func (m exerciseHistory) chart() string {
+	stats := m.visible()
+	width := min(max(m.ws.Width-4, 30), 100)
+
+	xs := make([]float64, len(stats))
+	ys := make([]float64, len(stats))
+	for i, s := range stats {
+		xs[i] = float64(s.Date.Unix())
+		ys[i] = m.value(s)
+	}
+
+	sb := &strings.Builder{}
+	if len(stats) == 0 {
+		sb.WriteString("No sessions in this time window.")
+		sb.WriteString(strings.Repeat("\n", m.chartHeight()))
+		return sb.String()
+	}
+
+	sb.WriteString(coms.LineChart(xs, ys, width, m.chartHeight()) + "\n")
+	first := stats[0].Date.Local().Format("2006-01-02")
+	last := stats[len(stats)-1].Date.Local().Format("2006-01-02")
+	gap := max(width-9-len(first)-len(last), 1)
+	sb.WriteString(strings.Repeat(" ", 9) + first + strings.Repeat(" ", gap) + last + "\n")
+	return sb.String()
+}
+
+func (m exerciseHistory) View() string {
+	sb := &strings.Builder{}
+
+	tabs := make([]string, len(historyMetrics))
+	for i, name := range historyMetrics {
+		if i == m.metric {
+			tabs[i] = coms.FocusedStyle.Render("[" + name + "]")
+		} else {
+			tabs[i] = " " + name + " "
+		}
+	}
+	title := fmt.Sprintf("%v  %v  %v", coms.FocusedStyle.Render(m.exercise.GetName()), strings.Join(tabs, ""), historyWindows[m.window].label)
+	sb.WriteString(title + "\n\n")
+
+	sb.WriteString(m.chart())
+	sb.WriteString(m.sessions.View() + "\n")
+
+	return sb.String()
+}
+
+func (m exerciseHistory) BreadCrumb() string {
+	return "history"
+}
+
+func (m exerciseHistory) Help() string {
+	return m.help.View(historyKeys)
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
+
+//------------------------------------------------------
+
+type historyKeymap struct {
+	metric key.Binding
+	window key.Binding
+	back   key.Binding
+}
+
+func (k historyKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.metric, k.window, k.back}
+}
+
+func (k historyKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.metric, k.window, k.back}}
+}
+
+var historyKeys = historyKeymap{
+	metric: key.NewBinding(
+		key.WithKeys("tab", "shift+tab"),
+		key.WithHelp("tab", "metric"),
+	),
+	window: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "time window"),
+	),
+	back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+}
@@ -18,6 +18,9 @@ const (
 )
 
 type exerciseSelect struct {
+	store    wodb.Store
+	settings coms.Settings
+
 	exerciseList list.Model
 	mode         int
 
@@ -29,13 +32,15 @@ type exerciseSelect struct {
 	status string
 }
 
-func NewDoExercise(workoutID uint, datum time.Time) exerciseSelect {
+func NewDoExercise(store wodb.Store, settings coms.Settings, workoutID uint, datum time.Time) exerciseSelect {
 
 	if datum.IsZero() {
 		datum = time.Now()
 	}
 
 	m := exerciseSelect{
+		store:        store,
+		settings:     settings,
 		mode:         MODE_EXERCISE_DO,
 		exerciseList: list.New(make([]list.Item, 0), coms.ListItemStyle(), 0, 0),
 		workoutID:    workoutID,
@@ -45,13 +50,15 @@ func NewDoExercise(workoutID uint, datum time.Time) exerciseSelect {
 	return m
 }
 
-func NewSelectExercise(workoutID uint, datum time.Time) exerciseSelect {
+func NewSelectExercise(store wodb.Store, settings coms.Settings, workoutID uint, datum time.Time) exerciseSelect {
 
 	if datum.IsZero() {
 		datum = time.Now()
 	}
 
 	m := exerciseSelect{
+		store:        store,
+		settings:     settings,
 		mode:         MODE_EXERCISE_RETURNID,
 		exerciseList: list.New(make([]list.Item, 0), coms.ListItemStyle(), 0, 0),
 		workoutID:    workoutID,
@@ -66,7 +73,7 @@ func (m *exerciseSelect) refreshExerciseList(exercises []wodb.Exercise) {
 	// exercise list
 	items := make([]list.Item, len(exercises))
 	for i := range exercises {
-		items[i] = coms.ExerciseItem{&exercises[i]}
+		items[i] = coms.ExerciseItem{Exercise: &exercises[i]}
 	}
 	exerciseList := list.New(items, coms.ListItemStyle(), 0, 0)
 	exerciseList.Title = "Select Exercise"
@@ -80,6 +87,7 @@ func (m *exerciseSelect) refreshExerciseList(exercises []wodb.Exercise) {
 	exerciseList.AdditionalShortHelpKeys = func() []key.Binding {
 		return []key.Binding{
 			exerciseSelectKeys.logExercise,
+			exerciseSelectKeys.history,
 			exerciseSelectKeys.selectDate,
 			exerciseSelectKeys.back,
 		}
@@ -89,7 +97,7 @@ func (m *exerciseSelect) refreshExerciseList(exercises []wodb.Exercise) {
 }
 
 func (m exerciseSelect) Init() tea.Cmd {
-	return tea.Batch(coms.ReloadExercises, textinput.Blink)
+	return tea.Batch(coms.ReloadExercises(m.store), textinput.Blink)
 }
 
 func (m exerciseSelect) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -116,7 +124,7 @@ func (m exerciseSelect) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.status = msg.Err.Error()
 			return m, cmd
 		}
-		return m, coms.GoTo(NewWorkoutModel(m.workoutID, m.datum))
+		return m, coms.GoTo(NewWorkoutModel(m.store, m.settings, m.workoutID, m.datum))
 
 	case tea.KeyMsg:
 		if m.exerciseList.FilterState() == list.Filtering {
@@ -126,11 +134,16 @@ func (m exerciseSelect) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			switch m.mode {
 			case MODE_EXERCISE_DO:
-				return m, coms.GoTo(NewExerciseEntry(m.datum, m.exerciseList.SelectedItem().(coms.ExerciseItem).Exercise))
+				return m, coms.GoTo(NewExerciseEntry(m.store, m.settings, m.datum, m.exerciseList.SelectedItem().(coms.ExerciseItem).Exercise))
 			case MODE_EXERCISE_RETURNID:
 				return m, coms.Ret(coms.SendExerciseID(m.exerciseList.SelectedItem().(coms.ExerciseItem).ID))
 			}
 
+		case "f3":
+			if item, ok := m.exerciseList.SelectedItem().(coms.ExerciseItem); ok {
+				return m, coms.GoTo(NewExerciseHistory(m.store, m.settings, item.Exercise))
+			}
+
 		case "esc":
 			return m, coms.Back
 		}
@@ -160,6 +173,7 @@ func (m exerciseSelect) Help() string {
 
 type exerciseSelectKeymap struct {
 	logExercise key.Binding
+	history     key.Binding
 	back        key.Binding
 	selectDate  key.Binding
 }
@@ -169,6 +183,10 @@ var exerciseSelectKeys = exerciseSelectKeymap{
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "log exercise"),
 	),
+	history: key.NewBinding(
+		key.WithKeys("f3"),
+		key.WithHelp("f3", "history"),
+	),
 	selectDate: key.NewBinding(
 		key.WithKeys("f5"),
 		key.WithHelp("f5", "change date"),
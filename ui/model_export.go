@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/export"
+	coms "github.com/zmnpl/clift/ui/common"
+)
+
+const (
+	EXPORT_FORMAT = iota
+	EXPORT_FROM
+	EXPORT_TO
+	EXPORT_EXERCISES
+	EXPORT_PATH
+)
+
+var exportLabels = []string{"Format", "From", "To", "Exercises", "Path"}
+
+type exportForm struct {
+	store    wodb.Store
+	settings coms.Settings
+
+	inputs     []textinput.Model
+	focusIndex int
+
+	help help.Model
+}
+
+func NewExportModel(store wodb.Store, settings coms.Settings) exportForm {
+	inputs := make([]textinput.Model, len(exportLabels))
+	for i := range inputs {
+		inputs[i] = textinput.New()
+		inputs[i].Width = 60
+	}
+	inputs[EXPORT_FORMAT].Placeholder = export.JSON + " or " + export.CSV
+	inputs[EXPORT_FROM].Placeholder = "YYYY-MM-DD"
+	inputs[EXPORT_TO].Placeholder = "YYYY-MM-DD"
+	inputs[EXPORT_EXERCISES].Placeholder = "all, or comma separated ids / names"
+	inputs[EXPORT_PATH].Placeholder = coms.DefaultExportPath(export.JSON)
+
+	m := exportForm{
+		store:    store,
+		settings: settings,
+		inputs:   inputs,
+		help:     help.New(),
+	}
+	m.focus(0)
+
+	return m
+}
+
+func (m exportForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m exportForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, coms.Back
+
+		case "enter":
+			if m.focusIndex == len(m.inputs) {
+				return m, coms.Export(m.store,
+					m.format(),
+					strings.TrimSpace(m.inputs[EXPORT_PATH].Value()),
+					strings.TrimSpace(m.inputs[EXPORT_FROM].Value()),
+					strings.TrimSpace(m.inputs[EXPORT_TO].Value()),
+					strings.TrimSpace(m.inputs[EXPORT_EXERCISES].Value()))
+			}
+			return m, m.focus(m.focusIndex + 1)
+
+		case "tab", "down":
+			return m, m.focus(m.focusIndex + 1)
+
+		case "shift+tab", "up":
+			return m, m.focus(m.focusIndex - 1)
+		}
+	}
+
+	if m.focusIndex < len(m.inputs) {
+		m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
+		m.inputs[EXPORT_PATH].Placeholder = coms.DefaultExportPath(m.format())
+	}
+	return m, cmd
+}
+
+func (m exportForm) format() string {
+	format := strings.ToLower(strings.TrimSpace(m.inputs[EXPORT_FORMAT].Value()))
+	if format == "" {
+		return export.JSON
+	}
+	return format
+}
+
+// focus moves the focus to input i; one past the inputs is the submit button.
+func (m *exportForm) focus(i int) tea.Cmd {
+	m.focusIndex = max(0, min(i, len(m.inputs)))
+
+	var cmd tea.Cmd
+	for j := range m.inputs {
+		if j == m.focusIndex {
+			cmd = m.inputs[j].Focus()
+			m.inputs[j].PromptStyle = coms.FocusedStyle
+			m.inputs[j].TextStyle = coms.FocusedStyle
+			continue
+		}
+		m.inputs[j].Blur()
+		m.inputs[j].PromptStyle = coms.NoStyle
+		m.inputs[j].TextStyle = coms.NoStyle
+	}
+	return cmd
+}
+
+func (m exportForm) View() string {
+	sb := &strings.Builder{}
+
+	for i, in := range m.inputs {
+		sb.WriteString(fmt.Sprintf("%-10v %v\n", exportLabels[i], in.View()))
+	}
+
+	button := blurredButton()
+	if m.focusIndex == len(m.inputs) {
+		button = focusedButton()
+	}
+	sb.WriteString(fmt.Sprintf("\n%v\n", button))
+
+	return sb.String()
+}
+
+func (m exportForm) BreadCrumb() string {
+	return "export"
+}
+
+func (m exportForm) Help() string {
+	return m.help.View(exportKeys)
+}
+
+//------------------------------------------------------
+
+type exportKeymap struct {
+	nav     key.Binding
+	confirm key.Binding
+	back    key.Binding
+}
+
+func (k exportKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.nav, k.confirm, k.back}
+}
+
+func (k exportKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.nav, k.confirm, k.back}}
+}
+
+var exportKeys = exportKeymap{
+	nav: key.NewBinding(
+		key.WithKeys("up", "down", "tab"),
+		key.WithHelp("↑/↓/tab", "navigate"),
+	),
+	confirm: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "next / export"),
+	),
+	back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+}
@@ -1,51 +1,659 @@
 package ui
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/notation"
 	coms "github.com/zmnpl/clift/ui/common"
 )
 
+const (
+	JOURNAL_DATE = iota
+	JOURNAL_SET
+	JOURNAL_REPS
+	JOURNAL_WEIGHT
+	JOURNAL_DURATION
+	JOURNAL_DISTANCE
+	JOURNAL_HEART_RATE
+	JOURNAL_CALORIES
+	JOURNAL_RPE
+)
+
+var journalEditLabels = []string{"Date", "Set", "Reps", "Weight", "Time", "Km", "HR", "kcal", "RPE"}
+
+// edit form inputs by measure of the exercise, see coms.MeasureOf
+var journalEditFields = map[int][]int{
+	coms.MEASURE_REPS:   {JOURNAL_DATE, JOURNAL_SET, JOURNAL_REPS, JOURNAL_WEIGHT, JOURNAL_RPE},
+	coms.MEASURE_CARDIO: {JOURNAL_DATE, JOURNAL_SET, JOURNAL_DURATION, JOURNAL_DISTANCE, JOURNAL_HEART_RATE, JOURNAL_CALORIES},
+	coms.MEASURE_TIME:   {JOURNAL_DATE, JOURNAL_SET, JOURNAL_DURATION},
+}
+
+const (
+	JOURNAL_FILTER_EXERCISE = iota
+	JOURNAL_FILTER_MUSCLE
+	JOURNAL_FILTER_WORKOUT
+	JOURNAL_FILTER_FROM
+	JOURNAL_FILTER_TO
+)
+
+var journalFilterLabels = []string{"Exercise", "Muscle", "Workout", "From", "To"}
+
+const (
+	JOURNAL_FORM_NONE = iota
+	JOURNAL_FORM_EDIT
+	JOURNAL_FORM_FILTER
+)
+
 type journal struct {
-	journal table.Model
+	store    wodb.Store
+	settings coms.Settings
+
+	journal   table.Model
+	rows      []coms.JournalRow // what the rows of journal stand for
+	collapsed map[string]bool   // collapsed exercise blocks
+
+	// loaded so far, newest first
+	sets     []wodb.PerformedSet
+	sessions map[uint]wodb.Session
+	more     bool // there may be older sets to load
+	loading  bool
+
+	exercises []wodb.Exercise
+	workouts  []wodb.Workout
+	names     coms.JournalNames
+
+	filter     wodb.SetFilter
+	filterText string // summary of the applied filter
+
+	// edit or filter form, shown below the journal
+	form         int
+	editInputs   []textinput.Model
+	editMeasure  int // picks the edit inputs shown
+	filterInputs []textinput.Model
+	focusIndex   int
+
+	// how the sets were before each change, the last one on top
+	undo []coms.JournalChange
+
+	deleteUnlocked bool
+
+	help help.Model
 }
 
-func NewReportModel() journal {
-	return journal{}
+func NewReportModel(store wodb.Store, settings coms.Settings) journal {
+	editInputs := make([]textinput.Model, len(journalEditLabels))
+	for i := range editInputs {
+		editInputs[i] = textinput.New()
+		editInputs[i].Width = 10
+	}
+	editInputs[JOURNAL_DATE].Placeholder = "YYYY-MM-DD"
+	editInputs[JOURNAL_DATE].CharLimit = 10
+	editInputs[JOURNAL_DURATION].Placeholder = "m:ss"
+	editInputs[JOURNAL_HEART_RATE].Width = 4
+	editInputs[JOURNAL_CALORIES].Width = 5
+	editInputs[JOURNAL_RPE].Width = 6
+
+	filterInputs := make([]textinput.Model, len(journalFilterLabels))
+	for i := range filterInputs {
+		filterInputs[i] = textinput.New()
+		filterInputs[i].Width = 12
+		filterInputs[i].Placeholder = "all"
+	}
+	filterInputs[JOURNAL_FILTER_FROM].Placeholder = "YYYY-MM-DD"
+	filterInputs[JOURNAL_FILTER_TO].Placeholder = "YYYY-MM-DD"
+
+	return journal{
+		store:        store,
+		settings:     settings,
+		journal:      coms.MakeJournal(nil),
+		collapsed:    make(map[string]bool),
+		sessions:     make(map[uint]wodb.Session),
+		editInputs:   editInputs,
+		filterInputs: filterInputs,
+		help:         help.New(),
+	}
 }
 
 func (m journal) Init() tea.Cmd {
-	return tea.Batch(coms.LoadPerformedSets)
+	return tea.Batch(
+		coms.ReloadExercises(m.store),
+		coms.ReloadWorkouts(m.store),
+		coms.LoadJournalPage(m.store, m.filter, 0, coms.JOURNAL_PAGE_SIZE))
 }
 
 func (m journal) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		m.journal.SetHeight(msg.Height - coms.HEADER_FOOTER_HEIGHT)
+		height := msg.Height - coms.HEADER_FOOTER_HEIGHT
+		if m.form != JOURNAL_FORM_NONE {
+			height -= 2
+		}
+		if m.filtered() {
+			height--
+		}
+		m.journal.SetHeight(height)
+
+	case coms.LockCriticalKey:
+		m.deleteUnlocked = false
+		return m, coms.SendStatus("", nil)
+
+	case coms.MsgExercisesReload:
+		m.exercises = msg.Exercises
+		m.names.Exercises = make(map[string]string, len(msg.Exercises))
+		for _, e := range msg.Exercises {
+			m.names.Exercises[e.ID] = e.GetName()
+		}
+		m.rebuild()
+		return m, nil
 
-	case coms.MsgPerformedSetsLoaded:
-		m.journal = coms.MakeJournal(msg.PerformedSets)
-		return m, tea.Batch(cmd, tea.WindowSize())
+	case coms.MsgWorkoutsReload:
+		m.workouts = msg.Workouts
+		m.names.Workouts = make(map[uint]string, len(msg.Workouts))
+		for _, w := range msg.Workouts {
+			m.names.Workouts[w.ID] = w.Name
+		}
+		m.rebuild()
+		return m, nil
+
+	case coms.MsgJournalPage:
+		m.loading = false
+		if msg.Err != nil {
+			return m, coms.SendStatus("", msg.Err)
+		}
+		if msg.Offset == 0 {
+			m.sets = msg.Sets
+		} else {
+			m.sets = append(m.sets, msg.Sets...)
+		}
+		for _, s := range msg.Sessions {
+			m.sessions[s.ID] = s
+		}
+		m.more = len(msg.Sets) == msg.Limit
+		m.rebuild()
+		return m, tea.WindowSize()
+
+	case coms.MsgJournalChanged:
+		if msg.Err != nil {
+			return m, coms.SendStatus("", msg.Err)
+		}
+		if !msg.Undo.IsEmpty() {
+			m.undo = append(m.undo, msg.Undo)
+		}
+		return m, tea.Batch(m.reload(), coms.SendStatus(msg.Status, nil))
 
 	case tea.KeyMsg:
+		switch m.form {
+		case JOURNAL_FORM_EDIT:
+			return m.updateEdit(msg)
+		case JOURNAL_FORM_FILTER:
+			return m.updateFilter(msg)
+		}
+
 		switch msg.String() {
 		case "esc":
 			return m, coms.Back
-		default:
 
+		case "enter", "e":
+			row, ok := m.selectedRow()
+			if !ok {
+				return m, nil
+			}
+			if row.Kind == coms.JOURNAL_ROW_SET {
+				return m, m.startEdit(row.Sets[0])
+			}
+			m.toggle(row)
+			return m, nil
+
+		case " ":
+			if row, ok := m.selectedRow(); ok {
+				m.toggle(row)
+			}
+			return m, nil
+
+		case "/":
+			return m, m.openForm(JOURNAL_FORM_FILTER, JOURNAL_FILTER_EXERCISE)
+
+		case "delete":
+			row, ok := m.selectedRow()
+			if !ok || row.Kind != coms.JOURNAL_ROW_SET {
+				return m, nil
+			}
+			if m.deleteUnlocked {
+				m.deleteUnlocked = false
+				return m, coms.DeletePerformedSets(m.store, row.Sets)
+			}
+			m.deleteUnlocked = true
+			return m, tea.Batch(coms.SendStatus("Press delete again to delete that set.", nil), coms.SleepToLockKey(2000*time.Millisecond))
+
+		case "D":
+			row, ok := m.selectedRow()
+			if !ok {
+				return m, nil
+			}
+			if m.deleteUnlocked {
+				m.deleteUnlocked = false
+				return m, coms.DeleteDay(m.store, row.Day)
+			}
+			m.deleteUnlocked = true
+			return m, tea.Batch(coms.SendStatus(fmt.Sprintf("Press D again to delete all sets of %v.", row.Day.Format("Mon 2006-01-02")), nil), coms.SleepToLockKey(2000*time.Millisecond))
+
+		case "t":
+			row, ok := m.selectedRow()
+			if !ok || row.Kind != coms.JOURNAL_ROW_SET {
+				return m, nil
+			}
+			old := row.Sets[0]
+			set := old
+			set.Type = wodb.SetTypes[(slices.Index(wodb.SetTypes, set.Type)+1)%len(wodb.SetTypes)]
+			return m, coms.UpdatePerformedSetType(m.store, old, set)
+
+		case "u":
+			if len(m.undo) == 0 {
+				return m, coms.SendStatus("Nothing to undo", nil)
+			}
+			change := m.undo[len(m.undo)-1]
+			m.undo = m.undo[:len(m.undo)-1]
+			return m, coms.RestorePerformedSets(m.store, change)
 		}
 	}
 
 	m.journal, cmd = m.journal.Update(msg)
-	return m, cmd
+	more := m.loadMore()
+	return m, tea.Batch(cmd, more)
+}
+
+// rebuild turns the loaded sets into table rows, keeping the cursor.
+func (m *journal) rebuild() {
+	var tableRows []table.Row
+	m.rows, tableRows = m.settings.BuildJournal(m.sets, m.sessions, m.names, m.collapsed)
+
+	cursor := m.journal.Cursor()
+	m.journal.SetRows(tableRows)
+	m.journal.SetCursor(min(cursor, max(0, len(tableRows)-1)))
+}
+
+// reload loads everything loaded so far again, e.g. after a change.
+func (m journal) reload() tea.Cmd {
+	return coms.LoadJournalPage(m.store, m.filter, 0, max(len(m.sets), coms.JOURNAL_PAGE_SIZE))
+}
+
+// loadMore fetches the next page once the cursor reaches the last row.
+func (m *journal) loadMore() tea.Cmd {
+	if !m.more || m.loading || m.journal.Cursor() < len(m.rows)-1 {
+		return nil
+	}
+	m.loading = true
+	return coms.LoadJournalPage(m.store, m.filter, len(m.sets), coms.JOURNAL_PAGE_SIZE)
+}
+
+func (m journal) selectedRow() (coms.JournalRow, bool) {
+	i := m.journal.Cursor()
+	if i < 0 || i >= len(m.rows) {
+		return coms.JournalRow{}, false
+	}
+	return m.rows[i], true
+}
+
+// toggle collapses or expands the block of row. On a day row it does so for
+// all blocks of the day.
+func (m *journal) toggle(row coms.JournalRow) {
+	if row.Kind != coms.JOURNAL_ROW_DAY {
+		m.collapsed[row.Block] = !m.collapsed[row.Block]
+		m.rebuild()
+		// stay on the exercise row, its sets may just have disappeared
+		for i, r := range m.rows {
+			if r.Kind == coms.JOURNAL_ROW_EXERCISE && r.Block == row.Block {
+				m.journal.SetCursor(i)
+				break
+			}
+		}
+		return
+	}
+
+	blocks := make([]string, 0, 10)
+	for _, r := range m.rows {
+		if r.Group == row.Group && r.Kind == coms.JOURNAL_ROW_EXERCISE {
+			blocks = append(blocks, r.Block)
+		}
+	}
+	collapse := slices.ContainsFunc(blocks, func(b string) bool { return !m.collapsed[b] })
+	for _, b := range blocks {
+		m.collapsed[b] = collapse
+	}
+	m.rebuild()
+}
+
+func (m journal) filtered() bool {
+	return m.filterText != ""
+}
+
+func (m *journal) inputs() []textinput.Model {
+	switch m.form {
+	case JOURNAL_FORM_EDIT:
+		return m.editInputs
+	case JOURNAL_FORM_FILTER:
+		return m.filterInputs
+	}
+	return nil
+}
+
+// fields are the indices of the inputs shown in the open form, in order.
+func (m journal) fields() []int {
+	if m.form == JOURNAL_FORM_EDIT {
+		return journalEditFields[m.editMeasure]
+	}
+	fields := make([]int, len(m.filterInputs))
+	for i := range fields {
+		fields[i] = i
+	}
+	return fields
+}
+
+func (m *journal) openForm(form, focus int) tea.Cmd {
+	m.form = form
+	m.journal.Blur()
+	return tea.Batch(m.focus(focus), tea.WindowSize())
+}
+
+func (m *journal) closeForm() tea.Cmd {
+	m.focus(-1)
+	m.form = JOURNAL_FORM_NONE
+	m.journal.Focus()
+	return tea.WindowSize()
+}
+
+func (m *journal) startEdit(set wodb.PerformedSet) tea.Cmd {
+	m.editMeasure = coms.MEASURE_REPS
+	if e, ok := wodb.FindExercise(m.exercises, set.ExerciseID); ok {
+		m.editMeasure = coms.MeasureOf(e)
+	}
+
+	m.editInputs[JOURNAL_DATE].SetValue(set.PerformedDate.Local().Format("2006-01-02"))
+	m.editInputs[JOURNAL_SET].SetValue(strconv.Itoa(set.SetNo + 1))
+	m.editInputs[JOURNAL_REPS].SetValue(strconv.Itoa(set.Reps))
+	m.editInputs[JOURNAL_WEIGHT].SetValue(m.editWeight(set))
+	m.editInputs[JOURNAL_DURATION].SetValue(notation.FormatDuration(set.Duration))
+	m.editInputs[JOURNAL_DISTANCE].SetValue(strconv.FormatFloat(set.Distance, 'f', -1, 64))
+	m.editInputs[JOURNAL_HEART_RATE].SetValue(optional(set.HeartRate))
+	m.editInputs[JOURNAL_CALORIES].SetValue(optional(set.Calories))
+	m.editInputs[JOURNAL_RPE].SetValue(notation.FormatEffort(set.RPE, set.RIR))
+	return m.openForm(JOURNAL_FORM_EDIT, journalEditFields[m.editMeasure][2])
+}
+
+// editWeight shows the weight of set in the unit it was entered in, naming
+// that unit if it isn't the one of the user.
+func (m journal) editWeight(set wodb.PerformedSet) string {
+	if set.Unit == "" || set.Unit == m.settings.Units {
+		return m.settings.WeightValue(set.Weight)
+	}
+	return notation.WeightValue(notation.FromKg(set.Weight, set.Unit)) + set.Unit
+}
+
+// optional leaves unknown values empty.
+func optional(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// updateForm handles navigation in the open form; ok is false for keys it
+// leaves to the caller.
+func (m *journal) updateForm(msg tea.KeyMsg) (tea.Cmd, bool) {
+	fields := m.fields()
+	n := len(fields)
+	at := slices.Index(fields, m.focusIndex)
+	switch msg.String() {
+	case "esc":
+		return m.closeForm(), true
+	case "tab":
+		return m.focus(fields[(at+1)%n]), true
+	case "shift+tab":
+		return m.focus(fields[(at+n-1)%n]), true
+	case "enter":
+		return nil, false
+	}
+
+	var cmd tea.Cmd
+	inputs := m.inputs()
+	inputs[m.focusIndex], cmd = inputs[m.focusIndex].Update(msg)
+	return cmd, true
+}
+
+// updateEdit handles keys while the edit form is open.
+func (m journal) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if cmd, ok := m.updateForm(msg); ok {
+		return m, cmd
+	}
+
+	row, ok := m.selectedRow()
+	if !ok || row.Kind != coms.JOURNAL_ROW_SET {
+		return m, m.closeForm()
+	}
+	old := row.Sets[0]
+	set, err := m.editedSet(old)
+	if err != nil {
+		return m, coms.SendStatus("", err)
+	}
+	return m, tea.Batch(m.closeForm(), coms.UpdatePerformedSet(m.store, old, set))
+}
+
+// updateFilter handles keys while the filter form is open.
+func (m journal) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if cmd, ok := m.updateForm(msg); ok {
+		return m, cmd
+	}
+
+	filter, err := m.parseFilter()
+	if err != nil {
+		return m, coms.SendStatus("", err)
+	}
+	m.filter = filter
+	parts := make([]string, 0, len(m.filterInputs))
+	for i, in := range m.filterInputs {
+		if v := strings.TrimSpace(in.Value()); v != "" {
+			parts = append(parts, strings.ToLower(journalFilterLabels[i])+" "+v)
+		}
+	}
+	m.filterText = strings.Join(parts, " · ")
+	m.sets = nil
+	m.more = false
+	m.rebuild()
+	m.journal.GotoTop()
+	return m, tea.Batch(m.closeForm(), coms.LoadJournalPage(m.store, m.filter, 0, coms.JOURNAL_PAGE_SIZE))
+}
+
+// editedSet applies the edit form to set. The time of day is kept when the
+// date changes.
+func (m journal) editedSet(set wodb.PerformedSet) (wodb.PerformedSet, error) {
+	day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(m.editInputs[JOURNAL_DATE].Value()), time.Local)
+	if err != nil {
+		return set, fmt.Errorf("invalid date, want YYYY-MM-DD")
+	}
+	setNo, err := strconv.Atoi(strings.TrimSpace(m.editInputs[JOURNAL_SET].Value()))
+	if err != nil || setNo < 1 {
+		return set, fmt.Errorf("invalid set number")
+	}
+	set.PerformedDate = coms.OnDay(day, set.PerformedDate.Local())
+	set.SetNo = setNo - 1
+
+	if m.editMeasure == coms.MEASURE_REPS {
+		reps, err := strconv.Atoi(strings.TrimSpace(m.editInputs[JOURNAL_REPS].Value()))
+		if err != nil || reps < 1 {
+			return set, fmt.Errorf("invalid reps")
+		}
+		weight, unit, err := m.settings.ParseWeight(m.editInputs[JOURNAL_WEIGHT].Value())
+		if err != nil {
+			return set, err
+		}
+		rpe, rir, err := notation.ParseEffort(m.editInputs[JOURNAL_RPE].Value())
+		if err != nil {
+			return set, err
+		}
+		set.Reps = reps
+		set.Weight = weight
+		set.Unit = unit
+		set.RPE = rpe
+		set.RIR = rir
+		return set, nil
+	}
+
+	unit := time.Second
+	if m.editMeasure == coms.MEASURE_CARDIO {
+		unit = time.Minute
+	}
+	set.Duration, err = notation.ParseDuration(m.editInputs[JOURNAL_DURATION].Value(), unit)
+	if err != nil {
+		return set, err
+	}
+	if m.editMeasure == coms.MEASURE_CARDIO {
+		set.Distance, err = notation.ParseDistance(m.editInputs[JOURNAL_DISTANCE].Value())
+		if err != nil {
+			return set, err
+		}
+		set.HeartRate, err = optionalInt(m.editInputs[JOURNAL_HEART_RATE].Value())
+		if err != nil {
+			return set, fmt.Errorf("invalid heart rate")
+		}
+		set.Calories, err = optionalInt(m.editInputs[JOURNAL_CALORIES].Value())
+		if err != nil {
+			return set, fmt.Errorf("invalid calories")
+		}
+	}
+	if set.IsEmpty() {
+		return set, fmt.Errorf("enter a time or distance")
+	}
+	return set, nil
+}
+
+// optionalInt reads a number that may be left empty.
+func optionalInt(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return n, nil
+}
+
+// parseFilter resolves the filter form. Exercises are comma separated ids or
+// names; a muscle narrows them down to exercises working it primarily.
+func (m journal) parseFilter() (wodb.SetFilter, error) {
+	value := func(i int) string { return strings.TrimSpace(m.filterInputs[i].Value()) }
+	filter := wodb.SetFilter{}
+	var err error
+
+	if v := value(JOURNAL_FILTER_FROM); v != "" {
+		filter.From, err = time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid from date: %v", v)
+		}
+	}
+	if v := value(JOURNAL_FILTER_TO); v != "" {
+		filter.To, err = time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid to date: %v", v)
+		}
+	}
+
+	if v := value(JOURNAL_FILTER_WORKOUT); v != "" {
+		id, _ := strconv.ParseUint(v, 10, 64)
+		for _, w := range m.workouts {
+			if uint64(w.ID) == id || strings.EqualFold(w.Name, v) {
+				filter.WorkoutID = w.ID
+				break
+			}
+		}
+		if filter.WorkoutID == 0 {
+			return filter, fmt.Errorf("Unknown workout: %v", v)
+		}
+	}
+
+	if v := value(JOURNAL_FILTER_EXERCISE); v != "" {
+		for _, q := range strings.Split(v, ",") {
+			e, ok := wodb.FindExercise(m.exercises, strings.TrimSpace(q))
+			if !ok {
+				return filter, fmt.Errorf("Unknown exercise: %v", q)
+			}
+			filter.ExerciseIDs = append(filter.ExerciseIDs, e.ID)
+		}
+	}
+
+	if v := value(JOURNAL_FILTER_MUSCLE); v != "" {
+		ids := make([]string, 0, 100)
+		for _, e := range m.exercises {
+			if !slices.ContainsFunc(e.GetPrimaryMuscles(), func(mu string) bool { return strings.EqualFold(mu, v) }) {
+				continue
+			}
+			if len(filter.ExerciseIDs) == 0 || slices.Contains(filter.ExerciseIDs, e.ID) {
+				ids = append(ids, e.ID)
+			}
+		}
+		if len(ids) == 0 {
+			return filter, fmt.Errorf("No exercises for muscle: %v", v)
+		}
+		filter.ExerciseIDs = ids
+	}
+
+	return filter, nil
+}
+
+// focus moves the focus to input i of the open form; anything out of range
+// blurs all inputs.
+func (m *journal) focus(i int) tea.Cmd {
+	m.focusIndex = i
+
+	var cmd tea.Cmd
+	inputs := m.inputs()
+	for j := range inputs {
+		if j == i {
+			cmd = inputs[j].Focus()
+			inputs[j].PromptStyle = coms.FocusedStyle
+			inputs[j].TextStyle = coms.FocusedStyle
+			continue
+		}
+		inputs[j].Blur()
+		inputs[j].PromptStyle = coms.NoStyle
+		inputs[j].TextStyle = coms.NoStyle
+	}
+	return cmd
 }
 
 func (m journal) View() string {
 	sb := &strings.Builder{}
+
+	if m.filtered() {
+		sb.WriteString(coms.FocusedStyle.Render("Filter: ") + m.filterText + "\n")
+	}
+
 	sb.WriteString(m.journal.View() + "\n")
+
+	labels := journalEditLabels
+	if m.form == JOURNAL_FORM_FILTER {
+		labels = journalFilterLabels
+	}
+	if m.form != JOURNAL_FORM_NONE {
+		sb.WriteString("\n")
+		inputs := m.inputs()
+		for _, i := range m.fields() {
+			sb.WriteString(coms.FocusedStyle.Render(labels[i]+": ") + inputs[i].View() + " ")
+		}
+		sb.WriteString("\n")
+	}
 	return sb.String()
 }
 
@@ -54,5 +662,88 @@ func (m journal) BreadCrumb() string {
 }
 
 func (m journal) Help() string {
-	return ""
+	switch m.form {
+	case JOURNAL_FORM_EDIT:
+		return m.help.View(journalEditKeys)
+	case JOURNAL_FORM_FILTER:
+		return m.help.View(journalFilterKeys)
+	}
+	return m.help.View(journalKeys)
+}
+
+//------------------------------------------------------
+
+type journalKeymap struct {
+	edit      key.Binding
+	setType   key.Binding
+	collapse  key.Binding
+	filter    key.Binding
+	deleteSet key.Binding
+	deleteDay key.Binding
+	undo      key.Binding
+	back      key.Binding
+}
+
+func (k journalKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.edit, k.setType, k.collapse, k.filter, k.deleteSet, k.deleteDay, k.undo, k.back}
+}
+
+func (k journalKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.edit, k.setType, k.collapse, k.filter, k.deleteSet, k.deleteDay, k.undo, k.back}}
+}
+
+var journalKeys = journalKeymap{
+	edit: key.NewBinding(
+		key.WithKeys("enter", "e"),
+		key.WithHelp("enter", "edit"),
+	),
+	setType: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "set type"),
+	),
+	collapse: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "fold"),
+	),
+	filter: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "filter"),
+	),
+	deleteSet: key.NewBinding(
+		key.WithKeys("delete"),
+		key.WithHelp("del", "delete set"),
+	),
+	deleteDay: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "delete day"),
+	),
+	undo: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "undo"),
+	),
+	back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+}
+
+var journalEditKeys = exportKeymap{
+	nav: key.NewBinding(
+		key.WithKeys("tab", "shift+tab"),
+		key.WithHelp("tab", "next field"),
+	),
+	confirm: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "save"),
+	),
+	back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "cancel"),
+	),
+}
+
+var journalFilterKeys = exportKeymap{
+	nav:     journalEditKeys.nav,
+	confirm: key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "apply")),
+	back:    journalEditKeys.back,
 }
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	wodb "github.com/zmnpl/clift/db"
+	coms "github.com/zmnpl/clift/ui/common"
+)
+
+type librarySelect struct {
+	store    wodb.Store
+	settings coms.Settings
+
+	programList list.Model
+}
+
+func NewLibrarySelectModel(store wodb.Store, settings coms.Settings) librarySelect {
+	return librarySelect{
+		store:       store,
+		settings:    settings,
+		programList: list.New(make([]list.Item, 0), coms.ListItemStyle(), 0, 0),
+	}
+}
+
+func (m librarySelect) Init() tea.Cmd {
+	return coms.LoadLibrary
+}
+
+func (m librarySelect) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case coms.MsgLibrary:
+		if msg.Err != nil {
+			return m, coms.SendStatus("", msg.Err)
+		}
+		m.refreshProgramList(msg)
+		return m, tea.WindowSize()
+
+	// an installed program is handed on to the program list
+	case coms.MsgProgramChanged:
+		if msg.Err != nil {
+			return m, coms.SendStatus("", msg.Err)
+		}
+		return m, coms.Ret(func() tea.Msg { return msg })
+
+	case tea.WindowSizeMsg:
+		m.programList.SetHeight(coms.GetContentHeight(msg.Height) - 3)
+
+	case tea.KeyMsg:
+		if m.programList.FilterState() == list.Filtering {
+			break
+		}
+
+		switch msg.String() {
+		case "enter":
+			if item, ok := m.programList.SelectedItem().(coms.LibraryItem); ok {
+				return m, coms.GoTo(NewLibraryInstallForm(m.store, m.settings, item.Program))
+			}
+
+		case "esc":
+			return m, coms.Back
+		}
+	}
+
+	m.programList, cmd = m.programList.Update(msg)
+	return m, cmd
+}
+
+func (m librarySelect) View() string {
+	sb := &strings.Builder{}
+	sb.WriteString(m.programList.View() + "\n\n")
+	sb.WriteString(m.programList.Help.View(m.programList))
+	return sb.String()
+}
+
+func (m *librarySelect) refreshProgramList(msg coms.MsgLibrary) {
+	items := make([]list.Item, len(msg.Programs))
+	for i := range msg.Programs {
+		items[i] = coms.LibraryItem{Program: msg.Programs[i]}
+	}
+	l := list.New(items, coms.ListItemStyle(), 0, 0)
+	l.Title = "Program Library"
+	l.SetSize(ListWidth, coms.WINDOW_HEIGHT-coms.HEADER_FOOTER_HEIGHT)
+	l.SetShowTitle(false)
+	l.SetShowHelp(false)
+	l.FilterInput.Cursor.Style = coms.FilterCursorStyle
+	l.FilterInput.PromptStyle = coms.FilterPromptStyle
+
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			librarySelectKeys.install,
+			librarySelectKeys.back,
+		}
+	}
+
+	m.programList = l
+}
+
+func (m librarySelect) BreadCrumb() string {
+	return "library"
+}
+
+func (m librarySelect) Help() string {
+	return ""
+}
+
+// ---------------------------------------------------------------
+type librarySelectKeymap struct {
+	install key.Binding
+	back    key.Binding
+}
+
+var librarySelectKeys = librarySelectKeymap{
+	install: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "install"),
+	),
+	back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+}
@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/library"
+	coms "github.com/zmnpl/clift/ui/common"
+)
+
+// libraryInstallForm asks for the maxes of the lifts of a library program
+// before installing it.
+type libraryInstallForm struct {
+	store    wodb.Store
+	settings coms.Settings
+	program  library.Program
+	lifts    []string
+
+	inputs     []textinput.Model
+	focusIndex int
+
+	help help.Model
+}
+
+func NewLibraryInstallForm(store wodb.Store, settings coms.Settings, program library.Program) libraryInstallForm {
+	lifts := program.Lifts()
+	inputs := make([]textinput.Model, len(lifts))
+	for i := range inputs {
+		inputs[i] = textinput.New()
+		inputs[i].Placeholder = settings.Units
+		inputs[i].Width = 10
+	}
+
+	m := libraryInstallForm{
+		store:    store,
+		settings: settings,
+		program:  program,
+		lifts:    lifts,
+		inputs:   inputs,
+		help:     help.New(),
+	}
+	m.focus(0)
+
+	return m
+}
+
+func (m libraryInstallForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m libraryInstallForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, coms.Back
+
+		case "enter":
+			if m.focusIndex == len(m.inputs) {
+				maxes, err := m.maxes()
+				if err != nil {
+					return m, coms.SendStatus("", err)
+				}
+				return m, coms.Ret(coms.InstallProgram(m.store, m.settings, m.program, maxes))
+			}
+			return m, m.focus(m.focusIndex + 1)
+
+		case "tab", "down":
+			return m, m.focus(m.focusIndex + 1)
+
+		case "shift+tab", "up":
+			return m, m.focus(m.focusIndex - 1)
+		}
+	}
+
+	if m.focusIndex < len(m.inputs) {
+		m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
+	}
+	return m, cmd
+}
+
+// maxes reads the inputs by exercise ID, in kilograms.
+func (m libraryInstallForm) maxes() (map[string]float64, error) {
+	maxes := make(map[string]float64, len(m.lifts))
+	for i, lift := range m.lifts {
+		value := strings.TrimSpace(m.inputs[i].Value())
+		kg, _, err := m.settings.ParseWeight(value)
+		if err != nil || kg <= 0 {
+			return nil, fmt.Errorf("%v: enter a weight, got %q", liftName(lift), value)
+		}
+		maxes[lift] = kg
+	}
+	return maxes, nil
+}
+
+// focus moves the focus to input i; one past the inputs is the submit button.
+func (m *libraryInstallForm) focus(i int) tea.Cmd {
+	m.focusIndex = max(0, min(i, len(m.inputs)))
+
+	var cmd tea.Cmd
+	for j := range m.inputs {
+		if j == m.focusIndex {
+			cmd = m.inputs[j].Focus()
+			m.inputs[j].PromptStyle = coms.FocusedStyle
+			m.inputs[j].TextStyle = coms.FocusedStyle
+			continue
+		}
+		m.inputs[j].Blur()
+		m.inputs[j].PromptStyle = coms.NoStyle
+		m.inputs[j].TextStyle = coms.NoStyle
+	}
+	return cmd
+}
+
+func (m libraryInstallForm) View() string {
+	sb := &strings.Builder{}
+
+	sb.WriteString(coms.FocusedStyle.Render(m.program.Name) + "\n")
+	sb.WriteString(m.program.Description + "\n\n")
+
+	if len(m.lifts) > 0 {
+		sb.WriteString(coms.BlurredStyle.Render("Maxes: "+m.program.Maxes) + "\n")
+	}
+	width := 0
+	for _, lift := range m.lifts {
+		width = max(width, len(liftName(lift)))
+	}
+	for i, in := range m.inputs {
+		sb.WriteString(fmt.Sprintf("%-*v %v\n", width, liftName(m.lifts[i]), in.View()))
+	}
+
+	button := blurredButton()
+	if m.focusIndex == len(m.inputs) {
+		button = focusedButton()
+	}
+	sb.WriteString(fmt.Sprintf("\n%v\n", button))
+
+	return sb.String()
+}
+
+// liftName makes an exercise ID readable.
+func liftName(id string) string {
+	return strings.ReplaceAll(id, "_", " ")
+}
+
+func (m libraryInstallForm) BreadCrumb() string {
+	return m.program.Name
+}
+
+func (m libraryInstallForm) Help() string {
+	return m.help.View(libraryInstallKeys)
+}
+
+//------------------------------------------------------
+
+type libraryInstallKeymap struct {
+	nav     key.Binding
+	confirm key.Binding
+	back    key.Binding
+}
+
+func (k libraryInstallKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.nav, k.confirm, k.back}
+}
+
+func (k libraryInstallKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.nav, k.confirm, k.back}}
+}
+
+var libraryInstallKeys = libraryInstallKeymap{
+	nav: key.NewBinding(
+		key.WithKeys("up", "down", "tab"),
+		key.WithHelp("↑/↓/tab", "navigate"),
+	),
+	confirm: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "next / install"),
+	),
+	back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	wodb "github.com/zmnpl/clift/db"
 	coms "github.com/zmnpl/clift/ui/common"
 )
 
@@ -23,6 +24,9 @@ const (
 )
 
 type model struct {
+	store    wodb.Store
+	settings coms.Settings
+
 	screenStack   []tea.Model
 	currentScreen tea.Model
 
@@ -34,12 +38,27 @@ type model struct {
 
 	// signals / error
 	statusMsg coms.StatusMsg
+
+	rest restTimer
+}
+
+// restTimer counts the rest between sets down; it runs while end is set.
+type restTimer struct {
+	id       int
+	end      time.Time
+	exercise string
 }
 
-func NewModel() model {
+func (r restTimer) running() bool {
+	return !r.end.IsZero()
+}
+
+func NewModel(store wodb.Store, settings coms.Settings) model {
 	screnStack := make([]tea.Model, 0, 100)
 
 	return model{
+		store:       store,
+		settings:    settings,
 		screenStack: screnStack,
 		datum:       time.Now(),
 		help:        help.New(),
@@ -63,6 +82,33 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "f5": // change date works from everywhere as well
 			return m, coms.GoTo(NewDateSelectModel(m.datum))
 		}
+		if m.rest.running() {
+			switch msg.String() {
+			case "f6":
+				m.rest.end = m.rest.end.Add(-coms.REST_STEP)
+				return m, cmd
+			case "f7":
+				m.rest.end = m.rest.end.Add(coms.REST_STEP)
+				return m, cmd
+			case "f8":
+				m.rest.end = time.Time{}
+				return m, coms.SendStatus("Rest skipped", nil)
+			}
+		}
+
+	case coms.MsgRestStart:
+		m.rest = restTimer{id: m.rest.id + 1, end: time.Now().Add(msg.Rest), exercise: msg.Exercise}
+		return m, coms.RestTick(m.rest.id)
+
+	case coms.MsgRestTick:
+		if int(msg) != m.rest.id || !m.rest.running() {
+			return m, cmd
+		}
+		if time.Until(m.rest.end) > 0 {
+			return m, coms.RestTick(m.rest.id)
+		}
+		m.rest.end = time.Time{}
+		return m, tea.Batch(coms.Bell, coms.SendStatus("Rest is over, next set of "+m.rest.exercise, nil))
 
 	case coms.MsgDate:
 		m.datum = time.Time(msg)
@@ -113,16 +159,43 @@ func (m model) upateScreenMain(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case coms.MsgToday:
+		if msg.Err != nil {
+			return m, coms.SendStatus("", msg.Err)
+		}
+		if msg.Program == nil {
+			return m, coms.SendStatus("No program to follow yet, pick one under programs (7)", nil)
+		}
+		if w, _ := msg.Program.Position(); w < 0 {
+			return m, coms.SendStatus(msg.Program.Name+" has no days scheduled", nil)
+		}
+		return m, coms.GoTo(NewProgramWorkoutModel(m.store, m.settings, *msg.Program, m.datum))
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "1":
-			return m, coms.GoTo(NewWorkoutSelectModel(m.datum))
+			return m, coms.GoTo(NewWorkoutSelectModel(m.store, m.settings, m.datum))
 
 		case "2":
-			return m, coms.GoTo(NewDoExercise(0, time.Now()))
+			return m, coms.GoTo(NewDoExercise(m.store, m.settings, 0, time.Now()))
 
 		case "3":
-			return m, coms.GoTo(NewReportModel())
+			return m, coms.GoTo(NewReportModel(m.store, m.settings))
+
+		case "4":
+			return m, coms.GoTo(NewExportModel(m.store, m.settings))
+
+		case "5":
+			return m, coms.GoTo(NewVolumeModel(m.store, m.settings))
+
+		case "6":
+			return m, coms.LoadToday(m.store)
+
+		case "7":
+			return m, coms.GoTo(NewProgramSelectModel(m.store, m.settings))
+
+		case "8":
+			return m, coms.GoTo(NewTimerModel(m.store, m.settings, m.datum))
 
 		case "esc":
 			m.statusMsg = coms.StatusMsg{}
@@ -132,10 +205,14 @@ func (m model) upateScreenMain(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 }
 
-var (
-	focusedButton = coms.FocusedStyle.Render("[ Submit ]")
-	blurredButton = fmt.Sprintf("[ %s ]", coms.BlurredStyle.Render("Submit"))
-)
+// styles depend on the theme, so buttons are rendered on demand
+func focusedButton() string {
+	return coms.FocusedStyle.Render("[ Submit ]")
+}
+
+func blurredButton() string {
+	return fmt.Sprintf("[ %s ]", coms.BlurredStyle.Render("Submit"))
+}
 
 func (m model) View() string {
 	sb := &strings.Builder{}
@@ -221,6 +298,10 @@ func (m model) makeStatus() string {
 	}
 
 	status.WriteString(statusRenderer.Render("~") + coms.StatusCenter.Render(fmt.Sprintf("%v", statusText)))
+	if m.rest.running() {
+		left := max(time.Until(m.rest.end), 0)
+		status.WriteString("  " + coms.FocusedStyle.Render("rest "+coms.FormatRest(left)) + coms.BlurredStyle.Render(" f6 -15s f7 +15s f8 skip"))
+	}
 
 	return status.String()
 }
@@ -230,6 +311,11 @@ func (m model) viewScreenMain() string {
 	sb.WriteString(coms.FocusedStyle.Render("1) ") + "workouts" + "\n")
 	sb.WriteString(coms.FocusedStyle.Render("2) ") + "exercises" + "\n")
 	sb.WriteString(coms.FocusedStyle.Render("3) ") + "journal" + "\n")
+	sb.WriteString(coms.FocusedStyle.Render("4) ") + "export" + "\n")
+	sb.WriteString(coms.FocusedStyle.Render("5) ") + "volume" + "\n")
+	sb.WriteString(coms.FocusedStyle.Render("6) ") + "today" + "\n")
+	sb.WriteString(coms.FocusedStyle.Render("7) ") + "programs" + "\n")
+	sb.WriteString(coms.FocusedStyle.Render("8) ") + "timer" + "\n")
 	return sb.String()
 }
 
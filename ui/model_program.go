@@ -0,0 +1,337 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/progression"
+	coms "github.com/zmnpl/clift/ui/common"
+)
+
+const (
+	PROGRAM_EDIT_NONE = iota
+	PROGRAM_EDIT_NAME
+	PROGRAM_EDIT_SCHEME
+)
+
+// programRow is the position a table row stands for. Weeks without days get a
+// row with day -1.
+type programRow struct {
+	week, day int
+}
+
+type program struct {
+	store    wodb.Store
+	settings coms.Settings
+
+	id      uint
+	program wodb.Program
+	rows    []programRow
+	table   table.Model
+
+	edit      textinput.Model
+	editField int
+
+	addToWeek      uint // week the next picked workout is added to
+	deleteUnlocked bool
+
+	help help.Model
+}
+
+func NewProgramModel(store wodb.Store, settings coms.Settings, id uint) program {
+	edit := textinput.New()
+	edit.Width = 50
+
+	return program{
+		store:    store,
+		settings: settings,
+		id:       id,
+		table: coms.MakeTable([]table.Column{
+			{Title: "", Width: 2},
+			{Title: "Week", Width: 14},
+			{Title: "Day", Width: 4},
+			{Title: "Workout", Width: 30},
+			{Title: "Scheme", Width: 30},
+		}),
+		edit: edit,
+		help: help.New(),
+	}
+}
+
+func (m program) Init() tea.Cmd {
+	return coms.LoadProgram(m.store, m.id)
+}
+
+func (m program) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.table.SetHeight(max(coms.GetContentHeight(msg.Height)-3, 3))
+
+	case coms.LockCriticalKey:
+		m.deleteUnlocked = false
+		return m, coms.SendStatus("", nil)
+
+	case coms.MsgProgram:
+		if msg.Err != nil {
+			return m, coms.SendStatus("", msg.Err)
+		}
+		m.program = msg.Program
+		m.rebuild()
+		return m, cmd
+
+	case coms.MsgProgramChanged:
+		return m, tea.Batch(coms.SendStatus(msg.Status, msg.Err), coms.LoadProgram(m.store, m.id))
+
+	case coms.MsgWorkoutID:
+		return m, coms.AddProgramDay(m.store, m.addToWeek, uint(msg))
+
+	case tea.KeyMsg:
+		if m.editField != PROGRAM_EDIT_NONE {
+			return m.updateEdit(msg)
+		}
+
+		row, ok := m.selected()
+		switch msg.String() {
+		case "esc":
+			return m, coms.Back
+
+		case "w":
+			return m, coms.AddProgramWeek(m.store, m.program)
+
+		case "+":
+			if !ok {
+				return m, coms.SendStatus("Add a week first (w)", nil)
+			}
+			m.addToWeek = m.program.Weeks[row.week].ID
+			return m, coms.GoTo(NewWorkoutPicker(m.store, m.settings))
+
+		case "enter":
+			if ok && row.day >= 0 {
+				return m, coms.SetProgramPosition(m.store, m.program.ID, row.week, row.day)
+			}
+			return m, cmd
+
+		case "n", "s":
+			if !ok {
+				return m, cmd
+			}
+			week := m.program.Weeks[row.week]
+			m.editField = PROGRAM_EDIT_NAME
+			m.edit.Placeholder = "e.g. deload"
+			m.edit.SetValue(week.Name)
+			if msg.String() == "s" {
+				m.editField = PROGRAM_EDIT_SCHEME
+				m.edit.Placeholder = "percent x reps of the training max, e.g. 65x5 75x5 85x5+"
+				m.edit.SetValue(week.Scheme)
+			}
+			m.edit.CursorEnd()
+			return m, m.edit.Focus()
+
+		case "delete", "D":
+			if !ok {
+				return m, cmd
+			}
+			wholeWeek := msg.String() == "D" || row.day < 0
+			if m.deleteUnlocked {
+				m.deleteUnlocked = false
+				if wholeWeek {
+					return m, coms.RemoveProgramWeek(m.store, m.program.Weeks[row.week].ID)
+				}
+				return m, coms.RemoveProgramDay(m.store, m.program.Weeks[row.week].Days[row.day].ID)
+			}
+			m.deleteUnlocked = true
+			what := "this day"
+			if wholeWeek {
+				what = "the whole week"
+			}
+			return m, tea.Batch(coms.SendStatus("Press "+msg.String()+" once more to remove "+what+".", nil), coms.SleepToLockKey(2000*time.Millisecond))
+		}
+	}
+
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// updateEdit handles keys while the name or scheme of a week is edited.
+func (m program) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "esc":
+		m.stopEdit()
+		return m, cmd
+
+	case "enter":
+		row, ok := m.selected()
+		if !ok {
+			m.stopEdit()
+			return m, cmd
+		}
+		week := m.program.Weeks[row.week]
+		value := strings.TrimSpace(m.edit.Value())
+		if m.editField == PROGRAM_EDIT_SCHEME {
+			if _, err := progression.ParseScheme(value); err != nil {
+				return m, coms.SendStatus("", err)
+			}
+			week.Scheme = value
+		} else {
+			week.Name = value
+		}
+		m.stopEdit()
+		return m, coms.UpdateProgramWeek(m.store, week)
+	}
+
+	m.edit, cmd = m.edit.Update(msg)
+	return m, cmd
+}
+
+func (m *program) stopEdit() {
+	m.editField = PROGRAM_EDIT_NONE
+	m.edit.Blur()
+	m.edit.SetValue("")
+}
+
+func (m program) selected() (programRow, bool) {
+	i := m.table.Cursor()
+	if i < 0 || i >= len(m.rows) {
+		return programRow{}, false
+	}
+	return m.rows[i], true
+}
+
+func (m *program) rebuild() {
+	posWeek, posDay := m.program.Position()
+
+	m.rows = make([]programRow, 0, 30)
+	rows := make([]table.Row, 0, 30)
+	for w, week := range m.program.Weeks {
+		title := strconv.Itoa(w + 1)
+		if week.Name != "" {
+			title += " " + week.Name
+		}
+
+		if len(week.Days) == 0 {
+			m.rows = append(m.rows, programRow{w, -1})
+			rows = append(rows, table.Row{"", title, "", "no days yet", week.Scheme})
+			continue
+		}
+		for d, day := range week.Days {
+			marker := ""
+			if w == posWeek && d == posDay {
+				marker = "▶"
+			}
+			workout := day.Workout.Name
+			if day.Workout.Deleted.Valid {
+				workout += " (deleted)"
+			}
+
+			m.rows = append(m.rows, programRow{w, d})
+			rows = append(rows, table.Row{marker, title, strconv.Itoa(d + 1), workout, week.Scheme})
+			title = ""
+			week.Scheme = ""
+		}
+	}
+
+	m.table.SetRows(rows)
+	// an empty table leaves the cursor at -1
+	if c := m.table.Cursor(); c < 0 || c >= len(rows) {
+		m.table.SetCursor(max(min(c, len(rows)-1), 0))
+	}
+}
+
+func (m program) View() string {
+	sb := &strings.Builder{}
+
+	title := coms.FocusedStyle.Render(m.program.Name)
+	if m.program.Active {
+		title += " ★"
+	}
+	sb.WriteString(title + "\n")
+
+	switch m.editField {
+	case PROGRAM_EDIT_NAME:
+		sb.WriteString("Week name: " + m.edit.View() + "\n")
+	case PROGRAM_EDIT_SCHEME:
+		sb.WriteString("Scheme: " + m.edit.View() + "\n")
+	default:
+		sb.WriteString(coms.BlurredStyle.Render("Schemes set the targets of exercises with a training max.") + "\n")
+	}
+
+	sb.WriteString(m.table.View() + "\n")
+	return sb.String()
+}
+
+func (m program) BreadCrumb() string {
+	return m.program.Name
+}
+
+func (m program) Help() string {
+	return m.help.View(programKeys)
+}
+
+//------------------------------------------------------
+
+type programKeymap struct {
+	next       key.Binding
+	addWeek    key.Binding
+	addDay     key.Binding
+	name       key.Binding
+	scheme     key.Binding
+	remove     key.Binding
+	removeWeek key.Binding
+	back       key.Binding
+}
+
+func (k programKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.next, k.addWeek, k.addDay, k.name, k.scheme, k.remove, k.removeWeek, k.back}
+}
+
+func (k programKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.next, k.addWeek, k.addDay, k.name},
+		{k.scheme, k.remove, k.removeWeek, k.back},
+	}
+}
+
+var programKeys = programKeymap{
+	next: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "next up"),
+	),
+	addWeek: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "add week"),
+	),
+	addDay: key.NewBinding(
+		key.WithKeys("+"),
+		key.WithHelp("+", "add day"),
+	),
+	name: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "name week"),
+	),
+	scheme: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "scheme"),
+	),
+	remove: key.NewBinding(
+		key.WithKeys("delete"),
+		key.WithHelp("del", "remove day"),
+	),
+	removeWeek: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "remove week"),
+	),
+	back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+}
@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	wodb "github.com/zmnpl/clift/db"
+	coms "github.com/zmnpl/clift/ui/common"
+)
+
+type programSelect struct {
+	store    wodb.Store
+	settings coms.Settings
+
+	programList    list.Model
+	programName    textinput.Model
+	deleteUnlocked bool
+}
+
+func NewProgramSelectModel(store wodb.Store, settings coms.Settings) programSelect {
+	programName := textinput.New()
+	programName.Placeholder = "name of the program"
+	programName.Width = 100
+
+	return programSelect{
+		store:       store,
+		settings:    settings,
+		programList: list.New(make([]list.Item, 0), coms.ListItemStyle(), 0, 0),
+		programName: programName,
+	}
+}
+
+func (m programSelect) Init() tea.Cmd {
+	return tea.Batch(coms.LoadPrograms(m.store), textinput.Blink)
+}
+
+func (m programSelect) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case coms.MsgPrograms:
+		if msg.Err != nil {
+			return m, coms.SendStatus("", msg.Err)
+		}
+		m.refreshProgramList(msg.Programs)
+		return m, tea.WindowSize()
+
+	case coms.MsgProgramChanged:
+		return m, tea.Batch(coms.SendStatus(msg.Status, msg.Err), coms.LoadPrograms(m.store))
+
+	case tea.WindowSizeMsg:
+		m.programList.SetHeight(coms.GetContentHeight(msg.Height) - 3)
+
+	case coms.LockCriticalKey:
+		m.deleteUnlocked = false
+		return m, coms.SendStatus("", nil)
+
+	case tea.KeyMsg:
+		if m.programName.Focused() {
+			switch msg.String() {
+			case "enter":
+				name := strings.TrimSpace(m.programName.Value())
+				m.programName.SetValue("")
+				m.programName.Blur()
+				if name == "" {
+					return m, cmd
+				}
+				return m, coms.NewProgram(m.store, name)
+
+			case "esc":
+				m.programName.SetValue("")
+				m.programName.Blur()
+				return m, cmd
+			}
+
+			m.programName, cmd = m.programName.Update(msg)
+			return m, cmd
+		}
+
+		if m.programList.FilterState() == list.Filtering {
+			break
+		}
+
+		item, selected := m.programList.SelectedItem().(coms.ProgramItem)
+		switch msg.String() {
+		case "enter":
+			if selected {
+				return m, coms.GoTo(NewProgramModel(m.store, m.settings, item.ID))
+			}
+
+		case "a":
+			if selected {
+				return m, coms.ActivateProgram(m.store, *item.Program)
+			}
+
+		case "n":
+			return m, m.programName.Focus()
+
+		case "l":
+			return m, coms.GoTo(NewLibrarySelectModel(m.store, m.settings))
+
+		case "delete":
+			if !selected {
+				return m, cmd
+			}
+			if m.deleteUnlocked {
+				m.deleteUnlocked = false
+				return m, coms.RemoveProgram(m.store, item.ID)
+			}
+			m.deleteUnlocked = true
+			return m, tea.Batch(coms.SendStatus("Press delete once more to remove "+item.Name+".", nil), coms.SleepToLockKey(2000*time.Millisecond))
+
+		case "esc":
+			return m, coms.Back
+		}
+	}
+
+	m.programList, cmd = m.programList.Update(msg)
+	return m, cmd
+}
+
+func (m programSelect) View() string {
+	sb := &strings.Builder{}
+	if m.programName.Focused() {
+		sb.WriteString(m.programName.View() + "\n")
+	}
+	sb.WriteString(m.programList.View() + "\n\n")
+	sb.WriteString(m.programList.Help.View(m.programList))
+	return sb.String()
+}
+
+func (m *programSelect) refreshProgramList(programs []wodb.Program) {
+	items := make([]list.Item, len(programs))
+	for i := range programs {
+		items[i] = coms.ProgramItem{Program: &programs[i]}
+	}
+	l := list.New(items, coms.ListItemStyle(), 0, 0)
+	l.Title = "Select a Program"
+	l.SetSize(ListWidth, coms.WINDOW_HEIGHT-coms.HEADER_FOOTER_HEIGHT)
+	l.SetShowTitle(false)
+	l.SetShowHelp(false)
+	l.FilterInput.Cursor.Style = coms.FilterCursorStyle
+	l.FilterInput.PromptStyle = coms.FilterPromptStyle
+
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			programSelectKeys.enter,
+			programSelectKeys.activate,
+			programSelectKeys.add,
+			programSelectKeys.remove,
+			programSelectKeys.back,
+		}
+	}
+
+	m.programList = l
+}
+
+func (m programSelect) BreadCrumb() string {
+	return "programs"
+}
+
+func (m programSelect) Help() string {
+	return ""
+}
+
+// ---------------------------------------------------------------
+type programSelectKeymap struct {
+	enter    key.Binding
+	activate key.Binding
+	add      key.Binding
+	library  key.Binding
+	remove   key.Binding
+	back     key.Binding
+}
+
+var programSelectKeys = programSelectKeymap{
+	enter: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "open"),
+	),
+	activate: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "follow"),
+	),
+	add: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "new"),
+	),
+	library: key.NewBinding(
+		key.WithKeys("l"),
+		key.WithHelp("l", "library"),
+	),
+	remove: key.NewBinding(
+		key.WithKeys("delete"),
+		key.WithHelp("del", "remove"),
+	),
+	back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+}
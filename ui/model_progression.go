@@ -0,0 +1,251 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/progression"
+	coms "github.com/zmnpl/clift/ui/common"
+)
+
+const (
+	PROGRESSION_RULE = iota
+	PROGRESSION_INCREMENT
+	PROGRESSION_REPS
+	PROGRESSION_TRAINING_MAX
+	PROGRESSION_DELOAD_AFTER
+	PROGRESSION_DELOAD_PERCENT
+)
+
+var progressionLabels = []string{"Rule", "Increment", "Rep range", "Training max", "Deload after", "Deload %"}
+
+type progressionForm struct {
+	store    wodb.Store
+	settings coms.Settings
+	we       wodb.WorkoutExercise
+
+	inputs     []textinput.Model
+	focusIndex int
+
+	help help.Model
+}
+
+func NewProgressionForm(store wodb.Store, settings coms.Settings, we wodb.WorkoutExercise) progressionForm {
+	inputs := make([]textinput.Model, len(progressionLabels))
+	for i := range inputs {
+		inputs[i] = textinput.New()
+		inputs[i].Width = 40
+	}
+	inputs[PROGRESSION_RULE].Placeholder = strings.Join(progression.Rules, ", ") + " or empty"
+	inputs[PROGRESSION_INCREMENT].Placeholder = "2.5"
+	inputs[PROGRESSION_REPS].Placeholder = "8-12, double only"
+	inputs[PROGRESSION_TRAINING_MAX].Placeholder = "100, percent only"
+	inputs[PROGRESSION_DELOAD_AFTER].Placeholder = "failed sessions in a row, empty for never"
+	inputs[PROGRESSION_DELOAD_PERCENT].Placeholder = "10"
+
+	inputs[PROGRESSION_RULE].SetValue(we.Progression)
+	if we.Increment > 0 {
+		inputs[PROGRESSION_INCREMENT].SetValue(strconv.FormatFloat(settings.DisplayWeight(we.Increment), 'f', -1, 64))
+	}
+	if we.RepsMin > 0 {
+		inputs[PROGRESSION_REPS].SetValue(fmt.Sprintf("%v-%v", we.RepsMin, we.RepsMax))
+	}
+	if we.TrainingMax > 0 {
+		inputs[PROGRESSION_TRAINING_MAX].SetValue(strconv.FormatFloat(settings.DisplayWeight(we.TrainingMax), 'f', -1, 64))
+	}
+	if we.DeloadAfter > 0 {
+		inputs[PROGRESSION_DELOAD_AFTER].SetValue(strconv.Itoa(we.DeloadAfter))
+		inputs[PROGRESSION_DELOAD_PERCENT].SetValue(strconv.FormatFloat(we.DeloadPercent, 'f', -1, 64))
+	}
+
+	m := progressionForm{
+		store:    store,
+		settings: settings,
+		we:       we,
+		inputs:   inputs,
+		help:     help.New(),
+	}
+	m.focus(0)
+
+	return m
+}
+
+func (m progressionForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m progressionForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, coms.Back
+
+		case "enter":
+			if m.focusIndex == len(m.inputs) {
+				we, err := m.rule()
+				if err != nil {
+					return m, coms.SendStatus("", err)
+				}
+				return m, coms.Ret(coms.SaveProgression(m.store, we))
+			}
+			return m, m.focus(m.focusIndex + 1)
+
+		case "tab", "down":
+			return m, m.focus(m.focusIndex + 1)
+
+		case "shift+tab", "up":
+			return m, m.focus(m.focusIndex - 1)
+		}
+	}
+
+	if m.focusIndex < len(m.inputs) {
+		m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
+	}
+	return m, cmd
+}
+
+// rule reads the inputs into the workout exercise.
+func (m progressionForm) rule() (wodb.WorkoutExercise, error) {
+	we := m.we
+	we.Progression = strings.ToLower(strings.TrimSpace(m.inputs[PROGRESSION_RULE].Value()))
+	we.Increment, we.RepsMin, we.RepsMax, we.TrainingMax, we.DeloadAfter, we.DeloadPercent = 0, 0, 0, 0, 0, 0
+	if we.Progression == progression.NONE {
+		return we, nil
+	}
+
+	var err error
+	value := func(i int) string { return strings.TrimSpace(m.inputs[i].Value()) }
+	float := func(i int) float64 {
+		if value(i) == "" || err != nil {
+			return 0
+		}
+		var f float64
+		f, err = strconv.ParseFloat(value(i), 64)
+		if err != nil {
+			err = fmt.Errorf("%v: not a number: %v", progressionLabels[i], value(i))
+		}
+		return f
+	}
+	weight := func(i int) float64 {
+		if value(i) == "" || err != nil {
+			return 0
+		}
+		var kg float64
+		kg, _, err = m.settings.ParseWeight(value(i))
+		if err != nil {
+			err = fmt.Errorf("%v: %v", progressionLabels[i], err)
+		}
+		return kg
+	}
+
+	we.Increment = weight(PROGRESSION_INCREMENT)
+	we.TrainingMax = weight(PROGRESSION_TRAINING_MAX)
+	we.DeloadPercent = float(PROGRESSION_DELOAD_PERCENT)
+	if err != nil {
+		return we, err
+	}
+
+	if after := value(PROGRESSION_DELOAD_AFTER); after != "" {
+		we.DeloadAfter, err = strconv.Atoi(after)
+		if err != nil {
+			return we, fmt.Errorf("Deload after must be a number of sessions, got %v", after)
+		}
+	}
+
+	if reps := value(PROGRESSION_REPS); reps != "" {
+		lo, hi, _ := strings.Cut(reps, "-")
+		we.RepsMin, err = strconv.Atoi(strings.TrimSpace(lo))
+		if err == nil {
+			we.RepsMax, err = strconv.Atoi(strings.TrimSpace(hi))
+		}
+		if err != nil {
+			return we, fmt.Errorf("Rep range must look like 8-12, got %v", reps)
+		}
+	}
+
+	return we, progression.Validate(we)
+}
+
+// focus moves the focus to input i; one past the inputs is the submit button.
+func (m *progressionForm) focus(i int) tea.Cmd {
+	m.focusIndex = max(0, min(i, len(m.inputs)))
+
+	var cmd tea.Cmd
+	for j := range m.inputs {
+		if j == m.focusIndex {
+			cmd = m.inputs[j].Focus()
+			m.inputs[j].PromptStyle = coms.FocusedStyle
+			m.inputs[j].TextStyle = coms.FocusedStyle
+			continue
+		}
+		m.inputs[j].Blur()
+		m.inputs[j].PromptStyle = coms.NoStyle
+		m.inputs[j].TextStyle = coms.NoStyle
+	}
+	return cmd
+}
+
+func (m progressionForm) View() string {
+	sb := &strings.Builder{}
+
+	sb.WriteString(coms.FocusedStyle.Render(m.we.Exercise.GetName()) + "\n\n")
+	for i, in := range m.inputs {
+		sb.WriteString(fmt.Sprintf("%-13v %v\n", progressionLabels[i], in.View()))
+	}
+
+	button := blurredButton()
+	if m.focusIndex == len(m.inputs) {
+		button = focusedButton()
+	}
+	sb.WriteString(fmt.Sprintf("\n%v\n", button))
+
+	return sb.String()
+}
+
+func (m progressionForm) BreadCrumb() string {
+	return "progression"
+}
+
+func (m progressionForm) Help() string {
+	return m.help.View(progressionKeys)
+}
+
+//------------------------------------------------------
+
+type progressionKeymap struct {
+	nav     key.Binding
+	confirm key.Binding
+	back    key.Binding
+}
+
+func (k progressionKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.nav, k.confirm, k.back}
+}
+
+func (k progressionKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.nav, k.confirm, k.back}}
+}
+
+var progressionKeys = progressionKeymap{
+	nav: key.NewBinding(
+		key.WithKeys("up", "down", "tab"),
+		key.WithHelp("↑/↓/tab", "navigate"),
+	),
+	confirm: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "next / save"),
+	),
+	back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+}
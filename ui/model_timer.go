@@ -0,0 +1,511 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/interval"
+	coms "github.com/zmnpl/clift/ui/common"
+)
+
+const (
+	TIMER_SETUP = iota
+	TIMER_RUNNING
+	TIMER_DONE
+)
+
+const (
+	TIMER_KIND = iota
+	TIMER_SEQUENCE
+	TIMER_ROUNDS
+	TIMER_REPS
+	TIMER_WEIGHT
+)
+
+var timerLabels = []string{"Timer", "Sequence", "Rounds", "Reps/round", "Weight"}
+
+const TIMER_BAR_WIDTH = 50
+
+// MsgTimerTick is the beat of the interval timer with the given ID.
+type MsgTimerTick int
+
+type timer struct {
+	store    wodb.Store
+	settings coms.Settings
+	datum    time.Time
+
+	// the rounds go back to the session of the workout, if there is one, and
+	// are logged on their own otherwise
+	workout    *wodb.Workout
+	we         *wodb.WorkoutExercise
+	exerciseID string
+
+	inputs     []textinput.Model
+	focusIndex int
+
+	state     int
+	kind      string
+	title     string
+	phases    []interval.Phase
+	startedAt time.Time
+	elapsed   time.Duration // up to resumedAt
+	resumedAt time.Time     // zero while paused
+	tickID    int
+	phase     int // phase of the last tick
+	adjust    int // rounds added or taken by hand
+
+	escapeUnlocked bool
+
+	help help.Model
+}
+
+func NewTimerModel(store wodb.Store, settings coms.Settings, datum time.Time) timer {
+	if datum.IsZero() {
+		datum = time.Now()
+	}
+
+	inputs := make([]textinput.Model, len(timerLabels))
+	for i := range inputs {
+		inputs[i] = textinput.New()
+		inputs[i].Width = 40
+	}
+	inputs[TIMER_KIND].Placeholder = strings.Join(interval.Kinds, ", ")
+	inputs[TIMER_KIND].SetValue(interval.EMOM)
+	inputs[TIMER_SEQUENCE].Placeholder = "work/rest, e.g. 1:00, 20/10 or 40/20x3 90"
+	inputs[TIMER_ROUNDS].Placeholder = "repeats of the sequence"
+	inputs[TIMER_REPS].Placeholder = "1"
+	inputs[TIMER_WEIGHT].Placeholder = "0"
+
+	m := timer{
+		store:    store,
+		settings: settings,
+		datum:    datum,
+		inputs:   inputs,
+		help:     help.New(),
+	}
+	m.focus(0)
+
+	return m
+}
+
+// NewWorkoutTimerModel times a workout exercise; the rounds become its sets
+// in the running session.
+func NewWorkoutTimerModel(store wodb.Store, settings coms.Settings, datum time.Time, workout *wodb.Workout, we *wodb.WorkoutExercise) timer {
+	m := NewTimerModel(store, settings, datum)
+	m.workout = workout
+	m.we = we
+	m.exerciseID = we.ExerciseID
+	if len(we.Sets) > 0 {
+		m.inputs[TIMER_REPS].Placeholder = strconv.Itoa(we.Sets[0].Reps)
+		m.inputs[TIMER_WEIGHT].Placeholder = settings.FormatWeight(we.Sets[0].Weight)
+	}
+	return m
+}
+
+func (m timer) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m timer) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case coms.LockCriticalKey:
+		m.escapeUnlocked = false
+		return m, coms.SendStatus("", nil)
+
+	case coms.MsgDate:
+		m.datum = time.Time(msg)
+		return m, cmd
+
+	case coms.MsgExerciseID:
+		m.exerciseID = string(msg)
+		return m, cmd
+
+	case coms.MsgExerciseLogged:
+		if msg.Err != nil {
+			return m, coms.SendStatus("", msg.Err)
+		}
+		return m, tea.Batch(coms.Back, coms.SendStatus(strings.TrimSpace("Logged "+m.title+" "+msg.Status), nil))
+
+	case MsgTimerTick:
+		if int(msg) != m.tickID || m.state != TIMER_RUNNING || m.resumedAt.IsZero() {
+			return m, cmd
+		}
+		phase, _, _ := interval.At(m.phases, m.running())
+		if phase >= len(m.phases) {
+			m.stop()
+			m.state = TIMER_DONE
+			return m, tea.Batch(coms.Bell, coms.SendStatus("Done! Correct the rounds with +/- and log them with enter", nil))
+		}
+		cmd = m.tick()
+		if phase != m.phase {
+			m.phase = phase
+			cmd = tea.Batch(coms.Bell, cmd)
+		}
+		return m, cmd
+
+	case tea.KeyMsg:
+		switch m.state {
+		case TIMER_SETUP:
+			return m.updateSetup(msg)
+		case TIMER_RUNNING, TIMER_DONE:
+			return m.updateRunning(msg)
+		}
+	}
+
+	return m, cmd
+}
+
+func (m timer) updateSetup(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "esc":
+		return m, coms.Back
+
+	case "f3":
+		if m.we == nil {
+			return m, coms.GoTo(NewSelectExercise(m.store, m.settings, 0, m.datum))
+		}
+
+	case "enter":
+		if m.focusIndex == len(m.inputs) {
+			cmd = m.start()
+			return m, cmd
+		}
+		return m, m.focus(m.focusIndex + 1)
+
+	case "tab", "down":
+		return m, m.focus(m.focusIndex + 1)
+
+	case "shift+tab", "up":
+		return m, m.focus(m.focusIndex - 1)
+	}
+
+	if m.focusIndex < len(m.inputs) {
+		m.inputs[m.focusIndex], cmd = m.inputs[m.focusIndex].Update(msg)
+	}
+	return m, cmd
+}
+
+func (m timer) updateRunning(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case " ":
+		if m.state != TIMER_RUNNING {
+			return m, cmd
+		}
+		if m.resumedAt.IsZero() {
+			m.resumedAt = time.Now()
+			cmd = m.tick()
+			return m, cmd
+		}
+		m.stop()
+		return m, cmd
+
+	case "+":
+		m.adjust++
+		return m, cmd
+
+	case "-":
+		if m.rounds() > 0 {
+			m.adjust--
+		}
+		return m, cmd
+
+	case "f1":
+		m.stop()
+		m.state = TIMER_DONE
+		return m, coms.SendStatus("Stopped, log the rounds with enter", nil)
+
+	case "enter":
+		if m.state == TIMER_DONE {
+			return m, m.log()
+		}
+
+	case "f3":
+		if m.state == TIMER_DONE && m.we == nil {
+			return m, coms.GoTo(NewSelectExercise(m.store, m.settings, 0, m.datum))
+		}
+
+	case "esc":
+		if m.escapeUnlocked {
+			return m, tea.Batch(coms.Back, coms.SendStatus("", nil))
+		}
+		m.escapeUnlocked = true
+		return m, tea.Batch(coms.SendStatus("Press esc once more to leave without logging", nil), coms.SleepToLockKey(2000*time.Millisecond))
+	}
+
+	return m, cmd
+}
+
+// start reads the setup and runs the timer.
+func (m *timer) start() tea.Cmd {
+	value := func(i int) string { return strings.TrimSpace(m.inputs[i].Value()) }
+
+	rounds := 0
+	if r := value(TIMER_ROUNDS); r != "" {
+		var err error
+		rounds, err = strconv.Atoi(r)
+		if err != nil || rounds < 1 {
+			return coms.SendStatus("", fmt.Errorf("Rounds must be a positive number, got %v", r))
+		}
+	}
+	if _, err := m.perRound(); err != nil {
+		return coms.SendStatus("", err)
+	}
+
+	m.kind = strings.ToLower(value(TIMER_KIND))
+	phases, err := interval.Plan(m.kind, value(TIMER_SEQUENCE), rounds)
+	if err != nil {
+		return coms.SendStatus("", err)
+	}
+
+	m.phases = phases
+	m.title = interval.Describe(m.kind, value(TIMER_SEQUENCE), rounds)
+	m.state = TIMER_RUNNING
+	m.startedAt = time.Now()
+	m.resumedAt = m.startedAt
+	for i := range m.inputs {
+		m.inputs[i].Blur()
+	}
+	tick := m.tick()
+	return tea.Batch(coms.Bell, tick, coms.SendStatus("Go! space pauses", nil))
+}
+
+// stop pauses the clock.
+func (m *timer) stop() {
+	if !m.resumedAt.IsZero() {
+		m.elapsed += time.Since(m.resumedAt)
+		m.resumedAt = time.Time{}
+	}
+}
+
+func (m *timer) tick() tea.Cmd {
+	m.tickID++
+	id := m.tickID
+	return tea.Tick(250*time.Millisecond, func(time.Time) tea.Msg {
+		return MsgTimerTick(id)
+	})
+}
+
+// running is the time the clock ran.
+func (m timer) running() time.Duration {
+	if m.resumedAt.IsZero() {
+		return m.elapsed
+	}
+	return m.elapsed + time.Since(m.resumedAt)
+}
+
+// rounds are the completed rounds: finished work phases, except for an AMRAP
+// whose rounds are counted by hand, plus the corrections.
+func (m timer) rounds() int {
+	n := m.adjust
+	if m.kind != interval.AMRAP {
+		_, _, worked := interval.At(m.phases, m.running())
+		n += worked
+	}
+	return max(n, 0)
+}
+
+// perRound reads reps and weight of a round.
+func (m timer) perRound() (coms.SetInput, error) {
+	reps, weight := m.inputs[TIMER_REPS].Value(), m.inputs[TIMER_WEIGHT].Value()
+	if reps == "" {
+		reps = m.inputs[TIMER_REPS].Placeholder
+	}
+	if weight == "" {
+		weight = m.inputs[TIMER_WEIGHT].Placeholder
+	}
+
+	r, err := strconv.Atoi(strings.TrimSpace(reps))
+	if err != nil || r < 1 {
+		return coms.SetInput{}, fmt.Errorf("Reps/round must be a positive number, got %v", reps)
+	}
+	w, _, err := m.settings.ParseWeight(weight)
+	if err != nil {
+		return coms.SetInput{}, fmt.Errorf("Weight must be a number, got %v", weight)
+	}
+
+	var wid uint
+	if m.workout != nil {
+		wid = m.workout.ID
+	}
+	return m.settings.CreateSetTemplate(1, r, w, wid, m.exerciseID), nil
+}
+
+// log turns every round into a set.
+func (m timer) log() tea.Cmd {
+	if m.exerciseID == "" {
+		return coms.SendStatus("Pick the exercise the rounds are logged as (f3)", nil)
+	}
+	round, err := m.perRound()
+	if err != nil {
+		return coms.SendStatus("", err)
+	}
+	round.ExerciseId = m.exerciseID
+
+	sets := make([]coms.SetInput, m.rounds())
+	for i := range sets {
+		sets[i] = round
+		sets[i].SetNo = i + 1
+		sets[i].PlaceholderToValue()
+	}
+
+	if m.we != nil {
+		return coms.Ret(coms.SendPerformedSets(sets, m.we.ID))
+	}
+	if len(sets) == 0 {
+		return coms.SendStatus("No rounds to log", nil)
+	}
+	session := wodb.Session{StartedAt: m.startedAt, Notes: m.title}
+	return coms.LogSingleExercise(m.store, m.settings, session, m.datum, sets)
+}
+
+// focus moves the focus to input i; one past the inputs is the start button.
+func (m *timer) focus(i int) tea.Cmd {
+	m.focusIndex = max(0, min(i, len(m.inputs)))
+
+	var cmd tea.Cmd
+	for j := range m.inputs {
+		if j == m.focusIndex {
+			cmd = m.inputs[j].Focus()
+			m.inputs[j].PromptStyle = coms.FocusedStyle
+			m.inputs[j].TextStyle = coms.FocusedStyle
+			continue
+		}
+		m.inputs[j].Blur()
+		m.inputs[j].PromptStyle = coms.NoStyle
+		m.inputs[j].TextStyle = coms.NoStyle
+	}
+	return cmd
+}
+
+func (m timer) View() string {
+	sb := &strings.Builder{}
+
+	exercise := "no exercise, f3 picks one"
+	if m.exerciseID != "" {
+		exercise = liftName(m.exerciseID)
+	}
+
+	if m.state == TIMER_SETUP {
+		sb.WriteString(coms.FocusedStyle.Render("Interval timer") + coms.BlurredStyle.Render(" · "+exercise) + "\n\n")
+		for i, in := range m.inputs {
+			sb.WriteString(fmt.Sprintf("%-11v %v\n", timerLabels[i], in.View()))
+		}
+
+		button := blurredButton()
+		if m.focusIndex == len(m.inputs) {
+			button = focusedButton()
+		}
+		sb.WriteString(fmt.Sprintf("\n%v\n", button))
+		return sb.String()
+	}
+
+	sb.WriteString(coms.FocusedStyle.Render(m.title) + coms.BlurredStyle.Render(" · "+exercise) + "\n\n")
+
+	running := m.running()
+	total := interval.Total(m.phases)
+	phase, left, worked := interval.At(m.phases, running)
+
+	switch {
+	case m.state == TIMER_DONE:
+		sb.WriteString(coms.FocusedStyle.Render("DONE") + "\n")
+	case m.phases[phase].Work:
+		sb.WriteString(coms.FocusedStyle.Render("WORK "+interval.Format(left)) + "\n")
+	default:
+		sb.WriteString(coms.BlurredStyle.Render("REST "+interval.Format(left)) + "\n")
+	}
+	if m.state == TIMER_RUNNING && m.resumedAt.IsZero() {
+		sb.WriteString(coms.BlurredStyle.Render("paused") + "\n")
+	} else {
+		sb.WriteString("\n")
+	}
+
+	done := TIMER_BAR_WIDTH
+	if total > 0 {
+		done = min(int(float64(TIMER_BAR_WIDTH)*float64(running)/float64(total)), TIMER_BAR_WIDTH)
+	}
+	sb.WriteString(coms.FocusedStyle.Render(strings.Repeat("█", done)) + coms.BlurredStyle.Render(strings.Repeat("░", TIMER_BAR_WIDTH-done)))
+	sb.WriteString(fmt.Sprintf(" %v / %v\n\n", interval.Format(min(running, total)), interval.Format(total)))
+
+	if m.kind != interval.AMRAP && m.state == TIMER_RUNNING {
+		sb.WriteString(fmt.Sprintf("Round %v of %v\n", min(worked+1, interval.Rounds(m.phases)), interval.Rounds(m.phases)))
+	}
+	sb.WriteString(fmt.Sprintf("Rounds done: %v\n", m.rounds()))
+
+	return sb.String()
+}
+
+func (m timer) BreadCrumb() string {
+	return "timer"
+}
+
+func (m timer) Help() string {
+	k := timerKeys
+	switch m.state {
+	case TIMER_SETUP:
+		return m.help.ShortHelpView([]key.Binding{k.nav, k.start, k.exercise, k.back})
+	case TIMER_RUNNING:
+		return m.help.ShortHelpView([]key.Binding{k.pause, k.rounds, k.finish, k.back})
+	}
+	return m.help.ShortHelpView([]key.Binding{k.rounds, k.log, k.exercise, k.back})
+}
+
+//------------------------------------------------------
+
+type timerKeymap struct {
+	nav      key.Binding
+	start    key.Binding
+	exercise key.Binding
+	pause    key.Binding
+	rounds   key.Binding
+	finish   key.Binding
+	log      key.Binding
+	back     key.Binding
+}
+
+var timerKeys = timerKeymap{
+	nav: key.NewBinding(
+		key.WithKeys("up", "down", "tab"),
+		key.WithHelp("↑/↓/tab", "navigate"),
+	),
+	start: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "next / start"),
+	),
+	exercise: key.NewBinding(
+		key.WithKeys("f3"),
+		key.WithHelp("f3", "exercise"),
+	),
+	pause: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "pause"),
+	),
+	rounds: key.NewBinding(
+		key.WithKeys("+", "-"),
+		key.WithHelp("+/-", "rounds"),
+	),
+	finish: key.NewBinding(
+		key.WithKeys("f1"),
+		key.WithHelp("f1", "finish"),
+	),
+	log: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "log"),
+	),
+	back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+}
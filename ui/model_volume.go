@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/zmnpl/clift/analytics"
+	wodb "github.com/zmnpl/clift/db"
+	coms "github.com/zmnpl/clift/ui/common"
+)
+
+// weeks the volume report can look back, 0 is everything
+var volumeWeeks = []int{4, 8, 12, 26, 0}
+
+type volume struct {
+	store    wodb.Store
+	settings coms.Settings
+
+	weeks  int // index into volumeWeeks
+	report table.Model
+
+	help help.Model
+}
+
+func NewVolumeModel(store wodb.Store, settings coms.Settings) volume {
+	return volume{
+		store:    store,
+		settings: settings,
+		weeks:    1,
+		report: coms.MakeTable([]table.Column{
+			{Title: "Week", Width: 10},
+			{Title: "Muscle", Width: 16},
+			{Title: "Sets", Width: 6},
+			{Title: "Tonnage", Width: 10},
+		}),
+		help: help.New(),
+	}
+}
+
+func (m volume) Init() tea.Cmd {
+	return coms.LoadMuscleVolume(m.store, volumeWeeks[m.weeks])
+}
+
+func (m volume) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.report.SetHeight(coms.GetContentHeight(msg.Height) - 2)
+
+	case coms.MsgMuscleVolume:
+		if msg.Err != nil {
+			return m, coms.SendStatus("", msg.Err)
+		}
+		m.refreshReport(msg.Volumes)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, coms.Back
+
+		case "w":
+			m.weeks = (m.weeks + 1) % len(volumeWeeks)
+			return m, coms.LoadMuscleVolume(m.store, volumeWeeks[m.weeks])
+		}
+	}
+
+	m.report, cmd = m.report.Update(msg)
+	return m, cmd
+}
+
+func (m *volume) refreshReport(volumes []analytics.MuscleVolume) {
+	rows := make([]table.Row, 0, len(volumes))
+	for i, v := range volumes {
+		week := v.Week
+		if i > 0 && volumes[i-1].Week == v.Week {
+			week = ""
+		}
+		rows = append(rows, table.Row{
+			week,
+			v.Muscle,
+			strconv.FormatFloat(v.Sets, 'f', -1, 64),
+			fmt.Sprintf("%.0f %v", m.settings.DisplayWeight(v.Tonnage), m.settings.Units),
+		})
+	}
+	m.report.SetRows(rows)
+	m.report.GotoTop()
+}
+
+func (m volume) View() string {
+	sb := &strings.Builder{}
+
+	window := "all weeks"
+	if n := volumeWeeks[m.weeks]; n > 0 {
+		window = fmt.Sprintf("last %v weeks", n)
+	}
+	sb.WriteString(coms.FocusedStyle.Render("Volume per muscle: ") + window +
+		fmt.Sprintf(", secondary muscles count %v\n", analytics.SECONDARY_SHARE))
+	sb.WriteString(m.report.View() + "\n")
+
+	return sb.String()
+}
+
+func (m volume) BreadCrumb() string {
+	return "volume"
+}
+
+func (m volume) Help() string {
+	return m.help.View(volumeKeys)
+}
+
+//------------------------------------------------------
+
+type volumeKeymap struct {
+	window key.Binding
+	back   key.Binding
+}
+
+func (k volumeKeymap) ShortHelp() []key.Binding {
+	return []key.Binding{k.window, k.back}
+}
+
+func (k volumeKeymap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{{k.window, k.back}}
+}
+
+var volumeKeys = volumeKeymap{
+	window: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "weeks"),
+	),
+	back: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "back"),
+	),
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	wodb "github.com/zmnpl/clift/db"
+	"github.com/zmnpl/clift/progression"
 	coms "github.com/zmnpl/clift/ui/common"
 )
 
@@ -18,6 +20,9 @@ const (
 )
 
 type workout struct {
+	store    wodb.Store
+	settings coms.Settings
+
 	workoutID uint
 	workout   wodb.Workout
 
@@ -25,6 +30,20 @@ type workout struct {
 	exerciseList list.Model
 
 	sessionSets map[uint][]coms.SetInput
+	proposals   map[uint]progression.Proposal // by workout exercise, MODE_DO only
+	loads       map[uint]progression.Proposal // suggested for target RPEs, not saved
+
+	// program day the workout is done for, if any
+	programDay *wodb.ProgramDay
+	scheme     []progression.SchemeSet
+	dayTitle   string
+
+	// session details, MODE_DO only
+	startedAt  time.Time
+	notes      textinput.Model
+	bodyweight textinput.Model
+
+	rest textinput.Model // rest of the selected exercise, MODE_EDIT only
 
 	mode           int
 	escapeUnlocked bool
@@ -33,7 +52,7 @@ type workout struct {
 	status string
 }
 
-func NewWorkoutModel(workoutID uint, datum time.Time) workout {
+func NewWorkoutModel(store wodb.Store, settings coms.Settings, workoutID uint, datum time.Time) workout {
 	if datum.IsZero() {
 		datum = time.Now()
 	}
@@ -41,16 +60,44 @@ func NewWorkoutModel(workoutID uint, datum time.Time) workout {
 	items := make([]list.Item, 0)
 	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
 
+	notes := textinput.New()
+	notes.Placeholder = "how did it go?"
+	notes.Width = 50
+
+	bodyweight := textinput.New()
+	bodyweight.Placeholder = "-"
+	bodyweight.CharLimit = 8
+	bodyweight.Width = 8
+
 	return workout{
+		store:        store,
+		settings:     settings,
 		datum:        datum,
 		workoutID:    workoutID,
 		exerciseList: l,
 		sessionSets:  make(map[uint][]coms.SetInput),
+		startedAt:    time.Now(),
+		notes:        notes,
+		bodyweight:   bodyweight,
 		mode:         MODE_DO,
 	}
 }
 
-func NewWorkoutModelEDIT(workoutID uint, datum time.Time) workout {
+// NewProgramWorkoutModel starts the workout scheduled next by program. The
+// week's scheme sets the targets of exercises with a training max.
+func NewProgramWorkoutModel(store wodb.Store, settings coms.Settings, program wodb.Program, datum time.Time) workout {
+	w, d := program.Position()
+	week := program.Weeks[w]
+	day := week.Days[d]
+
+	m := NewWorkoutModel(store, settings, day.WorkoutID, datum)
+	m.programDay = &day
+	m.scheme, _ = progression.ParseScheme(week.Scheme)
+	m.dayTitle = fmt.Sprintf("%v W%vD%v", program.Name, w+1, d+1)
+	return m
+}
+
+func NewWorkoutModelEDIT(store wodb.Store, settings coms.Settings, workoutID uint, datum time.Time) workout {
 	if datum.IsZero() {
 		datum = time.Now()
 	}
@@ -58,17 +105,25 @@ func NewWorkoutModelEDIT(workoutID uint, datum time.Time) workout {
 	items := make([]list.Item, 0)
 	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
 
+	rest := textinput.New()
+	rest.Placeholder = fmt.Sprintf("seconds or m:ss, empty for the default of %vs", settings.DefaultRest)
+	rest.CharLimit = 6
+	rest.Width = 50
+
 	return workout{
+		store:        store,
+		settings:     settings,
 		datum:        datum,
 		workoutID:    workoutID,
 		exerciseList: l,
 		sessionSets:  make(map[uint][]coms.SetInput),
+		rest:         rest,
 		mode:         MODE_EDIT,
 	}
 }
 
 func (m workout) Init() tea.Cmd {
-	return tea.Batch(textinput.Blink, coms.ReloadWorkoutSingle(m.workoutID))
+	return tea.Batch(textinput.Blink, coms.ReloadWorkoutSingle(m.store, m.workoutID))
 }
 
 func (m workout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -76,7 +131,11 @@ func (m workout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case tea.WindowSizeMsg:
-		m.exerciseList.SetHeight(coms.GetContentHeight(msg.Height) - 3)
+		height := coms.GetContentHeight(msg.Height) - 3
+		if m.mode == MODE_DO {
+			height-- // session line
+		}
+		m.exerciseList.SetHeight(height)
 
 	case coms.LockCriticalKey:
 		m.deleteUnlocked = false
@@ -84,7 +143,7 @@ func (m workout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, coms.SendStatus("", nil)
 
 	case coms.MsgExerciseID:
-		return m, coms.AddWorkoutExercise(m.workoutID, string(msg))
+		return m, coms.AddWorkoutExercise(m.store, m.workoutID, string(msg))
 
 	case coms.MsgDate:
 		m.datum = time.Time(msg)
@@ -97,7 +156,7 @@ func (m workout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Batch(cmd, tea.WindowSize())
 		}
 		if m.mode == MODE_EDIT {
-			return m, tea.Batch(coms.UpdateWorkoutExerciseSets(msg.Weid, msg.Sets), tea.WindowSize())
+			return m, tea.Batch(coms.UpdateWorkoutExerciseSets(m.store, msg.Weid, msg.Sets), tea.WindowSize())
 		}
 
 	case coms.MsgUpdatedWorkoutExercise:
@@ -105,10 +164,10 @@ func (m workout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.status = msg.Err.Error()
 		}
 
-		return m, coms.ReloadWorkoutSingle(m.workout.ID)
+		return m, coms.ReloadWorkoutSingle(m.store, m.workout.ID)
 
 	case coms.MsgExerciseAddedToWorkout:
-		return m, coms.ReloadWorkoutSingle(m.workout.ID)
+		return m, coms.ReloadWorkoutSingle(m.store, m.workout.ID)
 
 	case coms.MsgWorkoutSingleReload:
 		if msg.Err != nil {
@@ -116,15 +175,42 @@ func (m workout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.workout = msg.Workout
 			m.refreshWEList()
+			if m.mode == MODE_DO && m.proposals == nil {
+				return m, tea.Batch(coms.ProposeProgressions(m.store, m.settings, m.workout), tea.WindowSize())
+			}
 			return m, tea.Batch(cmd, tea.WindowSize())
 		}
 
+	case coms.MsgProgressions:
+		if msg.Err != nil {
+			return m, coms.SendStatus("", msg.Err)
+		}
+		m.proposals = msg.Proposals
+		m.loads = msg.Loads
+		m.refreshWEList()
+		if len(m.proposals) > 0 {
+			return m, tea.Batch(coms.SendStatus(fmt.Sprintf("%v exercises progressed, targets are saved on submit", len(m.proposals)), nil), tea.WindowSize())
+		}
+		return m, cmd
+
 	case tea.KeyMsg:
+		if m.notes.Focused() || m.bodyweight.Focused() {
+			return m.updateSessionInputs(msg)
+		}
+		if m.rest.Focused() {
+			return m.updateRest(msg)
+		}
+
 		if m.exerciseList.FilterState() == list.Filtering {
 			break
 		}
 
 		switch msg.String() {
+		case "f3":
+			if m.mode == MODE_DO {
+				return m, m.focusSessionInput(&m.notes)
+			}
+
 		case "enter":
 			if m.mode == MODE_DO && len(m.exerciseList.SelectedItem().(coms.WeItem).SetInputs) < 1 {
 				m.status = "No sets defined"
@@ -132,10 +218,37 @@ func (m workout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			weitem := m.exerciseList.SelectedItem().(coms.WeItem)
-			return m, coms.GoTo(NewWorkoutExerciseEntryModel(m.datum, &m.workout, weitem.WorkoutExercise, &weitem.WorkoutExercise.Exercise, weitem.SetInputs, MODE_RETURN_SETS))
+			entry := NewWorkoutExerciseEntryModel(m.store, m.settings, m.datum, &m.workout, weitem.WorkoutExercise, &weitem.WorkoutExercise.Exercise, weitem.SetInputs, MODE_RETURN_SETS)
+			if m.mode == MODE_DO {
+				entry.rest = m.settings.RestFor(*weitem.WorkoutExercise)
+			}
+			return m, coms.GoTo(entry)
 
 		case "+":
-			return m, coms.GoTo(NewSelectExercise(m.workout.ID, m.datum))
+			return m, coms.GoTo(NewSelectExercise(m.store, m.settings, m.workout.ID, m.datum))
+
+		case "t":
+			if m.mode == MODE_DO && m.exerciseList.SelectedItem() != nil {
+				weitem := m.exerciseList.SelectedItem().(coms.WeItem)
+				return m, coms.GoTo(NewWorkoutTimerModel(m.store, m.settings, m.datum, &m.workout, weitem.WorkoutExercise))
+			}
+
+		case "r":
+			if m.mode == MODE_EDIT && m.exerciseList.SelectedItem() != nil {
+				we := m.exerciseList.SelectedItem().(coms.WeItem).WorkoutExercise
+				m.rest.SetValue("")
+				if we.Rest > 0 {
+					m.rest.SetValue(coms.FormatRest(time.Duration(we.Rest) * time.Second))
+				}
+				m.rest.CursorEnd()
+				return m, m.rest.Focus()
+			}
+
+		case "f4":
+			if m.mode == MODE_EDIT && m.exerciseList.SelectedItem() != nil {
+				weitem := m.exerciseList.SelectedItem().(coms.WeItem)
+				return m, coms.GoTo(NewProgressionForm(m.store, m.settings, *weitem.WorkoutExercise))
+			}
 
 		case "f1":
 			if m.mode == MODE_DO {
@@ -143,7 +256,17 @@ func (m workout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				for i, v := range m.exerciseList.Items() {
 					weItems[i] = v.(coms.WeItem)
 				}
-				return m, coms.LogWorkout(weItems, m.datum)
+				bodyweight, _, _ := m.settings.ParseWeight(m.bodyweight.Value())
+				session := wodb.Session{
+					WorkoutID:  m.workout.ID,
+					StartedAt:  m.startedAt,
+					Notes:      m.notes.Value(),
+					Bodyweight: bodyweight,
+				}
+				if m.programDay != nil {
+					session.ProgramDayID = m.programDay.ID
+				}
+				return m, coms.LogWorkout(m.store, m.settings, session, weItems, m.datum)
 			}
 			if m.mode == MODE_EDIT {
 
@@ -151,7 +274,7 @@ func (m workout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "delete":
 			if m.deleteUnlocked {
-				return m, coms.RemoveWorkoutExercise(m.exerciseList.SelectedItem().(coms.WeItem).WorkoutExercise.ID)
+				return m, coms.RemoveWorkoutExercise(m.store, m.exerciseList.SelectedItem().(coms.WeItem).WorkoutExercise.ID)
 			}
 			m.deleteUnlocked = true
 			return m, tea.Batch(coms.SendStatus("Yo! Press delete one more time and that workout is gone.", nil), coms.SleepToLockKey(2000*time.Millisecond))
@@ -169,9 +292,79 @@ func (m workout) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// updateSessionInputs handles keys while notes or bodyweight are edited.
+func (m workout) updateSessionInputs(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "tab", "shift+tab":
+		if m.notes.Focused() {
+			return m, m.focusSessionInput(&m.bodyweight)
+		}
+		return m, m.focusSessionInput(&m.notes)
+
+	case "enter", "esc":
+		m.focusSessionInput(nil)
+		return m, cmd
+	}
+
+	if m.notes.Focused() {
+		m.notes, cmd = m.notes.Update(msg)
+	} else {
+		m.bodyweight, cmd = m.bodyweight.Update(msg)
+	}
+	return m, cmd
+}
+
+// updateRest handles keys while the rest of the selected exercise is edited.
+func (m workout) updateRest(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.String() {
+	case "enter":
+		seconds, err := coms.ParseRest(m.rest.Value())
+		if err != nil {
+			return m, coms.SendStatus("", err)
+		}
+		m.rest.Blur()
+		we := m.exerciseList.SelectedItem().(coms.WeItem).WorkoutExercise
+		return m, coms.SaveRest(m.store, we.ID, seconds)
+
+	case "esc":
+		m.rest.Blur()
+		return m, cmd
+	}
+
+	m.rest, cmd = m.rest.Update(msg)
+	return m, cmd
+}
+
+// focusSessionInput focuses in, one of notes or bodyweight. nil blurs both.
+func (m *workout) focusSessionInput(in *textinput.Model) tea.Cmd {
+	var cmd tea.Cmd
+	for _, i := range []*textinput.Model{&m.notes, &m.bodyweight} {
+		if i == in {
+			cmd = i.Focus()
+			i.PromptStyle = coms.FocusedStyle
+			i.TextStyle = coms.FocusedStyle
+			continue
+		}
+		i.Blur()
+		i.PromptStyle = coms.NoStyle
+		i.TextStyle = coms.NoStyle
+	}
+	return cmd
+}
+
 func (m workout) View() string {
 	sb := &strings.Builder{}
 	sb.WriteString(coms.FocusedStyle.Render("Date: ") + m.datum.Format("2006-01-02") + "\n")
+	if m.mode == MODE_DO {
+		sb.WriteString(coms.FocusedStyle.Render("Notes: ") + m.notes.View() + coms.FocusedStyle.Render(" Bodyweight: ") + m.bodyweight.View() + "\n")
+	}
+	if m.rest.Focused() {
+		sb.WriteString(coms.FocusedStyle.Render("Rest: ") + m.rest.View() + "\n")
+	}
 	sb.WriteString(m.exerciseList.View() + "\n\n")
 	sb.WriteString(m.exerciseList.Help.View(m.exerciseList))
 	return sb.String()
@@ -186,25 +379,53 @@ func (m *workout) refreshWEList() {
 
 	// workout list
 	maxSets := 1
+	notes := false
 	items := make([]list.Item, len(wes))
 	for i := range wes {
-		templates := coms.CreateSetTemplatesForWE(wes[i])
+		item := coms.WeItem{WorkoutExercise: &wes[i]}
+
+		if m.mode == MODE_EDIT {
+			item.Note = progression.Describe(wes[i], m.settings.Units)
+			if wes[i].Rest > 0 {
+				item.Note = strings.TrimPrefix(item.Note+", rest "+coms.FormatRest(time.Duration(wes[i].Rest)*time.Second), ", ")
+			}
+		}
+
+		we := wes[i]
+		if p, ok := m.proposals[we.ID]; ok {
+			we.Sets = p.Sets
+			we.TrainingMax = p.TrainingMax
+			item.Proposal = &p
+			item.Note = "↗ " + p.Reason
+		}
+		if l, ok := m.loads[we.ID]; ok {
+			we.Sets = l.Sets
+			item.Note = strings.TrimPrefix(item.Note+", "+l.Reason, ", ")
+		}
+		if len(m.scheme) > 0 && we.TrainingMax > 0 {
+			we.Sets = progression.ApplyScheme(m.scheme, we.TrainingMax, m.settings.Units)
+			m.settings.RoundTargetsToPlates(we.Exercise, we.Sets, nil)
+		}
+		item.SetInputs = m.settings.CreateSetTemplatesForWE(we)
 
 		// overwrite with user entered sessoin sets
 		sessionTemplates, ok := m.sessionSets[wes[i].ID]
 		if ok {
-			templates = sessionTemplates
+			item.SetInputs = sessionTemplates
 		}
 
-		items[i] = coms.WeItem{
-			&wes[i],
-			templates,
+		items[i] = item
+		if len(item.SetInputs) > maxSets {
+			maxSets = len(item.SetInputs)
 		}
-		if len(templates) > maxSets {
-			maxSets = len(templates)
+		if item.Note != "" {
+			notes = true
 		}
 	}
 	maxSets = maxSets + 1
+	if notes {
+		maxSets++
+	}
 
 	d := coms.ListItemStyle()
 	d.SetHeight(maxSets)
@@ -220,14 +441,18 @@ func (m *workout) refreshWEList() {
 	l.FilterInput.PromptStyle = coms.FilterPromptStyle
 
 	// TODO keys
+	modeKeys := []key.Binding{workoutKeys.sessionNotes, workoutKeys.timer}
+	if m.mode == MODE_EDIT {
+		modeKeys = []key.Binding{workoutKeys.progression, workoutKeys.rest}
+	}
 	l.AdditionalShortHelpKeys = func() []key.Binding {
-		return []key.Binding{
+		keys := []key.Binding{
 			workoutKeys.submit,
 			workoutKeys.enter,
 			workoutKeys.addExercise,
-			workoutKeys.changedate,
-			workoutKeys.back,
 		}
+		keys = append(keys, modeKeys...)
+		return append(keys, workoutKeys.changedate, workoutKeys.back)
 	}
 
 	m.exerciseList = l
@@ -238,6 +463,9 @@ func (m workout) BreadCrumb() string {
 	if m.mode == MODE_EDIT {
 		edit = " (edit)"
 	}
+	if m.dayTitle != "" {
+		edit = " (" + m.dayTitle + ")"
+	}
 	return m.workout.Name + edit
 }
 
@@ -256,11 +484,15 @@ func (m workout) summary() string {
 
 // ------------------------------------------------------------------------------
 type workoutKeymap struct {
-	enter       key.Binding
-	addExercise key.Binding
-	submit      key.Binding
-	changedate  key.Binding
-	back        key.Binding
+	enter        key.Binding
+	addExercise  key.Binding
+	sessionNotes key.Binding
+	progression  key.Binding
+	rest         key.Binding
+	timer        key.Binding
+	submit       key.Binding
+	changedate   key.Binding
+	back         key.Binding
 }
 
 var workoutKeys = workoutKeymap{
@@ -272,6 +504,22 @@ var workoutKeys = workoutKeymap{
 		key.WithKeys("+"),
 		key.WithHelp("+", "add exercise"),
 	),
+	sessionNotes: key.NewBinding(
+		key.WithKeys("f3"),
+		key.WithHelp("f3", "notes"),
+	),
+	progression: key.NewBinding(
+		key.WithKeys("f4"),
+		key.WithHelp("f4", "progression"),
+	),
+	rest: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "rest"),
+	),
+	timer: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "timer"),
+	),
 	submit: key.NewBinding(
 		key.WithKeys("f1"),
 		key.WithHelp("f1", "submit"),
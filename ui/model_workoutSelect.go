@@ -14,26 +14,40 @@ import (
 )
 
 type workoutSelect struct {
+	store    wodb.Store
+	settings coms.Settings
+
 	workoutList list.Model
 	workoutMD   string
 	workoutName textinput.Model
 	datum       time.Time
+	pick        bool // enter returns the workout ID instead of starting it
 }
 
-func NewWorkoutSelectModel(datum time.Time) workoutSelect {
+func NewWorkoutSelectModel(store wodb.Store, settings coms.Settings, datum time.Time) workoutSelect {
 	workoutName := textinput.New()
 	workoutName.Placeholder = "a nice name"
 	workoutName.Width = 100
 
 	return workoutSelect{
+		store:       store,
+		settings:    settings,
 		workoutList: list.New(make([]list.Item, 0), coms.ListItemStyle(), 0, 0),
 		workoutName: workoutName,
 		datum:       datum,
 	}
 }
 
+// NewWorkoutPicker lets the user pick a workout whose ID is sent back to the
+// previous screen as coms.MsgWorkoutID.
+func NewWorkoutPicker(store wodb.Store, settings coms.Settings) workoutSelect {
+	m := NewWorkoutSelectModel(store, settings, time.Now())
+	m.pick = true
+	return m
+}
+
 func (m workoutSelect) Init() tea.Cmd {
-	return tea.Batch(coms.ReloadWorkouts, textinput.Blink)
+	return tea.Batch(coms.ReloadWorkouts(m.store), textinput.Blink)
 }
 
 func (m workoutSelect) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -57,14 +71,14 @@ func (m workoutSelect) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.workoutList.SetHeight(coms.GetContentHeight(msg.Height) - 3)
 
 	case coms.MsgWorkoutAddEdit:
-		return m, coms.ReloadWorkouts
+		return m, coms.ReloadWorkouts(m.store)
 
 	case tea.KeyMsg:
 		if m.workoutName.Focused() {
 			switch msg.String() {
 			case "enter":
 				m.workoutName.Blur()
-				cmd = coms.NewWorkout(m.workoutName.Value())
+				cmd = coms.NewWorkout(m.store, m.workoutName.Value())
 				m.workoutName.SetValue("")
 				return m, cmd
 
@@ -85,10 +99,16 @@ func (m workoutSelect) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		switch msg.String() {
 		case "enter":
-			return m, coms.GoTo(NewWorkoutModel(m.workoutList.SelectedItem().(coms.WorkoutItem).ID, m.datum))
+			if m.pick {
+				if wi, ok := m.workoutList.SelectedItem().(coms.WorkoutItem); ok {
+					return m, coms.Ret(coms.SendWorkoutID(wi.ID))
+				}
+				return m, cmd
+			}
+			return m, coms.GoTo(NewWorkoutModel(m.store, m.settings, m.workoutList.SelectedItem().(coms.WorkoutItem).ID, m.datum))
 
 		case "f2":
-			return m, coms.GoTo(NewWorkoutModelEDIT(m.workoutList.SelectedItem().(coms.WorkoutItem).ID, m.datum))
+			return m, coms.GoTo(NewWorkoutModelEDIT(m.store, m.settings, m.workoutList.SelectedItem().(coms.WorkoutItem).ID, m.datum))
 
 		case "esc":
 			return m, coms.Back
@@ -102,7 +122,7 @@ func (m workoutSelect) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if !ok {
 				return m, cmd
 			}
-			return m, coms.RemoveWorkout(wi.ID)
+			return m, coms.RemoveWorkout(m.store, wi.ID)
 
 		default:
 
@@ -123,7 +143,7 @@ func (m workoutSelect) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	newIndex := m.workoutList.GlobalIndex()
 
 	if newIndex != oldIndex {
-		cmds = append(cmds, coms.WorkoutToMarkdown(*m.workoutList.SelectedItem().(coms.WorkoutItem).Workout, nil))
+		cmds = append(cmds, coms.WorkoutToMarkdown(m.settings, *m.workoutList.SelectedItem().(coms.WorkoutItem).Workout, nil))
 	}
 
 	return m, tea.Batch(cmds...)
@@ -149,7 +169,7 @@ func (m workoutSelect) View() string {
 func (m *workoutSelect) refreshWorkoutList(workouts []wodb.Workout) {
 	items := make([]list.Item, len(workouts))
 	for i := range workouts {
-		items[i] = coms.WorkoutItem{&workouts[i]}
+		items[i] = coms.WorkoutItem{Workout: &workouts[i]}
 	}
 	workoutsList := list.New(items, coms.ListItemStyle(), 0, 0)
 	workoutsList.Title = "Select a Workout"
@@ -174,6 +194,9 @@ func (m *workoutSelect) refreshWorkoutList(workouts []wodb.Workout) {
 }
 
 func (m workoutSelect) BreadCrumb() string {
+	if m.pick {
+		return "pick workout"
+	}
 	return "workouts"
 }
 